@@ -0,0 +1,77 @@
+package multistatus
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrAbandoned is the error of Workers failed by WithAbandonAfter
+var ErrAbandoned = errors.New("abandoned: worker never reported")
+
+// WithAbandonAfter guards against Workers that never call Done or Fail, which
+// would otherwise keep Print waiting forever. Once nothing has changed for d
+// while Workers are still pending, the pending Workers are failed with
+// ErrAbandoned and marked "abandoned", letting Print return. Paused Workers
+// are never abandoned.
+//
+// Workers started with Go, Command or Do report when their function returns,
+// so they are never abandoned, and while any of them is running or waiting
+// to start, nothing is: only Workers finished by hand are abandoned, once
+// they are all that's left.
+func WithAbandonAfter(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.abandonAfter = d
+	}
+}
+
+// watchAbandoned fails pending Workers once nothing has changed for the
+// abandon timeout and none of them has a function running or queued, until
+// stop is closed
+func (w *WorkerSet) watchAbandoned(stop chan struct{}) {
+	if w.abandonAfter <= 0 {
+		return
+	}
+	version := atomic.LoadInt64(&w.version)
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(w.refresh):
+		}
+		if v := atomic.LoadInt64(&w.version); v != version {
+			version, last = v, time.Now()
+			continue
+		}
+		if time.Since(last) < w.abandonAfter || w.Count(Pending)+w.Count(Stopping) == 0 {
+			continue
+		}
+		w.mu.Lock()
+		var pending []*Worker
+		live := false
+		for _, v := range w.members() {
+			if v.State.finished() {
+				continue
+			}
+			if v.fn != nil || v.executing {
+				live = true
+				break
+			}
+			if v.pausedAt.IsZero() {
+				pending = append(pending, v)
+			}
+		}
+		w.mu.Unlock()
+		if live || len(pending) == 0 {
+			// a function is yet to return, or only paused Workers are left
+			last = time.Now()
+			continue
+		}
+		for _, v := range pending {
+			v.SetStatus("abandoned")
+			v.FailWith(ErrAbandoned)
+		}
+		return
+	}
+}
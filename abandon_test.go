@@ -0,0 +1,44 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAbandonAfter(t *testing.T) {
+	ws := New(WithSilent(true), WithConcurrency(1), WithAbandonAfter(30*time.Millisecond), WithRefreshInterval(5*time.Millisecond))
+	slow := func(ctx context.Context) error {
+		time.Sleep(150 * time.Millisecond)
+		return nil
+	}
+	running := ws.Go("running", slow)
+	queued := ws.Go("queued", slow)
+	byHand := ws.Add("by hand")
+	var done *Worker
+	doReturned := make(chan struct{})
+	go func() {
+		defer close(doReturned)
+		done = ws.Add("do")
+		done.Do(func() error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		})
+	}()
+
+	start := time.Now()
+	ws.Print(context.Background())
+	<-doReturned
+	for _, w := range []*Worker{running, queued, done} {
+		if w.State != Completed {
+			t.Errorf("%s is %v, want %v: %v", w.Name, w.State, Completed, w.Err())
+		}
+	}
+	if !errors.Is(byHand.Err(), ErrAbandoned) {
+		t.Errorf("by hand failed with %v, want %v", byHand.Err(), ErrAbandoned)
+	}
+	if took := time.Since(start); took < 300*time.Millisecond {
+		t.Errorf("abandoned after %v, before the functions had returned", took)
+	}
+}
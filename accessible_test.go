@@ -0,0 +1,56 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessible(t *testing.T) {
+	var out syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithAccessible(true), WithRefreshInterval(time.Millisecond))
+	a, b := ws.Add("fetch"), ws.Add("build")
+	go func() {
+		a.Done()
+		time.Sleep(10 * time.Millisecond)
+		b.FailWith(errors.New("broke"))
+	}()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if strings.ContainsAny(got, "\x1b\r") {
+		t.Errorf("accessible output has escapes or carriage returns: %q", got)
+	}
+	for _, want := range []string{
+		"fetch completed. 1 of 2 finished, 1 remaining.",
+		"build failed. 2 of 2 finished, 0 remaining.",
+		"All 2 tasks finished: 1 completed, 1 failed.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output doesn't announce %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestAccessibleCanceled(t *testing.T) {
+	var out syncBuf
+	ws := New(WithOutput(&out), WithAccessible(true))
+	ws.Add("fetch").Done()
+	ws.Add("build")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ws.Print(ctx)
+	if got := out.String(); !strings.Contains(got, "fetch completed.") || !strings.HasSuffix(got, "Canceled.\n") {
+		t.Errorf("output is %q", got)
+	}
+}
+
+func TestAccessibleFromEnv(t *testing.T) {
+	t.Setenv("ACCESSIBLE", "1")
+	if ws := New(); !ws.accessible {
+		t.Error("ACCESSIBLE doesn't enable accessible mode")
+	}
+}
@@ -0,0 +1,44 @@
+package multistatus
+
+// SetAnimated controls whether the pending Worker is drawn with the spinner,
+// as it is by default. A Worker standing for a fact rather than running work
+// can be drawn with the still Pending marker instead, and doesn't keep the
+// frame redrawing on its own while the elapsed time isn't shown.
+func (w *Worker) SetAnimated(b bool) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.static = !b
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// SetShowElapsed controls whether the Worker's elapsed time is drawn on its
+// line when WithElapsed is given, as it is by default, and when it runs
+// over its expected duration
+func (w *Worker) SetShowElapsed(b bool) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.hideElapsed = !b
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// animating reports whether the WorkerSet changes over time on its own: a
+// Worker is pending or stopping, and drawn with the spinner or its elapsed
+// time
+func (w *WorkerSet) animating() bool {
+	if w.Count(Pending)+w.Count(Stopping) == 0 {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, worker := range w.members() {
+		if worker.State.finished() {
+			continue
+		}
+		if !worker.static || w.showElapsed && !worker.hideElapsed {
+			return true
+		}
+	}
+	return false
+}
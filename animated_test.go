@@ -0,0 +1,72 @@
+package multistatus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaticRows(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithColor(false), WithElapsed(true))
+	fact := ws.Add("fact")
+	fact.SetAnimated(false)
+	fact.SetShowElapsed(false)
+	still := ws.Add("still")
+	still.SetAnimated(false)
+	running := ws.Add("running")
+
+	first := ws.frameLines(true, false)
+	clock.Advance(3 * time.Second)
+	second := ws.frameLines(true, false)
+	if want := "  " + DefaultTheme.Pending + " fact"; first[0] != want || second[0] != want {
+		t.Errorf("got %q then %q, want %q both times", first[0], second[0], want)
+	}
+	if want := "  " + DefaultTheme.Pending + " still (3s)"; second[1] != want {
+		t.Errorf("got %q, want %q", second[1], want)
+	}
+	if first[2] == second[2] {
+		t.Errorf("the running row didn't change: %q", first[2])
+	}
+
+	if !ws.animating() {
+		t.Error("not animating with a running row")
+	}
+	running.Done()
+	if !ws.animating() {
+		t.Error("not animating with a static row showing its elapsed time")
+	}
+	still.Done()
+	if ws.animating() {
+		t.Error("animating with only a static row without its elapsed time")
+	}
+
+	fact.SetAnimated(true)
+	fact.SetShowElapsed(true)
+	if !ws.animating() {
+		t.Error("not animating once the row was animated again")
+	}
+}
+
+func TestStaticSnapshot(t *testing.T) {
+	ws := New(WithSilent(true))
+	fact := ws.Add("fact")
+	fact.SetAnimated(false)
+	ws.Add("timed").SetShowElapsed(false)
+	ws.Add("plain")
+	ws.AddInfo("note")
+
+	for _, v := range ws.Snapshot() {
+		var static, hidden bool
+		switch v.Name {
+		case "fact":
+			static = true
+		case "timed":
+			hidden = true
+		case "note":
+			static, hidden = true, true
+		}
+		if v.Static != static || v.HideElapsed != hidden {
+			t.Errorf("%s has Static %v and HideElapsed %v, want %v and %v", v.Name, v.Static, v.HideElapsed, static, hidden)
+		}
+	}
+}
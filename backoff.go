@@ -0,0 +1,68 @@
+package multistatus
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// WithBackoff sets how long to wait before retrying a Worker with
+// WithRetries, by calling fn with the number of the attempt that just
+// failed, counting from 1. DefaultBackoff is used by default. While it
+// waits the Worker is marked as backing off, with a countdown to its next
+// attempt in place of its status.
+func WithBackoff(fn func(attempt int) time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.backoff = fn
+	}
+}
+
+// DefaultBackoff waits exponentially longer after each failed attempt,
+// starting from 500ms and doubling up to 30s, with a random jitter of up to
+// half the wait taken off so that Workers failing together don't retry
+// together
+func DefaultBackoff(attempt int) time.Duration {
+	d := 30 * time.Second
+	if attempt < 7 {
+		d = 500 * time.Millisecond << uint(attempt-1)
+	}
+	return d - time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// backOff waits before retrying a Worker after its attempt-th attempt,
+// reporting whether to retry. It returns false straight away if Print is
+// canceled meanwhile.
+func (w *Worker) backOff(attempt int) bool {
+	ws := w.parent
+	fn := ws.backoff
+	if fn == nil {
+		fn = DefaultBackoff
+	}
+	d := fn(attempt)
+	if d <= 0 {
+		return ws.ctx.Err() == nil
+	}
+	ws.mu.Lock()
+	w.retryAt = ws.now().Add(d)
+	ws.mu.Unlock()
+	ws.touch()
+
+	ok := true
+	select {
+	case <-ws.after(d):
+	case <-ws.ctx.Done():
+		ok = false
+	}
+	ws.mu.Lock()
+	w.retryAt = time.Time{}
+	ws.mu.Unlock()
+	ws.touch()
+	return ok
+}
+
+// backoffText counts down to the next attempt of a Worker backing off
+func (w *WorkerSet) backoffText(v WorkerStatus) string {
+	left := span(w.now(), v.RetryAt)
+	left = (left + time.Second - 1).Truncate(time.Second)
+	return fmt.Sprintf(w.messages.RetryIn, w.formatDuration(left), len(v.Attempts)+1, w.retries+1)
+}
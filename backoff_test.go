@@ -0,0 +1,92 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoffCountdown(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithRetries(2),
+		WithBackoff(func(n int) time.Duration { return time.Duration(n) * 10 * time.Second }))
+	started := make(chan struct{}, 3)
+	worker := ws.Go("task", func(ctx context.Context) error {
+		started <- struct{}{}
+		return errors.New("broke")
+	})
+	line := func() string { return ws.frameLines(true, false)[0] }
+
+	for _, cycle := range []struct {
+		wait time.Duration
+		want []string
+	}{
+		{10 * time.Second, []string{"retrying in 10s (attempt 2/3)", "retrying in 6s (attempt 2/3)", "retrying in 1s (attempt 2/3)"}},
+		{20 * time.Second, []string{"retrying in 20s (attempt 3/3)", "retrying in 16s (attempt 3/3)", "retrying in 11s (attempt 3/3)"}},
+	} {
+		<-started
+		if !waitFor(func() bool { return clock.Waiters() == 1 }) {
+			t.Fatal("Worker didn't back off")
+		}
+		for i, want := range cycle.want {
+			if l := line(); !strings.Contains(l, want) || !strings.Contains(l, "◷") {
+				t.Errorf("line is %q, want %q", l, want)
+			}
+			if i == 0 {
+				clock.Advance(4 * time.Second)
+			} else {
+				clock.Advance(5 * time.Second)
+			}
+		}
+		clock.Advance(cycle.wait - 9*time.Second)
+	}
+	<-started
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatalf("Print returned %v", err)
+	}
+	if n := len(ws.Snapshot()[0].Attempts); worker.State != Failed || n != 2 {
+		t.Errorf("Worker is %v after %d retried attempts, want Failed after 2", worker.State, n)
+	}
+	if l := line(); strings.Contains(l, "retrying") {
+		t.Errorf("finished line still counts down: %q", l)
+	}
+}
+
+func TestBackoffCanceled(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithRetries(4),
+		WithBackoff(func(int) time.Duration { return time.Hour }))
+	worker := ws.Go("task", func(ctx context.Context) error { return errors.New("broke") })
+	if !waitFor(func() bool { return clock.Waiters() == 1 }) {
+		t.Fatal("Worker didn't back off")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		ws.Print(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelation didn't cut the backoff short")
+	}
+	if worker.State != Canceled {
+		t.Errorf("Worker is %v, want Canceled", worker.State)
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	for i := 1; i < 10; i++ {
+		max := 30 * time.Second
+		if i < 7 {
+			max = 500 * time.Millisecond << uint(i-1)
+		}
+		if d := DefaultBackoff(i); d < max/2 || d > max {
+			t.Errorf("DefaultBackoff(%d) is %v, want between %v and %v", i, d, max/2, max)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package multistatus
+
+import "context"
+
+// AddBatch adds a Worker for each name, as a call to Add for each would, but
+// taking the WorkerSet's lock once, which is much faster for thousands of
+// Workers. The Workers are returned in the order of names, with consecutive
+// IDs.
+func (w *WorkerSet) AddBatch(names []string) []*Worker {
+	return w.addAll(names, w.uniqueNames)
+}
+
+// GoBatch adds a Worker for each name as AddBatch does, and runs fn for each
+// as Go would, passing the Worker it runs for
+func (w *WorkerSet) GoBatch(names []string, fn func(ctx context.Context, worker *Worker) error) []*Worker {
+	workers := w.AddBatch(names)
+	w.mu.Lock()
+	for _, worker := range workers {
+		worker := worker
+		w.enqueue(worker, func(ctx context.Context) error {
+			return fn(ctx, worker)
+		})
+	}
+	w.mu.Unlock()
+	w.dispatch()
+	return workers
+}
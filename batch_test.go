@@ -0,0 +1,100 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// batchNames returns n worker names
+func batchNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("task %d", i)
+	}
+	return names
+}
+
+func TestAddBatch(t *testing.T) {
+	ws := New(WithOutput(&syncBuf{}), WithColor(false))
+	ws.Add("first")
+	workers := ws.AddBatch(batchNames(100))
+	for i, w := range workers {
+		if w.ID() != int64(i+1) || w.Name != fmt.Sprintf("task %d", i) {
+			t.Fatalf("worker %d is #%d %q", i, w.ID(), w.Name)
+		}
+	}
+	if n := ws.CountFast(Pending); n != 101 {
+		t.Errorf("%d pending, want 101", n)
+	}
+	if len(ws.AddBatch(nil)) != 0 {
+		t.Error("workers added for no names")
+	}
+
+	// drawn as if added one at a time
+	looped := New(WithColor(false))
+	looped.Add("first")
+	for _, name := range batchNames(100) {
+		looped.Add(name)
+	}
+	workers[5].Done()
+	looped.Workers[6].Done()
+	_, got := ws.frame(false, false)
+	_, want := looped.frame(false, false)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batch frame differs from looped Adds:\n%q\n%q", got, want)
+	}
+
+	for _, w := range workers {
+		w.Done()
+	}
+	ws.Workers[0].Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoBatch(t *testing.T) {
+	ws := New(WithSilent(true), WithConcurrency(4))
+	var running, most, ran atomic.Int32
+	workers := ws.GoBatch(batchNames(50), func(ctx context.Context, w *Worker) error {
+		n := running.Add(1)
+		for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+		}
+		defer running.Add(-1)
+		ran.Add(1)
+		if w.ID()%10 == 0 {
+			return fmt.Errorf("%s broke", w.Name)
+		}
+		return nil
+	})
+	ws.Print(context.Background())
+	if ran.Load() != 50 || most.Load() > 4 {
+		t.Errorf("%d ran, %d at once, want 50 and at most 4", ran.Load(), most.Load())
+	}
+	if err := workers[10].Err(); err == nil || err.Error() != "task 10 broke" {
+		t.Errorf("worker 10 failed with %v", err)
+	}
+	if sum := ws.Summary(); sum.Completed != 45 || sum.Failed != 5 {
+		t.Errorf("%d completed and %d failed, want 45 and 5", sum.Completed, sum.Failed)
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	names := batchNames(50000)
+	for i := 0; i < b.N; i++ {
+		ws := New(WithSilent(true))
+		for _, name := range names {
+			ws.Add(name)
+		}
+	}
+}
+
+func BenchmarkAddBatch(b *testing.B) {
+	names := batchNames(50000)
+	for i := 0; i < b.N; i++ {
+		New(WithSilent(true)).AddBatch(names)
+	}
+}
@@ -0,0 +1,47 @@
+package multistatus
+
+import (
+	"context"
+	"sync"
+)
+
+// Begin returns a new WorkerSet configured by opts that is already printing
+// until ctx is canceled, along with a function that finishes it, shaped for
+// the top of a CLI's main function:
+//
+//	ws, finish := multistatus.Begin(ctx)
+//	defer finish()
+//
+// Workers may be added at any time until finish is called, which waits for
+// them to finish, prints the final frame, and returns a Summary along with
+// the error returned by Print. Print keeps running while there are no Workers
+// until finish is called. finish may be called more than once, returning the
+// same results each time.
+func Begin(ctx context.Context, opts ...Option) (*WorkerSet, func() (Summary, error)) {
+	ws := New(opts...)
+	ws.unfinished++
+	var held sync.Once
+	ws.unhold = func() {
+		held.Do(func() {
+			ws.mu.Lock()
+			ws.unfinished--
+			ws.settle()
+			ws.mu.Unlock()
+		})
+	}
+
+	printed := make(chan error, 1)
+	go func() { printed <- ws.Print(ctx) }()
+
+	var once sync.Once
+	var sum Summary
+	var err error
+	return ws, func() (Summary, error) {
+		once.Do(func() {
+			ws.unhold()
+			err = <-printed
+			sum = ws.Summary()
+		})
+		return sum, err
+	}
+}
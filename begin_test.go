@@ -0,0 +1,93 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBeginParks(t *testing.T) {
+	clock := newFakeClock()
+	ws, finish := Begin(context.Background(), WithOutput(&syncBuf{}), WithTTY(true),
+		WithSharedDisplay(false), WithClock(clock), WithRenderStats(true),
+		WithRefreshInterval(100*time.Millisecond))
+	frames := func() int64 { return ws.RenderStats().Frames }
+	drawn := func(n int64) {
+		t.Helper()
+		if !waitFor(func() bool { return frames() == n }) {
+			t.Fatalf("%d frames drawn, want %d", frames(), n)
+		}
+	}
+	drawn(1)
+
+	// the finished Worker is drawn a refresh interval after the first frame,
+	// after which nothing is left to animate and the clock isn't waited on
+	ws.Add("first").Done()
+	clock.Advance(100 * time.Millisecond)
+	drawn(2)
+	if next := ws.NextAnimationDeadline(); !next.IsZero() {
+		t.Errorf("next frame due at %v with nothing pending", next)
+	}
+	clock.Advance(time.Hour)
+	if n := clock.Waiters(); n != 0 || frames() != 2 {
+		t.Errorf("%d frames drawn and %d waits on the clock an hour on", frames()-2, n)
+	}
+
+	// a new Worker wakes the loop to draw it straight away and animate it
+	w := ws.Add("second")
+	drawn(3)
+	clock.Advance(100 * time.Millisecond)
+	drawn(4)
+	w.Done()
+	sum, err := finish()
+	if err != nil || sum.Completed != 2 {
+		t.Errorf("finish returned %+v, %v", sum, err)
+	}
+}
+
+func TestBeginEmpty(t *testing.T) {
+	ws, finish := Begin(context.Background(), WithSilent(true))
+	time.Sleep(10 * time.Millisecond)
+	ws.Add("late").Done()
+	sum, err := finish()
+	if err != nil || sum.Completed != 1 {
+		t.Errorf("finish returned %+v, %v", sum, err)
+	}
+	if again, err := finish(); err != nil || again.Completed != sum.Completed {
+		t.Errorf("second finish returned %+v, %v", again, err)
+	}
+}
+
+func TestBeginNoWorkers(t *testing.T) {
+	var buf syncBuf
+	_, finish := Begin(context.Background(), WithOutput(&buf), WithTTY(true))
+	sum, err := finish()
+	if err != nil || len(sum.Workers) != 0 {
+		t.Errorf("finish returned %+v, %v", sum, err)
+	}
+}
+
+func TestBeginCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ws, finish := Begin(ctx, WithSilent(true))
+	w := ws.Add("running")
+	// watching its Context, the Worker is canceled whether or not Print has
+	// begun by the time ctx is
+	wctx := w.Context()
+	go func() {
+		<-wctx.Done()
+		w.FailWith(wctx.Err())
+	}()
+	cancel()
+	sum, err := finish()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if sum.Canceled != 1 || w.Context().Err() == nil {
+		t.Errorf("finish returned %+v with the worker's context %v, want it canceled", sum, w.Context().Err())
+	}
+	if _, again := finish(); again != err {
+		t.Errorf("second finish returned %v, want %v", again, err)
+	}
+}
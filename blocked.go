@@ -0,0 +1,19 @@
+package multistatus
+
+// SetBlocked marks the pending Worker as held up by something else, such as
+// another Worker it depends on, rather than running. Until SetBlocked(false)
+// it is drawn with the Theme's Blocked marker in place of the spinner.
+func (w *Worker) SetBlocked(b bool) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.blocked = b
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// Running reports whether v is pending and not held up: not waiting for its
+// turn to start, marked with SetBlocked, paused or backing off between
+// retries. Only running Workers are drawn with the spinner.
+func (v WorkerStatus) Running() bool {
+	return v.State == Pending && !v.Info && !v.Waiting && !v.Blocked && !v.Paused && v.RetryAt.IsZero()
+}
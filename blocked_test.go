@@ -0,0 +1,99 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// idleSet returns a WorkerSet with a Worker in each pending sub-state:
+// running, backing off, queued, blocked, paused, and one added by hand
+func idleSet(t *testing.T, opts ...Option) *WorkerSet {
+	clock := newFakeClock()
+	ws := New(append([]Option{WithClock(clock), WithColor(false), WithConcurrency(2), WithRetries(1),
+		WithBackoff(func(int) time.Duration { return 10 * time.Second })}, opts...)...)
+	release := make(chan struct{})
+	t.Cleanup(func() {
+		close(release)
+		ws.Close()
+	})
+	ws.Go("running", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	ws.Go("retrying", func(ctx context.Context) error { return errors.New("broke") })
+	ws.Go("queued", func(ctx context.Context) error { return nil })
+	ws.Add("blocked").SetBlocked(true)
+	ws.Add("paused").Pause()
+	ws.Add("by hand")
+	if !waitFor(func() bool { return clock.Waiters() == 1 }) {
+		t.Fatal("retrying worker didn't back off")
+	}
+	return ws
+}
+
+func TestIdleMarkersStatic(t *testing.T) {
+	ws := idleSet(t)
+	_, first := ws.frame(true, false)
+	_, second := ws.frame(true, false)
+	for i := range first {
+		name := ws.Snapshot()[i].Name
+		running := name == "running" || name == "by hand"
+		if changed := first[i] != second[i]; changed != running {
+			t.Errorf("%s: changed %v between frames: %q then %q", name, changed, first[i], second[i])
+		}
+	}
+	want := map[string]string{"queued": "  · queued waiting", "blocked": "  ⧗ blocked"}
+	for i, v := range ws.Snapshot() {
+		if w, ok := want[v.Name]; ok && first[i] != w {
+			t.Errorf("got %q, want %q", first[i], w)
+		}
+	}
+}
+
+func TestIdleSubStates(t *testing.T) {
+	ws := idleSet(t)
+	for _, v := range ws.Snapshot() {
+		var sub bool
+		switch v.Name {
+		case "retrying":
+			sub = !v.RetryAt.IsZero()
+		case "queued":
+			sub = v.Waiting
+		case "blocked":
+			sub = v.Blocked
+		case "paused":
+			sub = v.Paused
+		default:
+			sub = v.Running()
+		}
+		if !sub || (v.Running() != (v.Name == "running" || v.Name == "by hand")) {
+			t.Errorf("%s: snapshot %+v doesn't tell its sub-state", v.Name, v)
+		}
+	}
+}
+
+func TestIdleMarkersTheme(t *testing.T) {
+	theme := DefaultTheme
+	theme.Queued, theme.Blocked, theme.BlockedLabel = "q", "", "[WAIT]"
+	ws := idleSet(t, WithTheme(theme))
+	_, lines := ws.frame(true, false)
+	_, plain := ws.frame(false, false)
+	for i, v := range ws.Snapshot() {
+		switch v.Name {
+		case "queued":
+			if lines[i] != "  q queued waiting" {
+				t.Errorf("got %q", lines[i])
+			}
+		case "blocked":
+			// without a glyph, the pending marker without the spinner
+			if want := "  " + DefaultTheme.Pending + " blocked"; lines[i] != want {
+				t.Errorf("got %q, want %q", lines[i], want)
+			}
+			if plain[i] != "  [WAIT] blocked" {
+				t.Errorf("got %q in the word style", plain[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package multistatus
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// maxOutputLines bounds the number of lines of output kept per Worker, and
+// maxLineBytes the length of each line; longer lines are broken
+const (
+	maxOutputLines = 100
+	maxLineBytes   = 4096
+)
+
+// Default limits on the bytes of captured output kept, unless
+// WithOutputLimits is given
+const (
+	defaultWorkerOutputLimit = 64 << 10
+	defaultSetOutputLimit    = 16 << 20
+)
+
+// WithOutputLimits bounds the bytes of captured output kept for each Worker,
+// 64KiB by default, and for all the Workers of the WorkerSet together, 16MiB
+// by default, so that week-long runs don't grow without bound. Each Worker
+// also keeps at most its last 100 lines, and breaks lines longer than 4KiB.
+// The oldest lines are dropped first: once the WorkerSet's limit is reached,
+// Workers writing more keep only their most recent lines. Output notes how
+// many lines were dropped. A limit of 0 leaves that bound off.
+func WithOutputLimits(worker, set int) Option {
+	return func(w *WorkerSet) {
+		w.outputLimit = worker
+		w.setOutputLimit = set
+	}
+}
+
+// output keeps the most recent lines written to it, within the limits of
+// the WorkerSet it belongs to, if any
+type output struct {
+	mu      sync.Mutex
+	lines   []string
+	partial []byte
+	set     *WorkerSet
+
+	// size is the length of the lines kept, and dropped the number of
+	// lines dropped to stay within the limits
+	size    int
+	dropped int
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.write(p)
+	return len(p), nil
+}
+
+// write keeps the lines written, returning those p completed
+func (o *output) write(p []byte) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.partial = append(o.partial, p...)
+	var done []string
+	for {
+		var l []byte
+		i := bytes.IndexByte(o.partial, '\n')
+		switch {
+		case i >= 0 && i <= maxLineBytes:
+			l, o.partial = o.partial[:i], o.partial[i+1:]
+		case len(o.partial) > maxLineBytes:
+			l, o.partial = o.partial[:maxLineBytes], o.partial[maxLineBytes:]
+		}
+		if l == nil {
+			break
+		}
+		s := string(bytes.TrimSuffix(l, []byte("\r")))
+		done = append(done, s)
+		o.keep(s)
+	}
+	if len(done) > 0 {
+		// copy what's left rather than holding on to everything written
+		// for the sake of the final line
+		o.partial = append([]byte(nil), o.partial...)
+	}
+	return done
+}
+
+// keep appends a line, then drops the oldest lines until those kept are
+// within the limits. The caller must hold the lock.
+func (o *output) keep(l string) {
+	o.lines = append(o.lines, l)
+	o.grow(len(l))
+	for len(o.lines) > 1 && (len(o.lines) > maxOutputLines || o.overLimit()) {
+		o.grow(-len(o.lines[0]))
+		o.lines[0] = ""
+		o.lines = o.lines[1:]
+		o.dropped++
+	}
+}
+
+// grow adds n to the size of the lines kept, and to the WorkerSet's total
+func (o *output) grow(n int) {
+	o.size += n
+	if o.set != nil {
+		atomic.AddInt64(&o.set.outputBytes, int64(n))
+	}
+}
+
+// overLimit reports whether the lines kept are over the Worker's or the
+// WorkerSet's limit
+func (o *output) overLimit() bool {
+	ws := o.set
+	if ws == nil {
+		return false
+	}
+	return ws.outputLimit > 0 && o.size > ws.outputLimit ||
+		ws.setOutputLimit > 0 && atomic.LoadInt64(&ws.outputBytes) > int64(ws.setOutputLimit)
+}
+
+// detach stops counting the lines kept toward the WorkerSet's limit, as
+// when the Worker is removed
+func (o *output) detach() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.set != nil {
+		atomic.AddInt64(&o.set.outputBytes, -int64(o.size))
+		o.set = nil
+	}
+}
+
+// truncated returns the number of lines dropped to stay within the limits
+func (o *output) truncated() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.dropped
+}
+
+// unterminated returns the final line not yet terminated by a newline
+func (o *output) unterminated() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return string(o.partial)
+}
+
+// tail returns up to n of the most recent lines, including any final line
+// not yet terminated by a newline
+func (o *output) tail(n int) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	lines := append([]string(nil), o.lines...)
+	if len(o.partial) > 0 {
+		lines = append(lines, string(o.partial))
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// WithCapturedOutputTee also writes every line captured by a Worker to out as
+// it is completed, prefixed with the Worker's name as "[name] ", such as to
+// ship it to a central log. Each line is written with a single Write, so
+// lines from different Workers never interleave. A final line without a
+// newline is written when the Worker finishes.
+func WithCapturedOutputTee(out io.Writer) Option {
+	return func(w *WorkerSet) {
+		w.tee = out
+	}
+}
+
+// WithCapturedOutputPrefix formats the prefix of each line written with
+// WithCapturedOutputTee from the Worker's name, in place of "[name] "
+func WithCapturedOutputPrefix(fn func(name string) string) Option {
+	return func(w *WorkerSet) {
+		w.teePrefix = fn
+	}
+}
+
+// Write captures output produced on behalf of the Worker, such as a
+// subprocess's stdout, keeping the most recent lines. It implements io.Writer.
+func (w *Worker) Write(p []byte) (int, error) {
+	lines := w.out.write(p)
+	if w.parent.tee != nil {
+		for _, l := range lines {
+			w.teeLine(l)
+		}
+	}
+	return len(p), nil
+}
+
+// teeLine writes a line captured by the Worker to the WorkerSet's tee
+func (w *Worker) teeLine(l string) {
+	ws := w.parent
+	name := stripControl(w.Name)
+	prefix := "[" + name + "] "
+	if ws.teePrefix != nil {
+		prefix = ws.teePrefix(name)
+	}
+	ws.teeMu.Lock()
+	defer ws.teeMu.Unlock()
+	io.WriteString(ws.tee, prefix+l+"\n")
+}
+
+// flushTee writes the Worker's unterminated final line, if any, to the
+// WorkerSet's tee
+func (w *Worker) flushTee() {
+	if w.parent.tee == nil {
+		return
+	}
+	if l := w.out.unterminated(); l != "" {
+		w.teeLine(l)
+	}
+}
+
+// Output returns the most recent lines written to the Worker, after a line
+// noting how many earlier lines were dropped, if any were
+func (w *Worker) Output() []string {
+	lines := w.out.tail(maxOutputLines)
+	if n := w.out.truncated(); n > 0 {
+		m := &w.parent.messages
+		lines = append([]string{m.pluralf(n, m.Truncated, groupDigits(n))}, lines...)
+	}
+	return lines
+}
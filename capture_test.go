@@ -0,0 +1,88 @@
+package multistatus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeLog records each Write as a separate string
+type writeLog struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (l *writeLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writes = append(l.writes, string(p))
+	return len(p), nil
+}
+
+func TestCapturedOutputTee(t *testing.T) {
+	var log writeLog
+	ws := New(WithSilent(true), WithCapturedOutputTee(&log))
+	w := ws.Add("build")
+	// a line split across writes, two in one, and one left unterminated
+	for _, chunk := range []string{"comp", "iling\nlink", "ing\ndone\n", "no newline"} {
+		w.Write([]byte(chunk))
+	}
+	w.Done()
+	want := []string{"[build] compiling\n", "[build] linking\n", "[build] done\n", "[build] no newline\n"}
+	if fmt.Sprint(log.writes) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", log.writes, want)
+	}
+}
+
+func TestCapturedOutputPrefix(t *testing.T) {
+	var log writeLog
+	ws := New(WithSilent(true), WithCapturedOutputTee(&log),
+		WithCapturedOutputPrefix(func(name string) string { return name + ": " }))
+	w := ws.Add("bad\x1bname")
+	w.Write([]byte("line\n"))
+	if want := []string{"badname: line\n"}; fmt.Sprint(log.writes) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", log.writes, want)
+	}
+}
+
+func TestCapturedOutputTeeConcurrent(t *testing.T) {
+	const workers, lines = 50, 100
+	var log writeLog
+	ws := New(WithSilent(true), WithCapturedOutputTee(&log))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		w := ws.Add(fmt.Sprintf("w%d", i))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				// each line in two writes, the second carrying the newline
+				line := fmt.Sprintf("%s line %d", w.Name, j)
+				w.Write([]byte(line[:3]))
+				w.Write([]byte(line[3:] + "\n"))
+			}
+			w.Done()
+		}()
+	}
+	wg.Wait()
+
+	if len(log.writes) != workers*lines {
+		t.Fatalf("%d writes, want %d", len(log.writes), workers*lines)
+	}
+	next := map[string]int{}
+	for _, l := range log.writes {
+		var name, body string
+		var n int
+		if !strings.HasSuffix(l, "\n") || strings.Count(l, "\n") != 1 || strings.Count(l, "[") != 1 {
+			t.Fatalf("torn line %q", l)
+		}
+		if _, err := fmt.Sscanf(l, "[%s %s line %d\n", &name, &body, &n); err != nil || name != body+"]" {
+			t.Fatalf("malformed line %q: %v", l, err)
+		}
+		if n != next[body] {
+			t.Fatalf("%s: line %d, want %d", body, n, next[body])
+		}
+		next[body]++
+	}
+}
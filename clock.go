@@ -0,0 +1,59 @@
+package multistatus
+
+import "time"
+
+// A Clock tells the time and waits for it to pass. The WorkerSet uses the
+// real clock unless WithClock gives another, such as a fake one letting
+// tests move time forwards, or back, on their own terms.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// After returns a channel receiving the time once d has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// WithClock has the WorkerSet take its readings from c: when Workers are
+// added, start, pause, finish and fail attempts, the durations worked out
+// from them, waits between retries and their countdowns, the spacing of
+// starts with WithStartRate, the spinner's deadline, the time taken to lay
+// out and write frames, the display's pacing between them and the
+// recording's timestamps.
+func WithClock(c Clock) Option {
+	return func(w *WorkerSet) {
+		w.clock = c
+	}
+}
+
+// realClock is the Clock used unless WithClock gives another
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// now returns the current time by the WorkerSet's clock
+func (w *WorkerSet) now() time.Time {
+	if w.clock == nil {
+		return time.Now()
+	}
+	return w.clock.Now()
+}
+
+// after returns a channel receiving the time once d has passed by the
+// WorkerSet's clock
+func (w *WorkerSet) after(d time.Duration) <-chan time.Time {
+	if w.clock == nil {
+		return time.After(d)
+	}
+	return w.clock.After(d)
+}
+
+// span returns the time from start to end, or 0 if the clock was set back
+// in between so end comes first
+func span(start, end time.Time) time.Duration {
+	if d := end.Sub(start); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,96 @@
+package multistatus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock moved only by Advance and Set
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{c.now.Add(d), ch})
+	return ch
+}
+
+// Advance moves the clock on by d, firing the waits it passes
+func (c *fakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, which may be in the past
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+	kept := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(t) {
+			kept = append(kept, w)
+			continue
+		}
+		w.ch <- t
+	}
+	c.waiters = kept
+}
+
+// Waiters returns the number of waits not yet fired
+func (c *fakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// waitFor polls until cond holds or a second passes, reporting which
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+func TestWithClock(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock))
+	worker := ws.Add("task")
+	clock.Advance(3 * time.Second)
+	worker.Done()
+	clock.Advance(time.Hour)
+	if got := ws.Snapshot()[0].Elapsed; got != 3*time.Second {
+		t.Errorf("Elapsed is %v, want 3s", got)
+	}
+	if got := ws.Duration(); got != 3*time.Second {
+		t.Errorf("Duration is %v, want 3s", got)
+	}
+}
@@ -0,0 +1,84 @@
+package multistatus
+
+import "errors"
+
+// ErrClosed is returned by methods of a WorkerSet after Close, and is the
+// error of Workers failed by Close or added after it
+var ErrClosed = errors.New("multistatus: WorkerSet is closed")
+
+// Close tears the WorkerSet down for good, for libraries and tests that
+// mustn't leave anything running behind them. It cancels Print, as canceling
+// its context would, and waits for it to return, then fails the Workers
+// still pending with ErrClosed and waits for the WorkerSet's background
+// goroutines to exit. Once Close returns nothing more is written to the
+// output and no more Events are delivered.
+//
+// After Close, Print, Configure and Remove return ErrClosed, and Workers
+// added are failed with ErrClosed straight away. Close may be called more
+// than once; later calls do nothing. Unless Print has already done so, it
+// completes the status file, recording and run log, and returns an error if
+// any of them couldn't be written.
+func (w *WorkerSet) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	printed := w.printed
+	w.mu.Unlock()
+
+	close(w.closing)
+	w.cancel()
+	if printed != nil {
+		<-printed
+	}
+
+	w.mu.Lock()
+	allDone := w.allDone
+	var pending []*Worker
+	for _, worker := range w.members() {
+		if !worker.State.finished() {
+			pending = append(pending, worker)
+		}
+	}
+	if w.drainTimer != nil && w.drainTimer.Stop() {
+		w.drainTimer = nil
+		w.bg.Done()
+	}
+	w.mu.Unlock()
+	for _, worker := range pending {
+		worker.FailWith(worker.finalizeIdle(ErrClosed))
+	}
+	w.dispatch()
+	if w.unhold != nil {
+		w.unhold()
+	}
+	if allDone != nil {
+		<-allDone
+	}
+
+	w.mu.Lock()
+	w.finished = true
+	w.mu.Unlock()
+
+	w.events.close()
+	w.bg.Wait()
+	return w.teardown()
+}
+
+// settle closes allDone once no Workers are left to finish. The caller must
+// hold w.mu.
+func (w *WorkerSet) settle() {
+	if w.unfinished == 0 && w.allDone != nil {
+		close(w.allDone)
+		w.allDone = nil
+	}
+}
+
+// isClosed reports whether Close has been called
+func (w *WorkerSet) isClosed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
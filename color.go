@@ -0,0 +1,255 @@
+package multistatus
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorLevel is the range of colors a terminal can display
+type ColorLevel int
+
+// Available ColorLevels
+const (
+	// ColorNone disables colors and attributes entirely
+	ColorNone ColorLevel = iota
+	// Color16 uses the 8 basic colors and their bright variants
+	Color16
+	// Color256 uses the xterm 256 color palette
+	Color256
+	// ColorTrue uses 24-bit RGB colors
+	ColorTrue
+)
+
+// DetectColorLevel guesses the terminal's ColorLevel from the environment:
+// ColorTrue when COLORTERM is "truecolor" or "24bit", Color256 when TERM
+// contains "256color", and Color16 otherwise.
+func DetectColorLevel() ColorLevel {
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return ColorTrue
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Color256
+	}
+	return Color16
+}
+
+// WithColorLevel sets the range of colors used on a terminal, in place of
+// the one found by DetectColorLevel when the WorkerSet was created. Colors
+// in the Theme beyond the level are replaced by the closest available.
+func WithColorLevel(l ColorLevel) Option {
+	return func(w *WorkerSet) {
+		w.colorLevel = l
+	}
+}
+
+// level returns the ColorLevel to draw with
+func (w *WorkerSet) level(isTerm bool) ColorLevel {
+	if !isTerm || w.noColor {
+		return ColorNone
+	}
+	return w.colorLevel
+}
+
+type colorKind uint8
+
+const (
+	kindDefault colorKind = iota
+	kindBasic
+	kind256
+	kindRGB
+)
+
+// A Color is a foreground color along with text attributes. The zero Color
+// is the terminal's default foreground with no attributes.
+type Color struct {
+	kind    colorKind
+	n       uint8
+	r, g, b uint8
+	bold    bool
+	dim     bool
+}
+
+// The basic terminal colors, whose exact shades are chosen by the terminal
+var (
+	Black   = Color{kind: kindBasic, n: 0}
+	Red     = Color{kind: kindBasic, n: 1}
+	Green   = Color{kind: kindBasic, n: 2}
+	Yellow  = Color{kind: kindBasic, n: 3}
+	Blue    = Color{kind: kindBasic, n: 4}
+	Magenta = Color{kind: kindBasic, n: 5}
+	Cyan    = Color{kind: kindBasic, n: 6}
+	White   = Color{kind: kindBasic, n: 7}
+)
+
+// DefaultColor is the terminal's default foreground
+var DefaultColor = Color{}
+
+// RGB returns the 24-bit color with the given components
+func RGB(r, g, b uint8) Color {
+	return Color{kind: kindRGB, r: r, g: g, b: b}
+}
+
+// Palette returns the color at index n of the xterm 256 color palette
+func Palette(n uint8) Color {
+	if n < 16 {
+		return Color{kind: kindBasic, n: n}
+	}
+	return Color{kind: kind256, n: n}
+}
+
+// Bright returns the bright variant of a basic color
+func (c Color) Bright() Color {
+	if c.kind == kindBasic && c.n < 8 {
+		c.n += 8
+	}
+	return c
+}
+
+// Bold returns c drawn in bold
+func (c Color) Bold() Color {
+	c.bold = true
+	return c
+}
+
+// Dim returns c drawn dimmed
+func (c Color) Dim() Color {
+	c.dim = true
+	return c
+}
+
+// basicRGB approximates the basic colors as drawn by common terminals
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the component values of the 256 color palette's color cube
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// rgb returns c's components, approximating them for palette colors
+func (c Color) rgb() (r, g, b uint8) {
+	switch c.kind {
+	case kindRGB:
+		return c.r, c.g, c.b
+	case kindBasic:
+		v := basicRGB[c.n]
+		return v[0], v[1], v[2]
+	case kind256:
+		if c.n >= 232 {
+			v := 8 + 10*(c.n-232)
+			return v, v, v
+		}
+		i := c.n - 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	}
+	return 0, 0, 0
+}
+
+// To16 returns the basic color closest to c
+func (c Color) To16() Color {
+	if c.kind != kind256 && c.kind != kindRGB {
+		return c
+	}
+	r, g, b := c.rgb()
+	best, bestDist := 0, -1
+	for i, v := range basicRGB {
+		if d := colorDist(r, g, b, v[0], v[1], v[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	c.kind, c.n = kindBasic, uint8(best)
+	return c
+}
+
+// To256 returns the color of the 256 color palette closest to c
+func (c Color) To256() Color {
+	if c.kind != kindRGB {
+		return c
+	}
+	cube := func(v uint8) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := cube(c.r), cube(c.g), cube(c.b)
+	n := 16 + 36*ri + 6*gi + bi
+	dist := colorDist(c.r, c.g, c.b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	avg := (int(c.r) + int(c.g) + int(c.b)) / 3
+	gi2 := (avg - 3) / 10
+	if gi2 < 0 {
+		gi2 = 0
+	} else if gi2 > 23 {
+		gi2 = 23
+	}
+	gv := uint8(8 + 10*gi2)
+	if d := colorDist(c.r, c.g, c.b, gv, gv, gv); d < dist {
+		n = 232 + gi2
+	}
+	c.kind, c.n = kind256, uint8(n)
+	return c
+}
+
+// ToTrue returns c as a 24-bit color. Basic colors are left alone, so they
+// keep the shades chosen by the terminal.
+func (c Color) ToTrue() Color {
+	if c.kind != kind256 {
+		return c
+	}
+	c.r, c.g, c.b = c.rgb()
+	c.kind = kindRGB
+	return c
+}
+
+// sgr returns the SGR parameters drawing c at the given level, or an empty
+// string for none
+func (c Color) sgr(level ColorLevel) string {
+	switch level {
+	case ColorNone:
+		return ""
+	case Color16:
+		c = c.To16()
+	case Color256:
+		c = c.To256()
+	}
+	var params []string
+	if c.bold {
+		params = append(params, "1")
+	}
+	if c.dim {
+		params = append(params, "2")
+	}
+	switch c.kind {
+	case kindBasic:
+		if c.n < 8 {
+			params = append(params, strconv.Itoa(30+int(c.n)))
+		} else {
+			params = append(params, strconv.Itoa(90+int(c.n)-8))
+		}
+	case kind256:
+		params = append(params, "38;5;"+strconv.Itoa(int(c.n)))
+	case kindRGB:
+		params = append(params, "38;2;"+strconv.Itoa(int(c.r))+";"+strconv.Itoa(int(c.g))+";"+strconv.Itoa(int(c.b)))
+	}
+	return strings.Join(params, ";")
+}
+
+func colorDist(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
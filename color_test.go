@@ -0,0 +1,99 @@
+package multistatus
+
+import (
+	"testing"
+)
+
+func TestColorSGR(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		c    Color
+		want [4]string // at ColorNone, Color16, Color256 and ColorTrue
+	}{
+		{"basic", Green, [4]string{"", "32", "32", "32"}},
+		{"bright", Red.Bright(), [4]string{"", "91", "91", "91"}},
+		{"rgb", RGB(255, 135, 0), [4]string{"", "33", "38;5;208", "38;2;255;135;0"}},
+		{"gray", RGB(30, 30, 30), [4]string{"", "30", "38;5;234", "38;2;30;30;30"}},
+		{"palette", Palette(208), [4]string{"", "33", "38;5;208", "38;5;208"}},
+		{"low palette", Palette(11), [4]string{"", "93", "93", "93"}},
+		{"dim", DefaultColor.Dim(), [4]string{"", "2", "2", "2"}},
+		{"bold", Cyan.Bold(), [4]string{"", "1;36", "1;36", "1;36"}},
+		{"default", DefaultColor, [4]string{"", "", "", ""}},
+	} {
+		for level, want := range tc.want {
+			if got := tc.c.sgr(ColorLevel(level)); got != want {
+				t.Errorf("%s at level %d: got %q, want %q", tc.name, level, got, want)
+			}
+		}
+	}
+}
+
+func TestColorConversions(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		got, want Color
+	}{
+		{"To256", RGB(255, 135, 0).To256(), Palette(208)},
+		{"To256 gray", RGB(30, 30, 30).To256(), Palette(234)},
+		{"To16", RGB(250, 5, 5).To16(), Red.Bright()},
+		{"To16 palette", Palette(208).To16(), Yellow},
+		{"To16 bold", RGB(255, 135, 0).Bold().To16(), Yellow.Bold()},
+		{"ToTrue", Palette(208).ToTrue(), RGB(255, 135, 0)},
+		{"ToTrue basic", Blue.ToTrue(), Blue},
+	} {
+		if got, want := tc.got.sgr(ColorTrue), tc.want.sgr(ColorTrue); got != want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, want)
+		}
+	}
+}
+
+func TestDetectColorLevel(t *testing.T) {
+	for _, tc := range []struct {
+		colorterm, term string
+		want            ColorLevel
+	}{
+		{"truecolor", "xterm-256color", ColorTrue},
+		{"24bit", "xterm", ColorTrue},
+		{"", "xterm-256color", Color256},
+		{"", "screen-256color", Color256},
+		{"", "xterm", Color16},
+		{"", "", Color16},
+	} {
+		t.Setenv("COLORTERM", tc.colorterm)
+		t.Setenv("TERM", tc.term)
+		if got := DetectColorLevel(); got != tc.want {
+			t.Errorf("COLORTERM=%q TERM=%q: got %d, want %d", tc.colorterm, tc.term, got, tc.want)
+		}
+	}
+}
+
+func TestThemeColorLevels(t *testing.T) {
+	clearEnv(t)
+	theme := DefaultTheme
+	theme.CompletedColor = RGB(255, 135, 0)
+	theme.FailedColor = Red.Bright().Bold()
+	for _, tc := range []struct {
+		opt               Option
+		completed, failed string
+	}{
+		{WithColorLevel(ColorTrue), "  \033[38;2;255;135;0m✔\033[0m done", "  \033[1;91m✗\033[0m bad"},
+		{WithColorLevel(Color256), "  \033[38;5;208m✔\033[0m done", "  \033[1;91m✗\033[0m bad"},
+		{WithColorLevel(Color16), "  \033[33m✔\033[0m done", "  \033[1;91m✗\033[0m bad"},
+		{WithColor(false), "  ✔ done", "  ✗ bad"},
+	} {
+		ws := New(WithTheme(theme), tc.opt)
+		ws.Add("done").Done()
+		ws.Add("bad").Fail()
+		_, lines := ws.frame(true, true)
+		if lines[0] != tc.completed || lines[1] != tc.failed {
+			t.Errorf("got %q, want %q", lines[:2], []string{tc.completed, tc.failed})
+		}
+	}
+
+	// and none at all when the output isn't a terminal
+	ws := New(WithTheme(theme), WithColorLevel(ColorTrue))
+	ws.Add("done").Done()
+	if _, lines := ws.frame(false, true); lines[0] != "  [ OK ] done" {
+		t.Errorf("got %q", lines[0])
+	}
+}
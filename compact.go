@@ -0,0 +1,54 @@
+package multistatus
+
+// defaultCompactBelow is the terminal height below which the block is drawn
+// as a single line unless WithCompactBelow is given
+const defaultCompactBelow = 4
+
+// WithCompactBelow draws the block as a single line counting finished
+// Workers and naming a running one while the terminal is fewer than n lines
+// high, 4 by default, as in IDE output panes and small tmux panes where the
+// full block can't fit. On a terminal a single line high the line is
+// redrawn in place without moving the cursor up or down. The final frame is
+// printed in full either way. A height of 0 never draws the block compactly.
+func WithCompactBelow(n int) Option {
+	return func(w *WorkerSet) {
+		w.compactBelow = n
+	}
+}
+
+// compact reports whether a terminal of the given height is too short for
+// the full block
+func (w *WorkerSet) compact(height int) bool {
+	return height > 0 && height < w.compactBelow
+}
+
+// inline reports whether the terminal is a single line high, so frames must
+// be redrawn in place on the cursor's line
+func (w *WorkerSet) inline() bool {
+	if w.compactBelow <= 1 {
+		return false
+	}
+	_, height := w.Size()
+	return height == 1
+}
+
+// compactLine summarizes snap on a single line for a short terminal
+func (w *WorkerSet) compactLine(snap []WorkerStatus, marker string, width int) string {
+	counts, total := w.tally(snap)
+	done := counts[Completed] + counts[Failed] + counts[Canceled]
+	m := &w.messages
+	l := "  " + marker + " " + m.pluralf(total, m.Compact, done, total)
+	if f := counts[Failed]; f > 0 {
+		l += m.pluralf(f, m.Failures, f)
+	}
+	for _, i := range w.displayOrder(snap) {
+		if v := snap[i]; !v.State.finished() {
+			l += " · " + stripControl(v.Name)
+			if v.Status != "" {
+				l += " " + stripControl(v.Status)
+			}
+			break
+		}
+	}
+	return truncate(l, widthOrMax(width))
+}
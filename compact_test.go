@@ -0,0 +1,81 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tenWorkers adds ten Workers to ws: three completed, one failed, one with a
+// status and five pending
+func tenWorkers(ws *WorkerSet) []*Worker {
+	workers := make([]*Worker, 10)
+	for i := range workers {
+		workers[i] = ws.Add(fmt.Sprintf("w%d", i))
+	}
+	for _, w := range workers[:3] {
+		w.Done()
+	}
+	workers[3].Fail()
+	workers[4].SetStatus("busy")
+	return workers
+}
+
+func TestCompactHeights(t *testing.T) {
+	compact := []string{"  ⠋ 4/10 tasks done (1 failure) · w4 busy"}
+	for _, tc := range []struct {
+		height int
+		opts   []Option
+		want   []string // nil for the full block
+	}{
+		{1, nil, compact},
+		{2, nil, compact},
+		{3, nil, compact},
+		{5, nil, nil},
+		{5, []Option{WithCompactBelow(6)}, compact},
+		{2, []Option{WithCompactBelow(0)}, nil},
+	} {
+		ws := New(append([]Option{WithColor(false), WithSize(80, tc.height)}, tc.opts...)...)
+		tenWorkers(ws)
+		_, lines := ws.frame(true, false)
+		if tc.want != nil && fmt.Sprint(lines) != fmt.Sprint(tc.want) {
+			t.Errorf("height %d: got %q, want %q", tc.height, lines, tc.want)
+		}
+		if tc.want == nil && strings.Contains(lines[0], "tasks done") {
+			t.Errorf("height %d: got %q, want the full block", tc.height, lines)
+		}
+		if ws.compact(tc.height) && len(ws.frameLines(true, true)) != 10 {
+			t.Errorf("height %d: final frame isn't in full", tc.height)
+		}
+	}
+}
+
+func TestCompactInline(t *testing.T) {
+	for _, height := range []int{1, 2} {
+		var out syncBuf
+		ws := New(WithOutput(&out), WithTTY(true), WithColor(false), WithSize(80, height),
+			WithRefreshInterval(time.Millisecond))
+		workers := tenWorkers(ws)
+		for i, w := range workers[4:] {
+			time.AfterFunc(time.Duration(i+1)*5*time.Millisecond, w.Done)
+		}
+		ws.Print(context.Background())
+
+		up := strings.Count(out.String(), csi+"A")
+		down := strings.Count(out.String(), csi+"B")
+		if height == 1 && up+down != 0 {
+			t.Errorf("height 1: cursor moved up %d and down %d times", up, down)
+		}
+		if height == 2 && up == 0 {
+			t.Error("height 2: compact line not redrawn by moving up")
+		}
+		vt := newVT(24)
+		vt.feed(out.String())
+		lines := vt.lines()
+		if len(lines) != 10 || lines[9] != "  ✔ w9" {
+			t.Errorf("height %d: final frame\n%s", height, strings.Join(lines, "\n"))
+		}
+	}
+}
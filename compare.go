@@ -0,0 +1,128 @@
+package multistatus
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// SlowdownThreshold is how many times longer a Worker must take than before
+// for CompareSummaries to report it as slower
+var SlowdownThreshold = 1.5
+
+// minSlowdown is how much longer a Worker must take than before to be
+// reported as slower, so that timing noise in quick Workers isn't
+const minSlowdown = 100 * time.Millisecond
+
+// SummaryDiff compares two runs, as returned by CompareSummaries. Each list
+// is in the order of the newer Summary, except Removed, which is in the order
+// of the older.
+type SummaryDiff struct {
+	// NewlyFailing holds Workers that failed after not failing before,
+	// and Fixed those that completed after failing before
+	NewlyFailing []WorkerStatus
+	Fixed        []WorkerStatus
+
+	// Slower holds Workers that completed both times but took at least
+	// SlowdownThreshold times as long
+	Slower []Slowdown
+
+	// Added holds Workers only in the newer Summary, and Removed those
+	// only in the older
+	Added   []WorkerStatus
+	Removed []WorkerStatus
+}
+
+// A Slowdown is a Worker that took longer than before
+type Slowdown struct {
+	Old, New WorkerStatus
+}
+
+// Ratio returns how many times as long the Worker took
+func (s Slowdown) Ratio() float64 {
+	return float64(s.New.Elapsed) / float64(s.Old.Elapsed)
+}
+
+// Empty reports whether the runs had no differences worth reporting
+func (d SummaryDiff) Empty() bool {
+	return len(d.NewlyFailing) == 0 && len(d.Fixed) == 0 && len(d.Slower) == 0 &&
+		len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// CompareSummaries compares two runs of the same work, such as last night's
+// and tonight's Summary reloaded from JSON. Workers are matched by name, with
+// repeated names told apart by suffixing them in order as WithUniqueNames
+// would, so that the second "deploy" of each run is matched with the other.
+func CompareSummaries(old, new Summary) SummaryDiff {
+	var d SummaryDiff
+	oldKeys, oldByKey := keyedWorkers(old)
+	newKeys, newByKey := keyedWorkers(new)
+	for i, k := range newKeys {
+		v := new.Workers[i]
+		o, ok := oldByKey[k]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, v)
+		case v.State == Failed && o.State != Failed:
+			d.NewlyFailing = append(d.NewlyFailing, v)
+		case v.State == Completed && o.State == Failed:
+			d.Fixed = append(d.Fixed, v)
+		case v.State == Completed && o.State == Completed && o.Elapsed > 0 &&
+			v.Elapsed-o.Elapsed >= minSlowdown &&
+			float64(v.Elapsed) >= float64(o.Elapsed)*SlowdownThreshold:
+			d.Slower = append(d.Slower, Slowdown{Old: o, New: v})
+		}
+	}
+	for i, k := range oldKeys {
+		if _, ok := newByKey[k]; !ok {
+			d.Removed = append(d.Removed, old.Workers[i])
+		}
+	}
+	return d
+}
+
+// keyedWorkers names each Worker in sum uniquely, returning the names in
+// order and the Worker with each
+func keyedWorkers(sum Summary) ([]string, map[string]WorkerStatus) {
+	names := make(nameTable)
+	keys := make([]string, len(sum.Workers))
+	byKey := make(map[string]WorkerStatus, len(sum.Workers))
+	for i, v := range sum.Workers {
+		keys[i] = names.take(v.Name, true)
+		byKey[keys[i]] = v
+	}
+	return keys, byKey
+}
+
+// WriteText writes the differences to out as a short report on one line,
+// using DefaultMessages, such as "2 newly failing: deploy-eu, deploy-ap;
+// 1 fixed: migrate-db; deploy-us 3.2x slower"
+func (d SummaryDiff) WriteText(out io.Writer) error {
+	m := &DefaultMessages
+	names := func(vs []WorkerStatus) string {
+		s := make([]string, len(vs))
+		for i, v := range vs {
+			s[i] = stripControl(v.Name)
+		}
+		return strings.Join(s, ", ")
+	}
+	var parts []string
+	add := func(forms []string, vs []WorkerStatus) {
+		if len(vs) > 0 {
+			parts = append(parts, m.pluralf(len(vs), forms, len(vs), names(vs)))
+		}
+	}
+	add(m.NewlyFailing, d.NewlyFailing)
+	add(m.Fixed, d.Fixed)
+	for _, s := range d.Slower {
+		parts = append(parts, fmt.Sprintf(m.Slower, stripControl(s.New.Name), s.Ratio()))
+	}
+	add(m.Added, d.Added)
+	add(m.Removed, d.Removed)
+	if len(parts) == 0 {
+		parts = append(parts, m.NoChanges)
+	}
+	_, err := io.WriteString(out, strings.Join(parts, "; ")+"\n")
+	return err
+}
@@ -0,0 +1,99 @@
+package multistatus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// runOf returns the Summary, after a JSON round trip, of a run of the named
+// Workers taking the given times, failing those with a true outcome
+func runOf(t *testing.T, names []string, took []time.Duration, failed []bool) Summary {
+	clock := newFakeClock()
+	start := clock.Now()
+	ws := New(WithSilent(true), WithClock(clock))
+	workers := make([]*Worker, len(names))
+	for i, name := range names {
+		workers[i] = ws.Add(name)
+	}
+	for i, w := range workers {
+		clock.Set(start.Add(took[i]))
+		if failed[i] {
+			w.FailWith(errors.New("broke"))
+		} else {
+			w.Done()
+		}
+	}
+	b, err := json.Marshal(ws.Summary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum Summary
+	if err := json.Unmarshal(b, &sum); err != nil {
+		t.Fatal(err)
+	}
+	return sum
+}
+
+func TestCompareSummaries(t *testing.T) {
+	s := time.Second
+	old := runOf(t,
+		[]string{"deploy-eu", "deploy-ap", "migrate-db", "deploy-us", "deploy", "deploy", "cleanup"},
+		[]time.Duration{10 * s, 10 * s, 5 * s, 10 * s, s, s, s},
+		[]bool{false, false, true, false, false, false, false})
+	new := runOf(t,
+		[]string{"deploy-eu", "deploy-ap", "migrate-db", "deploy-us", "deploy", "deploy", "smoke-test"},
+		[]time.Duration{10 * s, 10 * s, 5 * s, 32 * s, s, 2 * s, s},
+		[]bool{true, true, false, false, false, true, false})
+
+	d := CompareSummaries(old, new)
+	names := func(vs []WorkerStatus) []string {
+		var s []string
+		for _, v := range vs {
+			s = append(s, v.Name)
+		}
+		return s
+	}
+	for _, tc := range []struct {
+		what      string
+		got, want []string
+	}{
+		// the second "deploy" is matched with the other run's second
+		{"newly failing", names(d.NewlyFailing), []string{"deploy-eu", "deploy-ap", "deploy"}},
+		{"fixed", names(d.Fixed), []string{"migrate-db"}},
+		{"added", names(d.Added), []string{"smoke-test"}},
+		{"removed", names(d.Removed), []string{"cleanup"}},
+	} {
+		if fmt.Sprint(tc.got) != fmt.Sprint(tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.what, tc.got, tc.want)
+		}
+	}
+	if len(d.Slower) != 1 || d.Slower[0].New.Name != "deploy-us" || d.Slower[0].Ratio() != 3.2 {
+		t.Errorf("slower: got %+v", d.Slower)
+	}
+
+	var buf bytes.Buffer
+	if err := d.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "compare.golden", buf.Bytes())
+}
+
+func TestCompareSummariesSame(t *testing.T) {
+	s := time.Second
+	// quick Workers aren't slower for noise under minSlowdown
+	old := runOf(t, []string{"a", "b"}, []time.Duration{10 * time.Millisecond, 10 * s}, []bool{false, true})
+	new := runOf(t, []string{"a", "b"}, []time.Duration{50 * time.Millisecond, 10 * s}, []bool{false, true})
+	d := CompareSummaries(old, new)
+	if !d.Empty() {
+		t.Errorf("got differences %+v", d)
+	}
+	var buf bytes.Buffer
+	d.WriteText(&buf)
+	if buf.String() != DefaultMessages.NoChanges+"\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
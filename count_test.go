@@ -0,0 +1,91 @@
+package multistatus
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// snapshotCounts counts the Workers of a Snapshot in each state
+func snapshotCounts(ws *WorkerSet) (counts [numStates]int64) {
+	for _, v := range ws.Snapshot() {
+		counts[v.State]++
+	}
+	return counts
+}
+
+func TestCountFast(t *testing.T) {
+	ws := New(WithSilent(true))
+	a, b := ws.Add("a"), ws.Add("b")
+	ws.AddBatch([]string{"c", "d"})
+	a.Done()
+	b.Fail()
+	for _, tc := range []struct {
+		state WorkerState
+		want  int64
+	}{{Pending, 2}, {Completed, 1}, {Failed, 1}, {Canceled, 0}, {-1, 0}, {numStates, 0}} {
+		if got := ws.CountFast(tc.state); got != tc.want {
+			t.Errorf("%v: got %d, want %d", tc.state, got, tc.want)
+		}
+	}
+	if err := ws.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if n := ws.CountFast(Completed); n != 0 {
+		t.Errorf("%d completed after removing the only one", n)
+	}
+}
+
+func TestCountFastStress(t *testing.T) {
+	ws := New(WithSilent(true))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				w := ws.Add(fmt.Sprintf("w%d-%d", g, i))
+				switch i % 4 {
+				case 0:
+					w.Done()
+				case 1:
+					w.Fail()
+				case 2:
+					w.Done()
+					ws.Remove(w)
+				}
+				ws.CountFast(Pending)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var sum int64
+	for s := WorkerState(0); s < numStates; s++ {
+		sum += ws.CountFast(s)
+	}
+	if n := int64(len(ws.Snapshot())); sum != n || n != 8*150 {
+		t.Errorf("counts sum to %d for %d workers, want %d", sum, n, 8*150)
+	}
+	want := snapshotCounts(ws)
+	for s := WorkerState(0); s < numStates; s++ {
+		if got := ws.CountFast(s); got != want[s] {
+			t.Errorf("%v: got %d, want %d as in the snapshot", s, got, want[s])
+		}
+	}
+	if sum := ws.Summary(); int64(sum.Completed) != ws.CountFast(Completed) || int64(sum.Pending) != ws.CountFast(Pending) {
+		t.Errorf("summary %d completed and %d pending disagrees with the counts", sum.Completed, sum.Pending)
+	}
+}
+
+func BenchmarkCountFast(b *testing.B) {
+	ws := New(WithSilent(true))
+	for i := 0; i < 100; i++ {
+		ws.Add("w").Done()
+	}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ws.CountFast(Completed)
+		}
+	})
+}
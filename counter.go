@@ -0,0 +1,131 @@
+package multistatus
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// counterBarWidth is the width in columns of a Counter's progress bar
+const counterBarWidth = 12
+
+// A Counter tracks many small tasks as a single Worker, counting them rather
+// than tracking each one. Counting is a single atomic operation, so a
+// Counter can keep up with hundreds of thousands of tasks. Its line shows a
+// progress bar, the rate tasks are finishing at, and how many failed.
+type Counter struct {
+	w        *Worker
+	total    int64
+	done     int64
+	failed   int64
+	finished int32
+
+	// rates is nil unless WithRateSparkline is given, guarded by the
+	// WorkerSet's lock
+	rates *rateRing
+}
+
+// CounterStatus is a point-in-time copy of a Counter's counts
+type CounterStatus struct {
+	Done   int64 `json:"done"`
+	Failed int64 `json:"failed"`
+	Total  int64 `json:"total,omitempty"`
+
+	// Rates holds the number of tasks counted in each of the recent
+	// intervals recorded with WithRateSparkline, oldest first
+	Rates []int64 `json:"rates,omitempty"`
+}
+
+// AddCounter adds a Worker counting total tasks, or an unknown number if
+// total is 0. The Worker finishes once total tasks have been counted:
+// Completed if none failed and Failed otherwise. With no total, call Finish
+// once all of the tasks have been counted.
+func (w *WorkerSet) AddCounter(name string, total int) *Counter {
+	worker := w.Add(name)
+	c := &Counter{w: worker, total: int64(total)}
+	w.mu.Lock()
+	if w.rateBucket > 0 && w.rateBuckets > 0 {
+		c.rates = newRateRing(w.rateBucket, w.rateBuckets, w.now())
+	}
+	worker.counter = c
+	w.mu.Unlock()
+	atomic.AddInt32(&w.counters, 1)
+	return c
+}
+
+// Incr counts a task that succeeded
+func (c *Counter) Incr() {
+	n := atomic.AddInt64(&c.done, 1)
+	if c.total > 0 && n+atomic.LoadInt64(&c.failed) == c.total {
+		c.Finish()
+	}
+}
+
+// Fail counts a task that failed
+func (c *Counter) Fail() {
+	n := atomic.AddInt64(&c.failed, 1)
+	if c.total > 0 && n+atomic.LoadInt64(&c.done) == c.total {
+		c.Finish()
+	}
+}
+
+// Finish ends the count: the Worker is Completed if no task failed, and
+// Failed otherwise. Tasks counted afterwards are ignored.
+func (c *Counter) Finish() {
+	if !atomic.CompareAndSwapInt32(&c.finished, 0, 1) {
+		return
+	}
+	if failed := atomic.LoadInt64(&c.failed); failed > 0 {
+		n := failed + atomic.LoadInt64(&c.done)
+		m := &c.w.parent.messages
+		c.w.FailWith(fmt.Errorf("%s of %d", m.pluralf(int(failed), m.CounterFailures, failed), n))
+	} else {
+		c.w.Done()
+	}
+	atomic.AddInt32(&c.w.parent.counters, -1)
+}
+
+// Worker returns the Worker standing for the Counter
+func (c *Counter) Worker() *Worker {
+	return c.w
+}
+
+// status returns the Counter's counts as of now, recording its rate. The
+// caller must hold the WorkerSet's lock.
+func (c *Counter) status(now time.Time) *CounterStatus {
+	s := &CounterStatus{
+		Done:   atomic.LoadInt64(&c.done),
+		Failed: atomic.LoadInt64(&c.failed),
+		Total:  c.total,
+	}
+	if c.rates != nil {
+		c.rates.sample(now, s.Done+s.Failed)
+		s.Rates = c.rates.completed()
+	}
+	return s
+}
+
+// counterLine formats the progress of a Counter in place of its status
+func (w *WorkerSet) counterLine(v WorkerStatus) string {
+	c, m := v.Counter, &w.messages
+	n := c.Done + c.Failed
+	rate := 0.0
+	if secs := v.Elapsed.Seconds(); secs > 0 {
+		rate = float64(n) / secs
+	}
+	var s string
+	if c.Total > 0 {
+		bar := renderBar(counterBarWidth, []barSegment{
+			{count: int(c.Done), plain: "="},
+			{count: int(c.Failed), plain: "x"},
+			{count: int(c.Total - n), plain: " "},
+		}, ColorNone)
+		s = "[" + bar + "] " + fmt.Sprintf(m.Counter, n, c.Total, rate)
+	} else {
+		s = fmt.Sprintf(m.CounterNoTotal, n, rate)
+	}
+	if c.Failed > 0 {
+		s += " · " + m.pluralf(int(c.Failed), m.CounterFailures, c.Failed)
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package multistatus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCounter(t *testing.T) {
+	ws := New(WithSilent(true))
+	c := ws.AddCounter("records", 1000)
+	ws.Add("other").Done()
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if g == 0 && i < 3 {
+					c.Fail()
+				} else {
+					c.Incr()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	// counted after the total, so ignored
+	c.Incr()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s := ws.Summary()
+	if s.Completed != 1 || s.Failed != 1 || len(s.Workers) != 2 {
+		t.Errorf("Summary has %d completed and %d failed of %d", s.Completed, s.Failed, len(s.Workers))
+	}
+	if v := s.Workers[0]; v.Counter.Done != 998 || v.Counter.Failed != 3 || v.Err != "3 failed of 1000" {
+		t.Errorf("Counter has %+v, error %q", v.Counter, v.Err)
+	}
+}
+
+func TestCounterLine(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithClock(clock))
+	c := ws.AddCounter("records", 200)
+	open := ws.AddCounter("events", 0)
+	for i := 0; i < 100; i++ {
+		c.Incr()
+		open.Incr()
+	}
+	c.Fail()
+	clock.Advance(4 * time.Second)
+
+	lines := ws.frameLines(false, false)
+	if got, want := lines[0], "  [ .. ] records [======      ] 101/200 · 25.2/s · 1 failed"; got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if got, want := lines[1], "  [ .. ] events 100 · 25.0/s"; got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	open.Finish()
+	open.Finish()
+	if v := ws.Snapshot()[1]; v.State != Completed {
+		t.Errorf("finished Counter is %v", v.State)
+	}
+}
+
+func BenchmarkCounterIncr(b *testing.B) {
+	ws := New(WithSilent(true))
+	c := ws.AddCounter("tasks", 0)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Incr()
+		}
+	})
+}
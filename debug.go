@@ -0,0 +1,17 @@
+package multistatus
+
+import (
+	"bufio"
+	"io"
+)
+
+// WithDebugLog appends timestamped records about the package's own
+// decisions to out, such as whether the terminal answered WithProbeTerminal
+// and when its size couldn't be read, keeping them out of the run log, which
+// records only what the Workers did. The log is flushed when Print returns.
+func WithDebugLog(out io.Writer) Option {
+	return func(w *WorkerSet) {
+		w.debug = &runLog{w: bufio.NewWriter(out), now: w.now}
+		w.debug.setPrefix(w.name)
+	}
+}
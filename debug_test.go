@@ -0,0 +1,28 @@
+package multistatus
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDebugLogTakesTerminalDiagnostics(t *testing.T) {
+	var run, debug bytes.Buffer
+	ws := New(WithLogWriter(&run), WithDebugLog(&debug), WithName("deploy"))
+	ws.logSizeFallback(errors.New("no tty"))
+	ws.logSizeFallback(errors.New("no tty"))
+	if err := ws.teardown(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(run.String(), "terminal:") {
+		t.Errorf("run log has terminal diagnostics:\n%s", run.String())
+	}
+	got := debug.String()
+	if n := strings.Count(got, "terminal: size unavailable"); n != 1 {
+		t.Errorf("debug log has %d size records, want 1:\n%s", n, got)
+	}
+	if !strings.Contains(got, "[deploy] ") {
+		t.Errorf("debug log records aren't prefixed with the name:\n%s", got)
+	}
+}
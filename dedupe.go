@@ -0,0 +1,88 @@
+package multistatus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithErrorGrouping collapses the errors of failed Workers in the final
+// report once at least n of them share the same error: the error is listed
+// once, after the Workers, along with how many failed with it and their
+// names. Each Worker's own error is still kept in snapshots and JSON. The
+// default is 3; 0 never groups errors.
+func WithErrorGrouping(n int) Option {
+	return func(w *WorkerSet) {
+		w.groupErrors = n
+	}
+}
+
+// WithErrorNormalizer sets a function reducing errors to what they have in
+// common for WithErrorGrouping, such as by removing addresses or request
+// IDs, so that similar errors are grouped together
+func WithErrorNormalizer(fn func(string) string) Option {
+	return func(w *WorkerSet) {
+		w.normalizeErr = fn
+	}
+}
+
+// errorGroup is a set of Workers that failed with the same error
+type errorGroup struct {
+	workers []WorkerStatus
+}
+
+// errorGroups finds the errors shared by enough failed Workers in rows to
+// be grouped, in the order they first appear, and the IDs of those Workers
+func (w *WorkerSet) errorGroups(rows []WorkerStatus) (groups []*errorGroup, grouped map[int64]bool) {
+	if w.groupErrors <= 0 {
+		return nil, nil
+	}
+	byErr := make(map[string]*errorGroup)
+	var order []*errorGroup
+	for _, v := range rows {
+		if v.State != Failed || v.Err == "" {
+			continue
+		}
+		key := v.Err
+		if w.normalizeErr != nil {
+			key = w.normalizeErr(key)
+		}
+		g, ok := byErr[key]
+		if !ok {
+			g = &errorGroup{}
+			byErr[key] = g
+			order = append(order, g)
+		}
+		g.workers = append(g.workers, v)
+	}
+	for _, g := range order {
+		if len(g.workers) < w.groupErrors {
+			continue
+		}
+		groups = append(groups, g)
+		if grouped == nil {
+			grouped = make(map[int64]bool)
+		}
+		for _, v := range g.workers {
+			grouped[v.ID] = true
+		}
+	}
+	return groups, grouped
+}
+
+// errorGroupLines formats a group of Workers that failed with the same
+// error: a line naming them, followed by the error of the first
+func (w *WorkerSet) errorGroupLines(g *errorGroup, marker string, width int) []string {
+	names := make([]string, len(g.workers))
+	for i, v := range g.workers {
+		names[i] = stripControl(v.Name)
+	}
+	list := strings.Join(names, ", ")
+	if len(names) > 4 {
+		list = names[0] + "…" + names[len(names)-1]
+	}
+	m := &w.messages
+	n := len(g.workers)
+	line := fmt.Sprintf("  %s %s", marker, m.pluralf(n, m.ErrorGroup, n, list))
+	lines := []string{truncate(line, widthOrMax(width))}
+	return append(lines, w.errorLines(g.workers[0], marker, width)...)
+}
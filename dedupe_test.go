@@ -0,0 +1,90 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// failNodes adds n Workers named node-01 onwards, failing with err, which
+// is formatted with their number if it has a verb
+func failNodes(ws *WorkerSet, n int, err string) {
+	for i := 1; i <= n; i++ {
+		msg := err
+		if strings.Contains(err, "%") {
+			msg = fmt.Sprintf(err, i)
+		}
+		ws.Add(fmt.Sprintf("node-%02d", i)).FailWith(errors.New(msg))
+	}
+}
+
+func TestErrorGroupingExact(t *testing.T) {
+	ws := New()
+	failNodes(ws, 5, "connection refused")
+	ws.Add("other").FailWith(errors.New("disk full"))
+	got := strings.Join(ws.frameLines(false, true), "\n")
+	want := strings.Join([]string{
+		"  [FAIL] node-01",
+		"  [FAIL] node-02",
+		"  [FAIL] node-03",
+		"  [FAIL] node-04",
+		"  [FAIL] node-05",
+		"  [FAIL] other",
+		"         disk full",
+		"  [FAIL] 5 workers failed with this error: node-01…node-05",
+		"         connection refused",
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// each Worker's own error is still exported
+	b, _ := json.Marshal(ws)
+	if n := strings.Count(string(b), `"error":"connection refused"`); n != 5 {
+		t.Errorf("JSON has the error %d times, want 5", n)
+	}
+}
+
+func TestErrorGroupingNormalizer(t *testing.T) {
+	digits := regexp.MustCompile(`[0-9]+`)
+	for _, tc := range []struct {
+		normalize func(string) string
+		groups    int
+	}{
+		{nil, 0},
+		{func(s string) string { return digits.ReplaceAllString(s, "N") }, 1},
+	} {
+		ws := New(WithErrorNormalizer(tc.normalize))
+		failNodes(ws, 4, "refused by 10.0.0.%d")
+		out := strings.Join(ws.frameLines(false, true), "\n")
+		if n := strings.Count(out, "failed with this error"); n != tc.groups {
+			t.Errorf("%d groups, want %d:\n%s", n, tc.groups, out)
+		}
+		if tc.groups == 1 && !strings.Contains(out, "4 workers failed with this error: node-01, node-02, node-03, node-04\n         refused by 10.0.0.1") {
+			t.Errorf("group lacks the first error:\n%s", out)
+		}
+	}
+}
+
+func TestErrorGroupingThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		threshold, failed int
+		grouped           bool
+	}{
+		{3, 2, false},
+		{3, 3, true},
+		{5, 4, false},
+		{5, 5, true},
+		{0, 50, false},
+	} {
+		ws := New(WithErrorGrouping(tc.threshold))
+		failNodes(ws, tc.failed, "timeout")
+		out := strings.Join(ws.frameLines(false, true), "\n")
+		if got := strings.Contains(out, "failed with this error"); got != tc.grouped {
+			t.Errorf("threshold %d with %d failed: grouped %v, want %v", tc.threshold, tc.failed, got, tc.grouped)
+		}
+	}
+}
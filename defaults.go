@@ -0,0 +1,54 @@
+package multistatus
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrDefaultsFixed is returned by SetDefaults once a WorkerSet has been
+// created
+var ErrDefaultsFixed = errors.New("multistatus: defaults are fixed once a WorkerSet is created")
+
+// defaults holds the Options set by SetDefaults. used is set by the first
+// New, after which they can't change.
+var defaults struct {
+	mu   sync.Mutex
+	opts []Option
+	used bool
+}
+
+// SetDefaults sets Options applied by every WorkerSet created from then on,
+// including by Begin, Run and NewPipeline, for programs that wrap the package
+// and want the same configuration everywhere. They're applied before the
+// Options given to New, which override them. Each call replaces the defaults
+// set by the last.
+//
+// The defaults are fixed once the first WorkerSet is created, so that every
+// WorkerSet in the process is created with the same ones: SetDefaults
+// returns ErrDefaultsFixed after that without changing them. It's safe to
+// call from multiple goroutines.
+func SetDefaults(opts ...Option) error {
+	defaults.mu.Lock()
+	defer defaults.mu.Unlock()
+	if defaults.used {
+		return ErrDefaultsFixed
+	}
+	defaults.opts = append([]Option(nil), opts...)
+	return nil
+}
+
+// Defaults returns the Options set by SetDefaults, for debugging, such as to
+// tell whether any were set at all
+func Defaults() []Option {
+	defaults.mu.Lock()
+	defer defaults.mu.Unlock()
+	return append([]Option(nil), defaults.opts...)
+}
+
+// useDefaults returns the Options set by SetDefaults, fixing them
+func useDefaults() []Option {
+	defaults.mu.Lock()
+	defer defaults.mu.Unlock()
+	defaults.used = true
+	return defaults.opts
+}
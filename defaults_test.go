@@ -0,0 +1,73 @@
+package multistatus
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// freshDefaults clears the defaults, and whether they were used, for the
+// length of the test
+func freshDefaults(t *testing.T) {
+	defaults.mu.Lock()
+	opts, used := defaults.opts, defaults.used
+	defaults.opts, defaults.used = nil, false
+	defaults.mu.Unlock()
+	t.Cleanup(func() {
+		defaults.mu.Lock()
+		defaults.opts, defaults.used = opts, used
+		defaults.mu.Unlock()
+	})
+}
+
+func TestSetDefaults(t *testing.T) {
+	freshDefaults(t)
+	if err := SetDefaults(WithHeader("org"), WithMaxRows(5)); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(Defaults()); n != 2 {
+		t.Errorf("%d defaults, want 2", n)
+	}
+	ws := New(WithHeader("mine"))
+	if ws.header != "mine" || ws.maxRows != 5 {
+		t.Errorf("header %q and max rows %d, want the option given to New to override", ws.header, ws.maxRows)
+	}
+	if ws := New(); ws.header != "org" {
+		t.Errorf("header %q, want the default", ws.header)
+	}
+
+	if err := SetDefaults(WithMaxRows(1)); err != ErrDefaultsFixed {
+		t.Errorf("got %v after New, want ErrDefaultsFixed", err)
+	}
+	if n := len(Defaults()); n != 2 || New().maxRows != 5 {
+		t.Errorf("defaults changed once fixed")
+	}
+}
+
+func TestSetDefaultsConcurrent(t *testing.T) {
+	freshDefaults(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			SetDefaults(WithHeader(fmt.Sprint(i)))
+			Defaults()
+		}()
+	}
+	wg.Wait()
+	var h int
+	if _, err := fmt.Sscan(New().header, &h); err != nil || h < 0 || h >= 20 {
+		t.Errorf("header %q isn't one of the defaults set", New().header)
+	}
+}
+
+func TestNoDefaults(t *testing.T) {
+	freshDefaults(t)
+	if d := Defaults(); len(d) != 0 {
+		t.Errorf("%d defaults set", len(d))
+	}
+	if ws := New(); ws.header != "" || ws.maxRows != 0 {
+		t.Errorf("header %q and max rows %d without defaults", ws.header, ws.maxRows)
+	}
+}
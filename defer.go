@@ -0,0 +1,84 @@
+package multistatus
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// WithFinalizerWarnings downgrades the errors of finalizers registered with
+// Defer to warnings: a finalizer that returns an error or panics adds a note
+// to the Worker saying so, see AddNote, rather than failing it.
+func WithFinalizerWarnings(b bool) Option {
+	return func(w *WorkerSet) {
+		w.softFinalizers = b
+	}
+}
+
+// Defer registers fn to clean up after a Worker started with Go or Command.
+// Once the Worker's function returns, or panics, its finalizers are called
+// in the reverse order they were registered, with the Worker shown as
+// finalizing, before it's marked Completed or Failed. They are also called
+// for a Worker finished before its function ran, such as one canceled while
+// waiting to start or skipped by a Pipeline. A finalizer that returns an
+// error or panics fails the Worker, with the error joined to any the
+// function returned, unless WithFinalizerWarnings is given. Print waits for
+// the finalizers to finish.
+func (w *Worker) Defer(fn func() error) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.deferred = append(w.deferred, fn)
+	ws.mu.Unlock()
+}
+
+// finalize calls the Worker's finalizers, returning err joined with any
+// errors they return
+func (w *Worker) finalize(err error) error {
+	ws := w.parent
+	ws.mu.Lock()
+	fns := w.deferred
+	w.deferred = nil
+	w.finalizing = len(fns) > 0
+	ws.mu.Unlock()
+	if len(fns) == 0 {
+		return err
+	}
+	ws.touch()
+	errs := []error{err}
+	for i := len(fns) - 1; i >= 0; i-- {
+		ferr := callFinalizer(fns[i])
+		if ferr != nil && ws.softFinalizers {
+			w.AddNote(fmt.Sprintf(ws.messages.CleanupFailed, ferr))
+			continue
+		}
+		errs = append(errs, ferr)
+	}
+	ws.mu.Lock()
+	w.finalizing = false
+	ws.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// finalizeIdle calls the finalizers of a Worker finishing without its
+// function running, as finalize does, unless the function is running, in
+// which case run calls them once it returns
+func (w *Worker) finalizeIdle(err error) error {
+	ws := w.parent
+	ws.mu.Lock()
+	executing := w.executing
+	ws.mu.Unlock()
+	if executing {
+		return err
+	}
+	return w.finalize(err)
+}
+
+// callFinalizer calls fn, turning a panic into an error
+func callFinalizer(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn()
+}
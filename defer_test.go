@@ -0,0 +1,97 @@
+package multistatus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeferOrder(t *testing.T) {
+	ws := New(WithSilent(true))
+	var order []int
+	var w *Worker
+	w = ws.Go("job", func(ctx context.Context) error {
+		w.Defer(func() error { order = append(order, 1); return nil })
+		w.Defer(func() error { order = append(order, 2); return errors.New("cleanup") })
+		return nil
+	})
+	ws.Print(context.Background())
+	if len(order) != 2 || order[0] != 2 || order[1] != 1 {
+		t.Errorf("finalizers ran in order %v, want [2 1]", order)
+	}
+	if w.State != Failed || w.Err().Error() != "cleanup" {
+		t.Errorf("Worker is %v with %v, want failed with the finalizer's error", w.State, w.Err())
+	}
+}
+
+func TestFinalizerWarnings(t *testing.T) {
+	ws := New(WithSilent(true), WithFinalizerWarnings(true))
+	var w *Worker
+	w = ws.Go("job", func(ctx context.Context) error {
+		w.Defer(func() error { panic("boom") })
+		w.Defer(func() error { return errors.New("no lock to release") })
+		return nil
+	})
+	ws.Print(context.Background())
+	if w.State != Completed {
+		t.Fatalf("Worker is %v with %v, want completed", w.State, w.Err())
+	}
+	notes := ws.Snapshot()[0].Notes
+	if len(notes) != 2 || notes[0] != "cleanup failed: no lock to release" || !bytes.HasPrefix([]byte(notes[1]), []byte("cleanup failed: panic: boom")) {
+		t.Errorf("notes %q, want one per failed finalizer", notes)
+	}
+}
+
+func TestFinalizersOnEveryPath(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		run  func(t *testing.T, ran *int32)
+	}{
+		{"canceled while queued", func(t *testing.T, ran *int32) {
+			ws := New(WithSilent(true), WithConcurrency(1))
+			ctx, cancel := context.WithCancel(context.Background())
+			ws.Go("first", func(ctx context.Context) error {
+				cancel()
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			queued := ws.Go("queued", func(context.Context) error { return nil })
+			queued.Defer(func() error { atomic.AddInt32(ran, 1); return nil })
+			ws.Print(ctx)
+		}},
+		{"canceled before Print", func(t *testing.T, ran *int32) {
+			ws := New(WithSilent(true))
+			w := ws.Add("by hand")
+			w.Defer(func() error { atomic.AddInt32(ran, 1); return nil })
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			ws.Print(ctx)
+		}},
+		{"skipped stage", func(t *testing.T, ran *int32) {
+			p := NewPipeline(WithSilent(true), WithOutput(&syncBuf{}))
+			first, second := New(), New()
+			p.AddStage("first", first)
+			p.AddStage("second", second)
+			first.Go("fails", func(context.Context) error { return errors.New("broken") })
+			w := second.Go("skipped", func(context.Context) error { return nil })
+			w.Defer(func() error { atomic.AddInt32(ran, 1); return nil })
+			p.Print(context.Background())
+		}},
+		{"closed", func(t *testing.T, ran *int32) {
+			ws := New(WithSilent(true))
+			w := ws.Add("by hand")
+			w.Defer(func() error { atomic.AddInt32(ran, 1); return nil })
+			ws.Close()
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ran int32
+			tc.run(t, &ran)
+			if ran != 1 {
+				t.Errorf("finalizer ran %d times, want 1", ran)
+			}
+		})
+	}
+}
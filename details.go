@@ -0,0 +1,55 @@
+package multistatus
+
+import "strings"
+
+// WithErrorLines limits the number of lines shown for each failed Worker's
+// error, 6 by default, noting how many more there were. A limit of 0 shows
+// every line.
+func WithErrorLines(n int) Option {
+	return func(w *WorkerSet) {
+		w.maxErrLines = n
+	}
+}
+
+// WithPlainWrapWidth sets the column at which errors are wrapped when the
+// output isn't a terminal, 100 by default. A width of 0 doesn't wrap them.
+func WithPlainWrapWidth(n int) Option {
+	return func(w *WorkerSet) {
+		w.plainWrap = n
+	}
+}
+
+// errorLines formats v's error to follow its line in the final report. The
+// error is wrapped at spaces to width columns, or to the plain wrap width if
+// width is 0, with each line indented to start under the Worker's name.
+func (w *WorkerSet) errorLines(v WorkerStatus, marker string, width int) []string {
+	if v.Err == "" {
+		return nil
+	}
+	if width == 0 {
+		width = w.plainWrap
+	}
+	indent := strings.Repeat(" ", 3+stringWidth(marker))
+	avail := 0
+	if width > 0 {
+		avail = width - len(indent)
+		if avail < 8 {
+			avail = 8
+		}
+	}
+
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(v.Err, "\n"), "\n") {
+		l = stripControl(strings.Replace(l, "\t", "    ", -1))
+		lines = append(lines, wrapText(l, avail)...)
+	}
+	m := &w.messages
+	if n := w.maxErrLines; n > 0 && len(lines) > n {
+		more := len(lines) - n
+		lines = append(lines[:n], m.pluralf(more, m.MoreLines, more))
+	}
+	for i, l := range lines {
+		lines[i] = truncate(indent+l, widthOrMax(width))
+	}
+	return lines
+}
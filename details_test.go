@@ -0,0 +1,100 @@
+package multistatus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const longErr = `Get "https://example.com/api/v1/things": dial tcp 10.0.0.1:443: connect: connection refused. The server did not respond within the deadline 日本語日本語日本語 averyveryveryveryveryveryveryveryverylongword`
+
+func TestWrapText(t *testing.T) {
+	for _, tc := range []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"one two three four", 9, []string{"one two", "three", "four"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"日本語日本語", 5, []string{"日本", "語日", "本語"}},
+		{"one two three", 0, []string{"one two three"}},
+	} {
+		if got := wrapText(tc.s, tc.width); strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("wrapText(%q, %d) = %q, want %q", tc.s, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestErrorLinesWrap(t *testing.T) {
+	for _, width := range []int{30, 40, 60, 100} {
+		ws := New(WithPlainWrapWidth(width), WithErrorLines(0))
+		ws.Add("bad-worker").FailWith(errors.New(longErr))
+		lines := ws.frameLines(false, true)
+		if len(lines) < 3 {
+			t.Fatalf("width %d: error not wrapped:\n%s", width, strings.Join(lines, "\n"))
+		}
+		indent := strings.Index(lines[0], "bad-worker")
+		var words []string
+		for _, l := range lines[1:] {
+			if w := stringWidth(l); w > width {
+				t.Errorf("width %d: %d columns in %q", width, w, l)
+			}
+			if strings.TrimLeft(l, " ") != l[indent:] {
+				t.Errorf("width %d: %q not indented under the name", width, l)
+			}
+			words = append(words, strings.TrimSpace(l))
+		}
+		// nothing is lost but the spaces lines were broken at
+		got := strings.ReplaceAll(strings.Join(words, ""), " ", "")
+		if want := strings.ReplaceAll(longErr, " ", ""); got != want {
+			t.Errorf("width %d: got %q", width, got)
+		}
+	}
+}
+
+func TestErrorLinesLimit(t *testing.T) {
+	err := errors.New("panic: oops\n\tat a.go:1\n\tat b.go:2\n\tat c.go:3\n\tat d.go:4\n\tat e.go:5\n\tat f.go:6\n\tat g.go:7")
+	ws := New()
+	ws.Add("x").FailWith(err)
+	lines := ws.frameLines(false, true)
+	if len(lines) != 8 {
+		t.Fatalf("got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if got, want := strings.TrimSpace(lines[7]), "… 2 more lines"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := strings.TrimSpace(lines[2]), "at a.go:1"; got != want {
+		t.Errorf("tab not expanded: %q", got)
+	}
+
+	ws = New(WithErrorLines(0))
+	ws.Add("x").FailWith(err)
+	if lines := ws.frameLines(false, true); len(lines) != 9 {
+		t.Errorf("WithErrorLines(0): got %d lines", len(lines))
+	}
+}
+
+func TestErrorLinesUnwrapped(t *testing.T) {
+	ws := New(WithPlainWrapWidth(0))
+	ws.Add("x").FailWith(errors.New(longErr))
+	lines := ws.frameLines(false, true)
+	if len(lines) != 2 || !strings.HasSuffix(lines[1], longErr) {
+		t.Errorf("got %q", lines)
+	}
+}
+
+func TestErrorLinesTerminalWidth(t *testing.T) {
+	// the terminal's width wins over the plain wrap width
+	ws := New(WithSize(50, 24), WithPlainWrapWidth(0), WithColor(false))
+	ws.Add("x").FailWith(errors.New(longErr))
+	_, lines := ws.frame(true, true)
+	if len(lines) < 4 {
+		t.Fatalf("not wrapped: %q", lines)
+	}
+	for _, l := range lines {
+		if w := stringWidth(l); w > 50 {
+			t.Errorf("%d columns in %q", w, l)
+		}
+	}
+}
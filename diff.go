@@ -0,0 +1,91 @@
+package multistatus
+
+// A Field identifies a field of WorkerStatus that changed
+type Field uint
+
+// Fields compared by DiffSnapshots. Elapsed is left out, as it changes
+// constantly.
+const (
+	FieldState Field = 1 << iota
+	FieldStatus
+	FieldErr
+	FieldPriority
+	FieldPinned
+)
+
+// Has reports whether f includes all of the fields in g
+func (f Field) Has(g Field) bool {
+	return f&g == g
+}
+
+// WorkerChange describes a Worker present in both snapshots whose status
+// changed between them
+type WorkerChange struct {
+	ID     int64
+	Old    WorkerStatus
+	New    WorkerStatus
+	Fields Field
+}
+
+// SnapshotDiff is the difference between two snapshots
+type SnapshotDiff struct {
+	// Added holds Workers only in the new snapshot, in its order
+	Added []WorkerStatus
+	// Removed holds Workers only in the old snapshot, in its order
+	Removed []WorkerStatus
+	// Changed holds Workers whose status changed, in the new snapshot's
+	// order
+	Changed []WorkerChange
+}
+
+// Empty reports whether the snapshots were the same
+func (d SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffSnapshots compares two snapshots, as returned by WorkerSet.Snapshot,
+// matching Workers by ID so that order and duplicate names don't matter.
+func DiffSnapshots(old, new []WorkerStatus) SnapshotDiff {
+	var d SnapshotDiff
+	prev := make(map[int64]WorkerStatus, len(old))
+	for _, v := range old {
+		prev[v.ID] = v
+	}
+	for _, v := range new {
+		o, ok := prev[v.ID]
+		if !ok {
+			d.Added = append(d.Added, v)
+			continue
+		}
+		delete(prev, v.ID)
+		if f := changedFields(o, v); f != 0 {
+			d.Changed = append(d.Changed, WorkerChange{ID: v.ID, Old: o, New: v, Fields: f})
+		}
+	}
+	for _, v := range old {
+		if _, ok := prev[v.ID]; ok {
+			d.Removed = append(d.Removed, v)
+		}
+	}
+	return d
+}
+
+func changedFields(a, b WorkerStatus) Field {
+	var f Field
+	if a.State != b.State {
+		f |= FieldState
+	}
+	if a.Status != b.Status {
+		f |= FieldStatus
+	}
+	if a.Err != b.Err {
+		f |= FieldErr
+	}
+	if a.Priority != b.Priority {
+		f |= FieldPriority
+	}
+	if a.Pinned != b.Pinned {
+		f |= FieldPinned
+	}
+	return f
+}
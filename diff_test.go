@@ -0,0 +1,62 @@
+package multistatus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	ws := New()
+	a, b, c := ws.Add("dup"), ws.Add("dup"), ws.Add("other")
+	old := ws.Snapshot()
+
+	a.SetStatus("working")
+	b.FailWith(errors.New("broke"))
+	b.SetPriority(3)
+	c.Done()
+	if err := ws.Remove(c); err != nil {
+		t.Fatal(err)
+	}
+	ws.Add("dup")
+	cur := ws.Snapshot()
+
+	idsOf := func(vs []WorkerStatus) (ids []int64) {
+		for _, v := range vs {
+			ids = append(ids, v.ID)
+		}
+		return ids
+	}
+	for _, tc := range []struct {
+		name     string
+		old, new []WorkerStatus
+	}{
+		{"in order", old, cur},
+		{"old permuted", []WorkerStatus{old[2], old[0], old[1]}, cur},
+		{"new permuted", old, []WorkerStatus{cur[2], cur[1], cur[0]}},
+	} {
+		d := DiffSnapshots(tc.old, tc.new)
+		if ids := idsOf(d.Added); len(ids) != 1 || ids[0] != 3 {
+			t.Errorf("%s: added %v, want [3]", tc.name, ids)
+		}
+		if ids := idsOf(d.Removed); len(ids) != 1 || ids[0] != c.ID() {
+			t.Errorf("%s: removed %v, want [%d]", tc.name, ids, c.ID())
+		}
+		changed := map[int64]Field{}
+		for _, ch := range d.Changed {
+			if ch.Old.ID != ch.ID || ch.New.ID != ch.ID {
+				t.Errorf("%s: change of %d pairs %d with %d", tc.name, ch.ID, ch.Old.ID, ch.New.ID)
+			}
+			changed[ch.ID] = ch.Fields
+		}
+		if len(changed) != 2 || changed[a.ID()] != FieldStatus || changed[b.ID()] != FieldState|FieldErr|FieldPriority {
+			t.Errorf("%s: changes %v", tc.name, changed)
+		}
+	}
+
+	if d := DiffSnapshots(cur, cur); !d.Empty() {
+		t.Errorf("a snapshot differs from itself: %+v", d)
+	}
+	if !(FieldState | FieldErr).Has(FieldErr) || FieldErr.Has(FieldState|FieldErr) {
+		t.Error("Field.Has")
+	}
+}
@@ -0,0 +1,639 @@
+package multistatus
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A display owns a region of a terminal and redraws the blocks of every
+// WorkerSet or Pipeline attached to it from a single loop, stacking them
+// vertically so they don't overwrite each other.
+type display struct {
+	out io.Writer
+
+	// clock paces the frames, taken from the block that started the loop
+	clock Clock
+
+	mu      sync.Mutex
+	entries []*displayEntry
+	wake    chan struct{}
+	running bool
+
+	// stopped is closed when the running loop exits, and fresh is set
+	// until its first frame has been drawn
+	stopped chan struct{}
+	fresh   bool
+
+	// urgent skips the wait before the next frame, which closes drawn
+	urgent bool
+	drawn  chan struct{}
+
+	// height is the number of lines in the live region after the last
+	// frame, which the next frame must erase, and prev holds them
+	height int
+	prev   []string
+
+	// cost is the average time taken to write a frame, and active the
+	// refresh interval as stretched to account for it
+	cost      time.Duration
+	active    time.Duration
+	stretched bool
+
+	// title is the last title sequence written
+	title string
+
+	// broken is set once a write fails, after which blocks are printed
+	// without escapes once they finish
+	broken bool
+}
+
+// A block is drawn by a display
+type block interface {
+	// frame returns the block's history and live lines, as
+	// WorkerSet.frame does
+	frame(isTerm, final bool) (history, lines []string)
+
+	// refreshInterval returns how often the block should be redrawn
+	// while it changes, at most how long to wait while it doesn't, and
+	// the slowest it may be redrawn while it changes on a slow terminal
+	refreshInterval() (active, idle, slowest time.Duration)
+
+	// NeedsRedraw and NextAnimationDeadline tell when the block must be
+	// redrawn, as WorkerSet's do
+	NeedsRedraw() bool
+	NextAnimationDeadline() time.Time
+
+	// timing returns the clock the block's deadlines are read from
+	timing() Clock
+
+	// setDisplay tells the block which display draws it, to poke
+	// whenever it changes
+	setDisplay(d *display)
+
+	// logf records a message in the block's run log
+	logf(format string, args ...interface{})
+
+	// title returns the sequence setting the terminal's title to the
+	// block's progress, or an empty string for none
+	title() string
+
+	// caps returns the escapes the block may have written
+	caps() escapeCaps
+
+	// guard writes the escapes placing the cursor at the start of a line
+	// before the first frame, as set by WithLineGuard
+	guard(e *escWriter)
+
+	// inline reports whether frames must be redrawn in place on the
+	// cursor's line, as the terminal is a single line high
+	inline() bool
+
+	// degraded reports whether a frame of the block was slow enough that
+	// only the lines that changed should be redrawn
+	degraded() bool
+
+	// wrote records a write of p, frames including the block's, which
+	// took d
+	wrote(p []byte, d time.Duration)
+}
+
+type displayEntry struct {
+	b block
+
+	// shown is set once a frame of the block has been drawn
+	shown bool
+
+	// finished is set once the block is done. The display then draws
+	// its final frame, freezes it in final, and closes rendered once it
+	// has been written.
+	finished bool
+	final    []string
+	rendered chan struct{}
+
+	// stopped is closed when the loop drawing the block exits
+	stopped chan struct{}
+
+	// err is the first error writing any of the block's frames
+	err error
+}
+
+var (
+	displaysMu sync.Mutex
+	displays   = map[*os.File]*display{}
+)
+
+// sharedDisplay returns the package-level display for out. Only files can be
+// shared; any other writer gets a display of its own.
+func sharedDisplay(out io.Writer) *display {
+	f, ok := out.(*os.File)
+	if !ok {
+		return newDisplay(out)
+	}
+	displaysMu.Lock()
+	defer displaysMu.Unlock()
+	d, ok := displays[f]
+	if !ok {
+		d = newDisplay(f)
+		displays[f] = d
+	}
+	return d
+}
+
+func newDisplay(out io.Writer) *display {
+	return &display{out: out, wake: make(chan struct{}, 1), drawn: make(chan struct{})}
+}
+
+// WithSharedDisplay controls whether the WorkerSet shares the terminal with
+// other WorkerSets printing to the same output at the same time. When shared,
+// which is the default, their blocks are stacked and drawn together;
+// otherwise the WorkerSet redraws its own block independently.
+func WithSharedDisplay(b bool) Option {
+	return func(w *WorkerSet) {
+		w.unshared = !b
+	}
+}
+
+// attach adds b to the display, starting the render loop if needed
+func (d *display) attach(b block) *displayEntry {
+	return d.attachEntry(&displayEntry{b: b, rendered: make(chan struct{})})
+}
+
+// flash draws only the final frame of b, which has already finished,
+// returning the error writing it
+func (d *display) flash(b block) error {
+	return d.finish(d.attachEntry(&displayEntry{b: b, finished: true, rendered: make(chan struct{})}))
+}
+
+// attachEntry adds e to the display as attach does
+func (d *display) attachEntry(e *displayEntry) *displayEntry {
+	b := e.b
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = append(d.entries, e)
+	b.setDisplay(d)
+	if !d.running {
+		d.running = true
+		d.clock = b.timing()
+		d.broken = false
+		d.fresh = true
+		d.stopped = make(chan struct{})
+		go func(stopped chan struct{}) {
+			defer close(stopped)
+			d.loop()
+		}(d.stopped)
+	} else {
+		d.poke()
+	}
+	e.stopped = d.stopped
+	return e
+}
+
+// finish marks e as done and blocks until its final frame has been written,
+// and if that was the loop's last frame, until the loop has exited. It
+// returns the first error writing the block's frames.
+func (d *display) finish(e *displayEntry) error {
+	d.mu.Lock()
+	e.finished = true
+	d.mu.Unlock()
+	d.poke()
+	<-e.rendered
+	d.mu.Lock()
+	drawing := d.running && d.stopped == e.stopped
+	d.mu.Unlock()
+	if !drawing {
+		<-e.stopped
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return e.err
+}
+
+// poke wakes the loop to redraw soon
+func (d *display) poke() bool {
+	select {
+	case d.wake <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// refresh has the loop redraw straight away and waits until it has, unless
+// the loop isn't running
+func (d *display) refresh() {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.urgent = true
+	drawn := d.drawn
+	d.mu.Unlock()
+	d.poke()
+	<-drawn
+}
+
+// loop draws a first frame straight away, then sleeps until a block needs
+// redrawing, as told by NeedsRedraw, or the earliest of their
+// NextAnimationDeadlines passes. A change is drawn no sooner than the
+// refresh interval after the last frame, gathering any others in between.
+// Once no block is animating, the loop parks until something changes. A
+// block's final frame is drawn as soon as it finishes, so a run that ends
+// before the first tick goes straight from the first frame to the last.
+func (d *display) loop() {
+	last := d.clock.Now()
+	if !d.draw() {
+		return
+	}
+	for {
+		active := d.interval()
+		var tick <-chan time.Time
+		if next := d.nextDeadline(); !next.IsZero() {
+			tick = d.clock.After(next.Sub(d.clock.Now()))
+		}
+		select {
+		case <-tick:
+		case <-d.wake:
+			if !d.needsRedraw() && !d.hurried() {
+				continue
+			}
+			if since := d.clock.Now().Sub(last); since < active {
+				d.debounce(active - since)
+			}
+		}
+		last = d.clock.Now()
+		if !d.draw() {
+			return
+		}
+	}
+}
+
+// debounce waits for wait to pass, gathering further changes, unless a block
+// finishes or a refresh is requested in the meantime
+func (d *display) debounce(wait time.Duration) {
+	timer := d.clock.After(wait)
+	for !d.hurried() {
+		select {
+		case <-timer:
+			return
+		case <-d.wake:
+		}
+	}
+}
+
+// hurried reports whether a refresh was requested or a finished block is
+// waiting for its final frame
+func (d *display) hurried() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.urgent {
+		return true
+	}
+	for _, e := range d.entries {
+		if e.finished && e.final == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// blocks returns the attached blocks, to be asked about outside the lock as
+// they may read the display's state themselves
+func (d *display) blocks() []block {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	blocks := make([]block, len(d.entries))
+	for i, e := range d.entries {
+		blocks[i] = e.b
+	}
+	return blocks
+}
+
+// needsRedraw reports whether any attached block has changed or has yet to
+// be drawn
+func (d *display) needsRedraw() bool {
+	d.mu.Lock()
+	for _, e := range d.entries {
+		if !e.shown {
+			d.mu.Unlock()
+			return true
+		}
+	}
+	d.mu.Unlock()
+	for _, b := range d.blocks() {
+		if b.NeedsRedraw() {
+			return true
+		}
+	}
+	return false
+}
+
+// nextDeadline returns the earliest of the attached blocks'
+// NextAnimationDeadlines, or the zero Time when none is animating
+func (d *display) nextDeadline() time.Time {
+	var next time.Time
+	for _, b := range d.blocks() {
+		if t := b.NextAnimationDeadline(); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// interval returns the shortest active refresh interval of the attached
+// blocks. When frames take long to write, it is stretched to twice the time
+// a frame takes, up to the shortest of the blocks' slowest intervals.
+func (d *display) interval() (active time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	slowest := time.Duration(0)
+	degraded := false
+	for _, e := range d.entries {
+		degraded = degraded || e.b.degraded()
+		a, _, s := e.b.refreshInterval()
+		if a > 0 && (active == 0 || a < active) {
+			active = a
+		}
+		if s > 0 && (slowest == 0 || s < slowest) {
+			slowest = s
+		}
+	}
+	if active == 0 {
+		active = 100 * time.Millisecond
+	}
+	d.stretched = 2*d.cost > active
+	if d.stretched {
+		active = 2 * d.cost
+		if slowest > 0 && active > slowest {
+			active = slowest
+		}
+	}
+	d.stretched = d.stretched || degraded
+	d.active = active
+	return active
+}
+
+// draw redraws every attached block, then drops finished blocks from the top
+// of the region, leaving their final frame in the scrollback. It reports
+// whether any blocks remain to be drawn.
+func (d *display) draw() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.broken {
+		return d.drawPlain()
+	}
+
+	var caps escapeCaps
+	inline := false
+	if len(d.entries) > 0 {
+		caps = d.entries[0].b.caps()
+		inline = d.entries[0].b.inline()
+	}
+	var history, lines []string
+	var rendered []chan struct{}
+	flushed, top := 0, true
+	for _, e := range d.entries {
+		e.shown = true
+		l := e.final
+		if l == nil {
+			var h []string
+			h, l = e.b.frame(true, e.finished)
+			history = append(history, h...)
+			if e.finished {
+				e.final = l
+				rendered = append(rendered, e.rendered)
+			}
+		}
+		if top && e.finished {
+			flushed += len(l)
+		} else {
+			top = false
+		}
+		lines = append(lines, l...)
+	}
+	drawn := d.entries
+	for len(d.entries) > 0 && d.entries[0].finished {
+		d.entries = d.entries[1:]
+	}
+	lines = append(history, lines...)
+	flushed += len(history)
+	n := len(lines)
+
+	buf := escWriter{caps: caps}
+	buf.SyncBegin()
+	if d.fresh && len(d.entries) > 0 {
+		d.entries[0].b.guard(&buf)
+	}
+	d.fresh = false
+	if inline {
+		// Redraw the line in place, printing any finished lines above it
+		buf.WriteString("\r")
+		buf.EraseLine()
+		for _, l := range lines[:flushed] {
+			buf.WriteString(l + "\n")
+		}
+		buf.WriteString(strings.Join(lines[flushed:], " "))
+	} else if d.stretched && flushed == 0 && n == d.height && len(d.prev) == n {
+		// Rewrite only the lines that changed, moving down past the rest
+		for i, l := range lines {
+			if l == d.prev[i] {
+				buf.CursorDown(1)
+			} else {
+				buf.EraseLine()
+				buf.WriteString(l + "\n")
+			}
+		}
+	} else {
+		wipe := n
+		if d.height > wipe {
+			wipe = d.height
+		}
+
+		// Ensure the output area is at least N lines long, then erase
+		// each line and move back up to its top
+		buf.WriteString(strings.Repeat("\n", wipe))
+		buf.CursorUp(wipe)
+		for i := 0; i < wipe; i++ {
+			buf.EraseLine()
+			buf.CursorDown(1)
+		}
+		buf.CursorUp(wipe)
+		for _, l := range lines {
+			buf.WriteString(l + "\n")
+		}
+	}
+	if len(d.entries) > 0 {
+		if t := d.entries[0].b.title(); t != d.title {
+			buf.WriteString(t)
+			d.title = t
+		}
+	}
+	buf.HideCursor()
+	more := len(d.entries) > 0
+	if more && inline {
+		d.height = 0
+		d.prev = nil
+	} else if more {
+		d.height = n - flushed
+		d.prev = lines[flushed:]
+		buf.CursorUp(d.height)
+	} else {
+		buf.ShowCursor()
+		d.running = false
+		d.height = 0
+		d.prev = nil
+	}
+	buf.SyncEnd()
+	start := d.clock.Now()
+	if _, err := d.out.Write(buf.Bytes()); err != nil {
+		d.broken = true
+		for _, e := range drawn {
+			if e.err == nil {
+				e.err = err
+			}
+			e.b.logf("terminal: write failed, printing without animation: %v", err)
+		}
+	}
+	took := span(start, d.clock.Now())
+	d.cost = (3*d.cost + took) / 4
+	for _, e := range drawn {
+		e.b.wrote(buf.Bytes(), took)
+	}
+	for _, ch := range rendered {
+		close(ch)
+	}
+	d.drawn = d.drew()
+	return more
+}
+
+// drew closes the channel waited on by refresh, returning the next one
+func (d *display) drew() chan struct{} {
+	d.urgent = false
+	close(d.drawn)
+	return make(chan struct{})
+}
+
+// drawPlain prints the final frame of each finished block without escapes,
+// as it would be printed without a terminal, and drops it. It reports
+// whether any blocks remain.
+func (d *display) drawPlain() bool {
+	var buf bytes.Buffer
+	var finished []*displayEntry
+	remaining := d.entries[:0]
+	for _, e := range d.entries {
+		if !e.finished {
+			remaining = append(remaining, e)
+			continue
+		}
+		_, e.final = e.b.frame(false, true)
+		for _, l := range e.final {
+			buf.WriteString(l + "\n")
+		}
+		finished = append(finished, e)
+	}
+	d.entries = remaining
+	_, err := d.out.Write(buf.Bytes())
+	for _, e := range finished {
+		if e.err == nil {
+			e.err = err
+		}
+		close(e.rendered)
+	}
+	d.drawn = d.drew()
+	if len(d.entries) == 0 {
+		d.running = false
+		d.height = 0
+		d.prev = nil
+		return false
+	}
+	return true
+}
+
+// displayFor returns the display ws should draw on
+func (w *WorkerSet) displayFor() *display {
+	if w.unshared {
+		return newDisplay(w.out)
+	}
+	return sharedDisplay(w.out)
+}
+
+func (w *WorkerSet) refreshInterval() (active, idle, slowest time.Duration) {
+	if atomic.LoadInt32(&w.counters) > 0 {
+		return w.refresh, w.refresh, w.slowRefresh
+	}
+	return w.refresh, w.idleRefresh, w.slowRefresh
+}
+
+func (w *WorkerSet) logf(format string, args ...interface{}) {
+	w.log.printf(format, args...)
+}
+
+func (w *WorkerSet) setDisplay(d *display) {
+	w.disp.Store(d)
+}
+
+// Refresh redraws the WorkerSet on the terminal straight away rather than
+// waiting for the next frame, and returns once the frame has been written.
+// Calls made at the same time share a single frame. Refresh does nothing
+// unless Print is drawing to a terminal, and mustn't be called from
+// functions that are themselves called while drawing, such as a line
+// function set with WithLineFunc.
+func (w *WorkerSet) Refresh() {
+	if d, ok := w.disp.Load().(*display); ok {
+		d.refresh()
+	}
+}
+
+// WithSlowRefreshInterval sets the slowest the display is redrawn while
+// Workers change, 2s by default. When a slow terminal, such as one over a
+// poor connection, takes longer to write each frame than the refresh
+// interval allows, the interval is stretched up to this limit and only the
+// lines that changed are redrawn. The interval shrinks back as the terminal
+// catches up.
+func WithSlowRefreshInterval(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.slowRefresh = d
+	}
+}
+
+// AdaptedRefreshInterval returns how often the terminal is being redrawn
+// while Workers change: the refresh interval, or longer if it was stretched
+// for a slow terminal. It returns the refresh interval if Print isn't
+// drawing to a terminal.
+func (w *WorkerSet) AdaptedRefreshInterval() time.Duration {
+	if d, ok := w.disp.Load().(*display); ok {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.running && d.active > 0 {
+			return d.active
+		}
+	}
+	return w.refresh
+}
+
+// WithIdleRefreshInterval sets the longest time between redraws on a
+// terminal while nothing changes, 1s by default. The display is redrawn
+// promptly on any change, and then slows down step by step while the
+// Workers are quiet. An interval no longer than the refresh interval keeps
+// redrawing at the refresh interval.
+func WithIdleRefreshInterval(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.idleRefresh = d
+	}
+}
+
+// WithScrollingHistory moves each Worker's line out of the live block once it
+// finishes, printing it permanently above the block along with how long the
+// Worker took. The live block then holds only pending Workers, and the
+// scrollback keeps a record of every Worker in the order they finished. This
+// only affects output to a terminal.
+func WithScrollingHistory(b bool) Option {
+	return func(w *WorkerSet) {
+		w.scrollHistory = b
+	}
+}
@@ -0,0 +1,302 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// printBoth prints a and b at once, finishing a's Worker well before b's,
+// and returns the order their Prints returned in
+func printBoth(t *testing.T, a, b *WorkerSet) []string {
+	t.Helper()
+	aw, bw := a.Add("alpha"), b.Add("beta")
+	aw.SetStatus("working")
+	bw.SetStatus("working")
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for name, ws := range map[string]*WorkerSet{"a": a, "b": b} {
+		wg.Add(1)
+		go func(name string, ws *WorkerSet) {
+			defer wg.Done()
+			if err := ws.Print(context.Background()); err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}(name, ws)
+	}
+	time.Sleep(20 * time.Millisecond)
+	aw.Done()
+	time.Sleep(50 * time.Millisecond)
+	bw.Done()
+	wg.Wait()
+	return order
+}
+
+func TestSharedDisplay(t *testing.T) {
+	var out syncBuf
+	opts := []Option{WithOutput(&out), WithTTY(true), WithSize(80, 24), WithColor(false),
+		WithRefreshInterval(time.Millisecond)}
+	a, b := New(append(opts, WithHeader("first"))...), New(append(opts, WithHeader("second"))...)
+	if order := printBoth(t, a, b); strings.Join(order, "") != "ab" {
+		t.Errorf("Prints returned in order %v, want a's first", order)
+	}
+
+	vt := newVT(24)
+	vt.feed(out.String())
+	lines := vt.lines()
+	var first, second, alpha, beta = -1, -1, -1, -1
+	for i, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "first "):
+			first = i
+		case strings.HasPrefix(l, "second "):
+			second = i
+		case strings.Contains(l, "alpha"):
+			alpha = i
+		case strings.Contains(l, "beta"):
+			beta = i
+		}
+	}
+	if !(first >= 0 && first < alpha && alpha < second && second < beta) || len(lines) != 4 {
+		t.Errorf("blocks aren't stacked on the screen:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestSharedDisplayOptOut(t *testing.T) {
+	var out syncBuf
+	opts := []Option{WithOutput(&out), WithTTY(true), WithSize(80, 24), WithRefreshInterval(time.Millisecond)}
+	a, b := New(append(opts, WithSharedDisplay(false))...), New(append(opts, WithSharedDisplay(false))...)
+	printBoth(t, a, b)
+	if a.disp.Load().(*display) == b.disp.Load().(*display) {
+		t.Error("WorkerSets opted out of sharing share a display")
+	}
+}
+
+func TestSeparateOutputs(t *testing.T) {
+	var outA, outB syncBuf
+	a := New(WithOutput(&outA), WithTTY(true), WithSize(80, 24), WithRefreshInterval(time.Millisecond))
+	b := New(WithOutput(&outB), WithTTY(true), WithSize(80, 24), WithRefreshInterval(time.Millisecond))
+	printBoth(t, a, b)
+	if strings.Contains(outA.String(), "beta") || strings.Contains(outB.String(), "alpha") {
+		t.Error("WorkerSets on different outputs drew each other's Workers")
+	}
+	if !strings.Contains(outA.String(), "alpha") || !strings.Contains(outB.String(), "beta") {
+		t.Error("WorkerSets didn't draw their own Workers")
+	}
+}
+
+// idleFrames prints a WorkerSet with one Worker left untouched for d, at the
+// given refresh intervals, on a fake clock moved on a tenth of a second at a
+// time, and returns the number of frames drawn over d
+func idleFrames(t *testing.T, d, active, idle time.Duration) int64 {
+	t.Helper()
+	clock := newFakeClock()
+	ws := New(WithOutput(&syncBuf{}), WithTTY(true), WithSharedDisplay(false), WithClock(clock),
+		WithRenderStats(true), WithRefreshInterval(active), WithIdleRefreshInterval(idle))
+	w := ws.Add("quiet")
+	printed := make(chan error, 1)
+	go func() { printed <- ws.Print(context.Background()) }()
+
+	// between frames the display waits on the clock, and nothing else does
+	waiting := func() bool { return clock.Waiters() == 1 }
+	if !waitFor(waiting) {
+		t.Fatal("the display never waited on the clock")
+	}
+	for end := clock.Now().Add(d); clock.Now().Before(end); {
+		clock.Advance(100 * time.Millisecond)
+		if !waitFor(waiting) {
+			t.Fatalf("the display stopped waiting on the clock at %v", clock.Now())
+		}
+	}
+	frames := ws.RenderStats().Frames
+	w.Done()
+	if err := <-printed; err != nil {
+		t.Fatal(err)
+	}
+	return frames
+}
+
+// TestIdleCadence leaves a Worker idle for a minute at the default refresh
+// intervals. Slowing down, the display draws a first frame, then frames at
+// 0.1s, 0.3s, 0.7s and 1.5s, and every second from 2.5s to 59.5s.
+func TestIdleCadence(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		idle   time.Duration
+		frames int64
+	}{
+		{"adaptive", time.Second, 1 + 4 + 58},
+		{"fixed", 100 * time.Millisecond, 1 + 600},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := idleFrames(t, time.Minute, 100*time.Millisecond, tc.idle); got != tc.frames {
+				t.Errorf("drew %d frames in an idle minute, want %d", got, tc.frames)
+			}
+		})
+	}
+}
+
+func TestIdleWakesOnChange(t *testing.T) {
+	var buf syncBuf
+	ws := New(WithOutput(&buf), WithTTY(true), WithColor(false),
+		WithRefreshInterval(5*time.Millisecond), WithIdleRefreshInterval(time.Hour))
+	w := ws.Add("task")
+	go ws.Print(context.Background())
+	// long enough to have slowed right down
+	time.Sleep(100 * time.Millisecond)
+	w.SetStatus("changed")
+	if !waitFor(func() bool { return strings.Contains(buf.String(), "changed") }) {
+		t.Errorf("change not drawn while idle")
+	}
+	w.Done()
+	ws.Close()
+}
+
+// fastFrames prints a WorkerSet whose one Worker finishes before Print, or
+// once the first frame is drawn if late is set, and returns the frames drawn
+// and the output. The fake clock stands still, so no tick comes in between.
+func fastFrames(t *testing.T, late bool) (int64, string) {
+	t.Helper()
+	var buf syncBuf
+	ws := New(WithOutput(&buf), WithTTY(true), WithSharedDisplay(false), WithColor(false),
+		WithClock(newFakeClock()), WithRenderStats(true))
+	w := ws.Add("quick")
+	if !late {
+		w.Done()
+	}
+	printed := make(chan error, 1)
+	go func() { printed <- ws.Print(context.Background()) }()
+	if late {
+		if !waitFor(func() bool { return ws.RenderStats().Frames == 1 }) {
+			t.Fatal("no first frame")
+		}
+		w.Done()
+	}
+	if err := <-printed; err != nil {
+		t.Fatal(err)
+	}
+	return ws.RenderStats().Frames, buf.String()
+}
+
+func TestFastRun(t *testing.T) {
+	for _, tc := range []struct {
+		late   bool
+		frames int64
+	}{
+		{false, 1},
+		{true, 2},
+	} {
+		frames, out := fastFrames(t, tc.late)
+		if frames != tc.frames {
+			t.Errorf("finishing late %v drew %d frames, want %d", tc.late, frames, tc.frames)
+		}
+		if !tc.late && strings.Contains(out, "⠋") {
+			t.Errorf("instant run drew a spinner: %q", out)
+		}
+		vt := newVT(24)
+		vt.feed(out)
+		if lines := vt.lines(); len(lines) != 2 || lines[0] != "  ✔ quick" {
+			t.Errorf("finishing late %v left %q", tc.late, lines)
+		}
+	}
+}
+
+// clockedWriter takes delay by the fake clock to write, sending it on writing
+// once it has begun to wait so the clock can be moved on, and notes when each
+// write began. The delay may be changed.
+type clockedWriter struct {
+	buf     syncBuf
+	clock   *fakeClock
+	delay   atomic.Int64
+	writing chan time.Duration
+
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+func (w *clockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.starts = append(w.starts, w.clock.Now())
+	w.mu.Unlock()
+	if d := time.Duration(w.delay.Load()); d > 0 {
+		done := w.clock.After(d)
+		w.writing <- d
+		<-done
+	}
+	return w.buf.Write(p)
+}
+
+// gap returns the time between the last two writes, or 0 before the second
+func (w *clockedWriter) gap() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if n := len(w.starts); n >= 2 {
+		return w.starts[n-1].Sub(w.starts[n-2])
+	}
+	return 0
+}
+
+func TestSlowTerminal(t *testing.T) {
+	clock := newFakeClock()
+	out := &clockedWriter{clock: clock, writing: make(chan time.Duration)}
+	out.delay.Store(int64(20 * time.Millisecond))
+	ws := New(WithOutput(out), WithTTY(true), WithSharedDisplay(false), WithClock(clock),
+		WithRenderStats(true), WithRefreshInterval(5*time.Millisecond),
+		WithSlowRefreshInterval(200*time.Millisecond))
+	w := ws.Add("busy")
+	printed := make(chan error, 1)
+	go func() { printed <- ws.Print(context.Background()) }()
+
+	// step changes the Worker twice while a frame is being written, the
+	// second change finding the display busy, and lets the write finish;
+	// otherwise it changes the Worker and moves the clock on a refresh
+	// interval. The display is locked while it writes, so how often it's
+	// redrawn is read from the writes rather than AdaptedRefreshInterval.
+	i := 0
+	step := func() {
+		i++
+		select {
+		case d := <-out.writing:
+			w.SetStatus(fmt.Sprint(i, "a"))
+			w.SetStatus(fmt.Sprint(i, "b"))
+			clock.Advance(d)
+		default:
+			w.SetStatus(fmt.Sprint(i))
+			clock.Advance(5 * time.Millisecond)
+		}
+	}
+	if !waitFor(func() bool { step(); return out.gap() >= 30*time.Millisecond }) {
+		t.Fatalf("frames written %v apart on a slow terminal", out.gap())
+	}
+	if s := ws.RenderStats(); s.Skipped == 0 {
+		t.Errorf("no changes skipped: %+v", s)
+	}
+
+	// and speeds back up once the terminal recovers
+	out.delay.Store(0)
+	if !waitFor(func() bool { step(); return out.gap() == 5*time.Millisecond }) {
+		t.Errorf("frames written %v apart after the terminal recovered", out.gap())
+	}
+	if got := ws.AdaptedRefreshInterval(); got != 5*time.Millisecond {
+		t.Errorf("interval is %v after the terminal recovered", got)
+	}
+	w.Done()
+	if err := <-printed; err != nil {
+		t.Fatal(err)
+	}
+
+	// the interval was stretched no further than the slowest
+	for j := 1; j < len(out.starts); j++ {
+		if gap := out.starts[j].Sub(out.starts[j-1]); gap > 200*time.Millisecond {
+			t.Errorf("frames written %v apart, past the slowest", gap)
+		}
+	}
+}
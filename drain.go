@@ -0,0 +1,96 @@
+package multistatus
+
+import (
+	"container/heap"
+	"errors"
+	"time"
+)
+
+// ErrDraining is the error of Workers canceled by Drain, including those
+// added after it
+var ErrDraining = errors.New("multistatus: WorkerSet is draining")
+
+// WithDrainTimeout sets how long Drain waits for running Workers before
+// canceling them. By default it waits until they finish.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.drainTimeout = d
+	}
+}
+
+// Drain stops the WorkerSet taking on new work, as a long-lived service might
+// on SIGTERM, while it keeps drawing the Workers already running until they
+// finish. Workers waiting to start are canceled without being started, and
+// Workers added from then on are canceled straight away, all with
+// ErrDraining. Once the drain timeout set by WithDrainTimeout passes, Workers
+// still running are canceled too and their Context is canceled. The header
+// notes the number of Workers left while draining.
+//
+// Drain lets Print return once the running Workers finish, including when
+// printing from Begin. Calling Drain again does nothing.
+func (w *WorkerSet) Drain() {
+	w.mu.Lock()
+	if w.draining || w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.draining = true
+	var queued []*Worker
+	for w.queue.Len() > 0 {
+		queued = append(queued, heap.Pop(&w.queue).(*Worker))
+	}
+	if w.drainTimeout > 0 {
+		w.bg.Add(1)
+		w.drainTimer = time.AfterFunc(w.drainTimeout, func() {
+			defer w.bg.Done()
+			w.drainTimedOut()
+		})
+	}
+	w.mu.Unlock()
+
+	w.log.printf("draining: %d queued canceled", len(queued))
+	for _, worker := range queued {
+		worker.transition(Canceled, worker.finalizeIdle(ErrDraining))
+	}
+	w.touch()
+	if w.unhold != nil {
+		w.unhold()
+	}
+}
+
+// drainTimedOut cancels the Workers still running once the drain timeout
+// passes
+func (w *WorkerSet) drainTimedOut() {
+	w.mu.Lock()
+	w.drainTimer = nil
+	var pending []*Worker
+	for _, worker := range w.members() {
+		if !worker.State.finished() {
+			pending = append(pending, worker)
+		}
+	}
+	if len(pending) == 0 {
+		w.mu.Unlock()
+		return
+	}
+	// held like Begin's hold, Print can't return until the Context is
+	// canceled too
+	w.unfinished++
+	w.mu.Unlock()
+	w.log.printf("drain timed out: %d canceled", len(pending))
+	for _, worker := range pending {
+		worker.transition(Canceled, worker.finalizeIdle(ErrDraining))
+	}
+	w.cancel()
+	w.mu.Lock()
+	w.unfinished--
+	w.settle()
+	w.mu.Unlock()
+}
+
+// isDraining reports whether Drain has been called
+func (w *WorkerSet) isDraining() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.draining
+}
@@ -0,0 +1,97 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDrainQueue(t *testing.T) {
+	ws := New(WithSilent(true), WithConcurrency(1))
+	release := make(chan struct{})
+	var started atomic.Int32
+	running := ws.Go("running", func(ctx context.Context) error {
+		started.Add(1)
+		<-release
+		return nil
+	})
+	queued := []*Worker{
+		ws.Go("queued 1", func(ctx context.Context) error { started.Add(1); return nil }),
+		ws.Go("queued 2", func(ctx context.Context) error { started.Add(1); return nil }),
+	}
+	if !waitFor(func() bool { return started.Load() == 1 }) {
+		t.Fatal("first worker didn't start")
+	}
+
+	ws.Drain()
+	ws.Drain()
+	for _, w := range queued {
+		if v := ws.Snapshot()[w.ID()]; v.State != Canceled || !errors.Is(w.Err(), ErrDraining) {
+			t.Errorf("%s is %v with %v, want canceled with ErrDraining", w.Name, v.State, w.Err())
+		}
+	}
+	late := ws.Go("late", func(ctx context.Context) error { started.Add(1); return nil })
+	if !errors.Is(late.Err(), ErrDraining) {
+		t.Errorf("worker added while draining has error %v", late.Err())
+	}
+
+	close(release)
+	ws.Print(context.Background())
+	if n := started.Load(); n != 1 {
+		t.Errorf("%d workers started, want only the running one", n)
+	}
+	if v := ws.Snapshot()[running.ID()]; v.State != Completed {
+		t.Errorf("running worker is %v after draining, want Completed", v.State)
+	}
+}
+
+func TestDrainHeader(t *testing.T) {
+	ws := New(WithColor(false), WithHeader("build"))
+	ws.Add("a")
+	ws.Add("b")
+	ws.Drain()
+	if _, lines := ws.frame(false, false); !strings.HasSuffix(lines[0], " · draining — 2 tasks remaining") {
+		t.Errorf("header %q", lines[0])
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	ws := New(WithSilent(true), WithDrainTimeout(20*time.Millisecond))
+	w := ws.Go("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ws.Drain()
+	done := make(chan error, 1)
+	go func() { done <- ws.Print(context.Background()) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Print didn't return after the drain timeout")
+	}
+	if v := ws.Snapshot()[0]; v.State != Canceled || !errors.Is(w.Err(), ErrDraining) {
+		t.Errorf("stuck worker is %v with %v, want canceled with ErrDraining", v.State, w.Err())
+	}
+	if w.Context().Err() == nil {
+		t.Error("stuck worker's context wasn't canceled")
+	}
+}
+
+func TestDrainBegin(t *testing.T) {
+	ws, finish := Begin(context.Background(), WithSilent(true))
+	ws.Add("a").Done()
+	ws.Drain()
+	done := make(chan struct{})
+	go func() {
+		finish()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("finish didn't return after Drain")
+	}
+}
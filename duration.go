@@ -0,0 +1,73 @@
+package multistatus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithDurationExcludingPauses has Duration, and the run's time shown in the
+// header and reported in Summary.Duration, leave out the time during which
+// every unfinished Worker was paused with Pause, so the run as a whole was
+// paused
+func WithDurationExcludingPauses(b bool) Option {
+	return func(w *WorkerSet) {
+		w.excludePauses = b
+	}
+}
+
+// Duration returns the wall time of the run, from when its first Worker was
+// added, rather than when Print began, to when its last Worker finished, or
+// until now while any is unfinished. Information rows are left out, and a
+// WorkerSet without Workers has run for 0. With WithDurationExcludingPauses
+// the time the run spent paused is left out too.
+func (w *WorkerSet) Duration() time.Duration {
+	snap := countable(w.Snapshot())
+	d, _ := w.duration(snap, w.now())
+	return d
+}
+
+// duration returns the Duration of the run as of now for snap, along with
+// the time it spent paused
+func (w *WorkerSet) duration(snap []WorkerStatus, now time.Time) (d, paused time.Duration) {
+	var first, last time.Time
+	for _, v := range snap {
+		if first.IsZero() || v.Started.Before(first) {
+			first = v.Started
+		}
+		end := v.Finished
+		if !v.State.finished() {
+			end = now
+		}
+		if end.After(last) {
+			last = end
+		}
+	}
+	if first.IsZero() {
+		return 0, 0
+	}
+	w.mu.Lock()
+	paused = w.halted
+	if !w.haltedAt.IsZero() {
+		paused += span(w.haltedAt, now)
+	}
+	w.mu.Unlock()
+	d = span(first, last)
+	if w.excludePauses {
+		d = max(d-paused, 0)
+	}
+	return d, paused
+}
+
+// noteHalt starts or ends, as of now, the span during which every unfinished
+// Worker is paused. The caller must hold the lock.
+func (w *WorkerSet) noteHalt(now time.Time) {
+	unfinished := atomic.LoadInt64(&w.counts[Pending]) + atomic.LoadInt64(&w.counts[Stopping])
+	halted := w.pausedN > 0 && int64(w.pausedN) >= unfinished
+	switch {
+	case halted && w.haltedAt.IsZero():
+		w.haltedAt = now
+	case !halted && !w.haltedAt.IsZero():
+		w.halted += span(w.haltedAt, now)
+		w.haltedAt = time.Time{}
+	}
+}
@@ -0,0 +1,107 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDurationPauses(t *testing.T) {
+	for _, exclude := range []bool{false, true} {
+		clock := newFakeClock()
+		ws := New(WithSilent(true), WithClock(clock), WithDurationExcludingPauses(exclude),
+			WithHeader("run"), WithElapsed(true))
+		if d := ws.Duration(); d != 0 {
+			t.Errorf("empty WorkerSet ran for %v", d)
+		}
+		ws.AddInfo("info")
+		a, b := ws.Add("a"), ws.Add("b")
+		clock.Advance(5 * time.Second)
+		a.Pause()
+		clock.Advance(5 * time.Second) // b still runs, so the run isn't paused
+		b.Pause()
+		clock.Advance(10 * time.Second)
+		a.Resume()
+		clock.Advance(5 * time.Second)
+		a.Done() // only b is left, and paused
+		clock.Advance(5 * time.Second)
+		b.Fail()
+		clock.Advance(time.Hour)
+
+		want := 30 * time.Second
+		if exclude {
+			want = 15 * time.Second
+		}
+		sum := ws.Summary()
+		if sum.Duration != want || sum.PausedDuration != 15*time.Second {
+			t.Errorf("exclude %v: Summary has Duration %v and PausedDuration %v, want %v and 15s",
+				exclude, sum.Duration, sum.PausedDuration, want)
+		}
+		if d := ws.Duration(); d != want {
+			t.Errorf("exclude %v: Duration is %v, want %v", exclude, d, want)
+		}
+		if header := ws.frameLines(false, true)[0]; !strings.Contains(header, "("+ws.formatDuration(want)+")") {
+			t.Errorf("exclude %v: header %q doesn't show %v", exclude, header, want)
+		}
+	}
+}
+
+func TestDurationRetries(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithRetries(1),
+		WithBackoff(func(int) time.Duration { return 10 * time.Second }))
+	var calls int32
+	ws.Go("task", func(ctx context.Context) error {
+		clock.Advance(2 * time.Second)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errors.New("broke")
+		}
+		return nil
+	})
+	if !waitFor(func() bool { return clock.Waiters() == 1 }) {
+		t.Fatal("Worker didn't back off")
+	}
+	clock.Advance(10 * time.Second)
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	v := ws.Snapshot()[0]
+	if v.Elapsed != 14*time.Second || v.Active != 4*time.Second {
+		t.Errorf("Worker has Elapsed %v and Active %v, want 14s and 4s", v.Elapsed, v.Active)
+	}
+	if len(v.Attempts) != 1 || v.Attempts[0].Elapsed != 2*time.Second {
+		t.Errorf("Attempts are %+v, want one of 2s", v.Attempts)
+	}
+	if d := ws.Duration(); d != 14*time.Second {
+		t.Errorf("Duration is %v, want 14s", d)
+	}
+}
+
+func TestDurationCanceled(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock))
+	running := make(chan struct{})
+	ws.Go("task", func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ws.Add("queued")
+	<-running
+	clock.Advance(5 * time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ws.Print(ctx)
+	clock.Advance(time.Hour)
+	for _, v := range ws.Snapshot() {
+		if v.State != Canceled || v.Elapsed != 5*time.Second {
+			t.Errorf("%s is %v after %v, want Canceled after 5s", v.Name, v.State, v.Elapsed)
+		}
+	}
+	if d := ws.Summary().Duration; d != 5*time.Second {
+		t.Errorf("Duration is %v, want 5s", d)
+	}
+}
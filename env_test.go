@@ -0,0 +1,82 @@
+package multistatus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultsFromEnvJSON(t *testing.T) {
+	t.Setenv("MULTISTATUS_FORMAT", "json")
+	var out bytes.Buffer
+	ws := New(WithDefaultsFromEnv(), WithOutput(&out))
+	ws.Add("build").Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "\n"); n != 1 {
+		t.Fatalf("printed %d lines, want 1:\n%s", n, out.String())
+	}
+	s, err := DecodeSummary(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Completed != 1 || !s.OK {
+		t.Errorf("decoded %+v, want one completed Worker", s)
+	}
+}
+
+func TestDefaultsFromEnvWarnings(t *testing.T) {
+	t.Setenv("MULTISTATUS_FORMAT", "fancy")
+	t.Setenv("MULTISTATUS_REFRESH_MS", "soon")
+	var out, run, debug bytes.Buffer
+	ws := New(WithDefaultsFromEnv(), WithOutput(&out), WithLogWriter(&run), WithDebugLog(&debug))
+	ws.Add("build").Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(run.String(), "environment:") {
+		t.Errorf("run log has environment warnings:\n%s", run.String())
+	}
+	for _, want := range []string{
+		`MULTISTATUS_FORMAT: unsupported format "fancy", ignored`,
+		`MULTISTATUS_REFRESH_MS: invalid interval "soon", ignored`,
+	} {
+		if !strings.Contains(debug.String(), want) {
+			t.Errorf("debug log lacks %q:\n%s", want, debug.String())
+		}
+	}
+}
+
+func TestDefaultsFromEnvExplicit(t *testing.T) {
+	t.Setenv("ACCESSIBLE", "1")
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("MULTISTATUS_FORMAT", "json")
+	t.Setenv("MULTISTATUS_REFRESH_MS", "50")
+	explicit := []Option{WithAccessible(false), WithColor(true), WithTTY(true), WithRefreshInterval(10 * time.Millisecond)}
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		env  bool
+	}{
+		{"alone", []Option{WithDefaultsFromEnv()}, true},
+		{"explicit before", append(append([]Option(nil), explicit...), WithDefaultsFromEnv()), false},
+		{"explicit after", append([]Option{WithDefaultsFromEnv()}, explicit...), false},
+	} {
+		ws := New(tc.opts...)
+		got := fmt.Sprint(ws.accessible, ws.noColor, ws.jsonOutput, ws.refresh)
+		want := "false false false 10ms"
+		if tc.env {
+			want = "true true true 50ms"
+		}
+		if got != want {
+			t.Errorf("%s: accessible, no color, JSON and refresh are %s, want %s", tc.name, got, want)
+		}
+		if !tc.env && (ws.tty == nil || !*ws.tty) {
+			t.Errorf("%s: MULTISTATUS_FORMAT overrode WithTTY", tc.name)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package multistatus
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WithAdvancedEscapes enables escapes beyond cursor movement and colors,
+// which some terminals and multiplexers don't understand and print as
+// garbage. With them the terminal's title follows the progress of the
+// WorkerSet. Inside tmux or screen the escapes are wrapped to pass through
+// to the outer terminal, and frames are drawn with synchronized output so
+// terminals that support it never show one half drawn. They are disabled by
+// default.
+func WithAdvancedEscapes(b bool) Option {
+	return func(w *WorkerSet) {
+		w.advancedEscapes = b
+	}
+}
+
+// escapeCaps records which escapes may be written to the terminal, beyond
+// the cursor movement and colors written everywhere
+type escapeCaps struct {
+	// osc allows operating system commands, such as setting the title
+	osc bool
+
+	// sync allows synchronized output, which has the terminal hold a
+	// frame until it has been written in full
+	sync bool
+
+	// mux is the multiplexer the terminal runs inside, "tmux" or
+	// "screen", whose passthrough OSC sequences must be wrapped in
+	mux string
+}
+
+// detectCaps decides which escapes to use from the environment, as read by
+// getenv, and whether advanced escapes were enabled
+func detectCaps(getenv func(string) string, advanced bool) escapeCaps {
+	c := escapeCaps{osc: advanced, sync: advanced}
+	switch {
+	case getenv("TMUX") != "":
+		c.mux = "tmux"
+	case getenv("STY") != "", strings.HasPrefix(getenv("TERM"), "screen"):
+		c.mux = "screen"
+	}
+	return c
+}
+
+// Escape sequences and their introducers. Every escape is written through an
+// escWriter or built from these.
+const (
+	esc = "\033"
+	csi = esc + "["
+	osc = esc + "]"
+	dcs = esc + "P"
+	st  = esc + "\\"
+
+	sgrReset    = csi + "0m"
+	cursorQuery = csi + "6n"
+)
+
+// An escWriter buffers text and escape sequences for the terminal, writing
+// each escape only if its capabilities and color level allow it
+type escWriter struct {
+	bytes.Buffer
+	caps  escapeCaps
+	level ColorLevel
+}
+
+// CursorUp moves the cursor up n lines
+func (e *escWriter) CursorUp(n int) {
+	e.WriteString(strings.Repeat(csi+"A", n))
+}
+
+// CursorDown moves the cursor down n lines
+func (e *escWriter) CursorDown(n int) {
+	e.WriteString(strings.Repeat(csi+"B", n))
+}
+
+// EraseLine erases the line the cursor is on
+func (e *escWriter) EraseLine() {
+	e.WriteString(csi + "2K")
+}
+
+// HideCursor hides the cursor
+func (e *escWriter) HideCursor() {
+	e.WriteString(csi + "?25l")
+}
+
+// ShowCursor shows the cursor
+func (e *escWriter) ShowCursor() {
+	e.WriteString(csi + "?25h")
+}
+
+// SetColor draws what follows in c, reporting whether the color level allowed
+// it
+func (e *escWriter) SetColor(c Color) bool {
+	sgr := c.sgr(e.level)
+	if sgr == "" {
+		return false
+	}
+	e.WriteString(csi + sgr + "m")
+	return true
+}
+
+// ResetColor draws what follows without colors or other attributes
+func (e *escWriter) ResetColor() {
+	e.WriteString(sgrReset)
+}
+
+// SyncBegin has the terminal hold what follows until SyncEnd, if it supports
+// synchronized output
+func (e *escWriter) SyncBegin() {
+	if e.caps.sync {
+		e.WriteString(csi + "?2026h")
+	}
+}
+
+// SyncEnd has the terminal draw what it held since SyncBegin
+func (e *escWriter) SyncEnd() {
+	if e.caps.sync {
+		e.WriteString(csi + "?2026l")
+	}
+}
+
+// OSC writes the operating system command with the given payload, wrapped
+// for the multiplexer if needed, if OSC sequences are enabled. Control
+// characters are dropped from the payload, as a BEL, ESC or C1 control in
+// user supplied text would otherwise end the command early and let the rest
+// through to the terminal.
+func (e *escWriter) OSC(payload string) {
+	if !e.caps.osc {
+		return
+	}
+	seq := osc + stripControl(payload) + "\a"
+	switch e.caps.mux {
+	case "tmux":
+		e.WriteString(dcs + "tmux;" + strings.ReplaceAll(seq, esc, esc+esc) + st)
+	case "screen":
+		e.WriteString(dcs + seq + st)
+	default:
+		e.WriteString(seq)
+	}
+}
+
+// Title sets the terminal's title to s
+func (e *escWriter) Title(s string) {
+	e.OSC("2;" + s)
+}
+
+// titleSeq returns the sequence setting the terminal's title to s, or an
+// empty string if OSC sequences are disabled
+func (c escapeCaps) titleSeq(s string) string {
+	e := escWriter{caps: c}
+	e.Title(s)
+	return e.String()
+}
+
+// caps returns the escapes the WorkerSet may use
+func (w *WorkerSet) caps() escapeCaps {
+	return detectCaps(os.Getenv, w.advancedEscapes)
+}
+
+// title returns the sequence setting the terminal's title to the
+// WorkerSet's progress, or an empty string if that's disabled
+func (w *WorkerSet) title() string {
+	if !w.advancedEscapes {
+		return ""
+	}
+	counts, total := w.tally(w.Snapshot())
+	done := counts[Completed] + counts[Failed] + counts[Canceled]
+	s := fmt.Sprintf("%d/%d", done, total)
+	if t := w.header; t != "" {
+		s = t + " " + s
+	} else if w.name != "" {
+		s = w.name + " " + s
+	}
+	return w.caps().titleSeq(s)
+}
@@ -0,0 +1,260 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ownEscapes matches the escapes the package writes itself: CSI sequences,
+// and OSC sequences whose payload is free of control characters
+var ownEscapes = regexp.MustCompile("\x1b\\[[0-9;?]*[A-Za-z]|\x1b\\][^\x00-\x1f\x7f\u0080-\u009f]*\a")
+
+// strayControl returns the first control character left in s once the
+// package's own escapes are removed, reporting whether there was one
+func strayControl(s string) (rune, bool) {
+	for _, r := range ownEscapes.ReplaceAllString(s, "") {
+		if r == '\x1b' || r == '\a' || (r >= 0x80 && r < 0xa0) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// hostileSeeds are names and status text trying to end an escape early
+var hostileSeeds = []string{
+	"plain",
+	"\x1b]2;pwned\a",
+	"\x1b]8;;http://evil\x1b\\link\x1b]8;;\x1b\\",
+	"bell\a in the middle",
+	"csi \x9b31m and \x9d0;title\x9c",
+	"\x1b[2J\x1b[H cleared",
+	"\x1bPtmux;\x1b\x1b]2;x\a\x1b\\",
+	"\xff\xfe invalid \x1b",
+}
+
+func FuzzOSC(f *testing.F) {
+	for _, s := range hostileSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, payload string) {
+		for _, mux := range []string{"", "tmux", "screen"} {
+			e := escWriter{caps: escapeCaps{osc: true, mux: mux}}
+			e.Title(payload)
+			seq := e.String()
+			switch mux {
+			case "tmux":
+				seq = strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(seq, dcs+"tmux;"), st), esc+esc, esc)
+			case "screen":
+				seq = strings.TrimSuffix(strings.TrimPrefix(seq, dcs), st)
+			}
+			if !ownEscapes.MatchString(seq) || ownEscapes.FindString(seq) != seq {
+				t.Fatalf("%s: payload %q escaped the OSC sequence: %q", mux, payload, e.String())
+			}
+		}
+	})
+}
+
+func FuzzUserText(f *testing.F) {
+	// FuzzOSC covers the wrapping for multiplexers
+	for _, k := range []string{"TMUX", "STY", "TERM"} {
+		f.Setenv(k, "")
+	}
+	for i, s := range hostileSeeds {
+		f.Add(s, hostileSeeds[(i+1)%len(hostileSeeds)], hostileSeeds[(i+2)%len(hostileSeeds)])
+	}
+	f.Fuzz(func(t *testing.T, name, status, text string) {
+		check := func(mode, out string) {
+			if r, ok := strayControl(out); ok {
+				t.Fatalf("%s: %q reached the output from name %q, status %q, text %q:\n%q", mode, r, name, status, text, out)
+			}
+		}
+		build := func(opts ...Option) (*WorkerSet, *Worker) {
+			ws := New(append([]Option{WithAdvancedEscapes(true), WithHeader(name), WithColorLevel(ColorTrue)}, opts...)...)
+			done := ws.Add(name)
+			done.SetStatus(status)
+			done.AddNote(text)
+			done.Done()
+			failed := ws.Add(status)
+			failed.SetIcon(text)
+			failed.FailWith(errors.New(text))
+			pending := ws.Add(text)
+			pending.SetStatus(status)
+			return ws, pending
+		}
+
+		ws, _ := build()
+		for _, format := range []ScreenshotFormat{ScreenshotANSI, ScreenshotHTML} {
+			shot, err := ws.Screenshot(80, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			check("screenshot", shot)
+		}
+		check("title", ws.title())
+		check("final frame", strings.Join(ws.frameLines(true, true), "\n"))
+
+		for _, tty := range []bool{false, true} {
+			var out, rec syncBuf
+			ws, pending := build(WithOutput(&out), WithTTY(tty), WithSize(80, 24), WithRecording(&rec),
+				WithRefreshInterval(time.Millisecond), WithSharedDisplay(false))
+			time.AfterFunc(5*time.Millisecond, pending.Done)
+			if err := ws.Print(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			check("print", out.String())
+			if tty {
+				_, events := castEvents(t, rec.String())
+				for _, e := range events {
+					check("recording", e[2].(string))
+				}
+			}
+		}
+	})
+}
+
+func TestDetectCaps(t *testing.T) {
+	for _, tc := range []struct {
+		env      map[string]string
+		advanced bool
+		want     escapeCaps
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, false, escapeCaps{}},
+		{map[string]string{"TERM": "xterm-256color"}, true, escapeCaps{osc: true, sync: true}},
+		{map[string]string{"TERM": "screen-256color", "TMUX": "/tmp/tmux-1000/default,1,0"}, false, escapeCaps{mux: "tmux"}},
+		{map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux-1000/default,1,0"}, true, escapeCaps{osc: true, sync: true, mux: "tmux"}},
+		{map[string]string{"TERM": "screen", "STY": "1234.pts-0.host"}, false, escapeCaps{mux: "screen"}},
+		{map[string]string{"TERM": "xterm", "STY": "1234.pts-0.host"}, true, escapeCaps{osc: true, sync: true, mux: "screen"}},
+		{map[string]string{"TERM": "screen.xterm-256color"}, true, escapeCaps{osc: true, sync: true, mux: "screen"}},
+		{map[string]string{}, false, escapeCaps{}},
+	} {
+		getenv := func(k string) string { return tc.env[k] }
+		if got := detectCaps(getenv, tc.advanced); got != tc.want {
+			t.Errorf("%v advanced %v: got %+v, want %+v", tc.env, tc.advanced, got, tc.want)
+		}
+	}
+}
+
+func TestTitleSeq(t *testing.T) {
+	for _, tc := range []struct {
+		caps escapeCaps
+		want string
+	}{
+		{escapeCaps{}, ""},
+		{escapeCaps{mux: "tmux"}, ""},
+		{escapeCaps{osc: true}, "\x1b]2;build 3/4\a"},
+		{escapeCaps{osc: true, mux: "tmux"}, "\x1bPtmux;\x1b\x1b]2;build 3/4\a\x1b\\"},
+		{escapeCaps{osc: true, mux: "screen"}, "\x1bP\x1b]2;build 3/4\a\x1b\\"},
+	} {
+		if got := tc.caps.titleSeq("build 3/4"); got != tc.want {
+			t.Errorf("%+v: got %q, want %q", tc.caps, got, tc.want)
+		}
+	}
+}
+
+func TestAdvancedEscapesOff(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	var buf syncBuf
+	ws := New(WithOutput(&buf), WithTTY(true), WithHeader("build"))
+	ws.Add("a").Done()
+	ws.Print(context.Background())
+	// only cursor movement, erasing and colors by default
+	for _, seq := range regexp.MustCompile("\x1b(\\[[0-9;?]*[A-Za-z]|.)").FindAllString(buf.String(), -1) {
+		switch seq[len(seq)-1] {
+		case 'A', 'B', 'K', 'm', 'l', 'h':
+			if strings.Contains(seq, "2026") {
+				t.Errorf("synchronized output without WithAdvancedEscapes: %q", seq)
+			}
+		default:
+			t.Errorf("unexpected escape %q", seq)
+		}
+	}
+}
+
+func TestAdvancedEscapesTmux(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	var buf syncBuf
+	ws := New(WithOutput(&buf), WithTTY(true), WithHeader("build"), WithAdvancedEscapes(true))
+	w := ws.Add("a")
+	time.AfterFunc(20*time.Millisecond, w.Done)
+	ws.Print(context.Background())
+	if want := "\x1bPtmux;\x1b\x1b]2;build 0/1\a\x1b\\"; !strings.Contains(buf.String(), want) {
+		t.Errorf("no wrapped title %q in %q", want, buf.String())
+	}
+}
+
+func TestEscWriterCaps(t *testing.T) {
+	methods := []struct {
+		name string
+		call func(*escWriter)
+		want string // with every capability
+	}{
+		{"CursorUp", func(e *escWriter) { e.CursorUp(2) }, "\x1b[A\x1b[A"},
+		{"CursorUp zero", func(e *escWriter) { e.CursorUp(0) }, ""},
+		{"CursorDown", func(e *escWriter) { e.CursorDown(1) }, "\x1b[B"},
+		{"EraseLine", (*escWriter).EraseLine, "\x1b[2K"},
+		{"HideCursor", (*escWriter).HideCursor, "\x1b[?25l"},
+		{"ShowCursor", (*escWriter).ShowCursor, "\x1b[?25h"},
+		{"ResetColor", (*escWriter).ResetColor, "\x1b[0m"},
+		{"SyncBegin", (*escWriter).SyncBegin, "\x1b[?2026h"},
+		{"SyncEnd", (*escWriter).SyncEnd, "\x1b[?2026l"},
+		{"Title", func(e *escWriter) { e.Title("x") }, "\x1b]2;x\a"},
+	}
+	for _, caps := range []escapeCaps{{}, {osc: true}, {sync: true}, {osc: true, sync: true}} {
+		for _, m := range methods {
+			want := m.want
+			switch {
+			case strings.HasPrefix(m.name, "Sync") && !caps.sync, m.name == "Title" && !caps.osc:
+				want = ""
+			}
+			e := escWriter{caps: caps}
+			m.call(&e)
+			if got := e.String(); got != want {
+				t.Errorf("%s with %+v: got %q, want %q", m.name, caps, got, want)
+			}
+		}
+	}
+}
+
+func TestEscWriterSetColor(t *testing.T) {
+	for level, want := range []string{"", "\x1b[33m", "\x1b[38;5;208m", "\x1b[38;2;255;135;0m"} {
+		e := escWriter{level: ColorLevel(level)}
+		if ok := e.SetColor(RGB(255, 135, 0)); ok != (want != "") || e.String() != want {
+			t.Errorf("level %d: got %q and %v, want %q", level, e.String(), ok, want)
+		}
+		e.Reset()
+		if e.SetColor(DefaultColor) || e.Len() != 0 {
+			t.Errorf("level %d: default color wrote %q", level, e.String())
+		}
+	}
+}
+
+// TestNoRawEscapes keeps every escape behind the escWriter
+func TestNoRawEscapes(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := regexp.MustCompile(`\\(033|x1[bB]|u001[bB])`)
+	for _, f := range files {
+		if f == "escapes.go" || strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, line := range strings.Split(string(b), "\n") {
+			if raw.MatchString(line) {
+				t.Errorf("%s:%d: raw escape outside escapes.go: %s", f, i+1, strings.TrimSpace(line))
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package multistatus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// eventQueueSize bounds the number of undelivered Events held per WorkerSet
+const eventQueueSize = 1024
+
+// EventType distinguishes the kinds of Event
+type EventType int
+
+// Available EventTypes
+const (
+	// EventTransition is sent when a Worker changes state
+	EventTransition EventType = iota
+	// EventRemoved is sent when a Worker is removed from its WorkerSet;
+	// its From and To are both the Worker's final state
+	EventRemoved
+	// EventStatus is sent when a Worker's status text changes; its From
+	// and To are both the Worker's current state
+	EventStatus
+)
+
+// Event describes a change to a Worker
+type Event struct {
+	// Seq numbers the Events of a WorkerSet from 1 in the order they are
+	// delivered, so a gap means Events were dropped. Times never decrease
+	// with Seq, and the Workers stopped together when Print is canceled,
+	// which share a Time, are numbered in order of ID.
+	Seq uint64
+
+	Type   EventType
+	Worker *Worker
+	ID     int64
+	From   WorkerState
+	To     WorkerState
+	Status string
+	Time   time.Time
+}
+
+// eventQueue delivers Events to subscribers from a single goroutine so that
+// Done and Fail never wait on subscriber code.
+type eventQueue struct {
+	mu       sync.Mutex
+	subs     []func(Event)
+	ch       chan Event
+	draining int32
+	dropped  int64
+
+	// seq is the Seq of the last Event queued, guarded by mu
+	seq uint64
+
+	// closed is set by close, after which Events are dropped, and running
+	// counts the delivery goroutines close waits for
+	closed  bool
+	running sync.WaitGroup
+}
+
+// Subscribe registers fn to be called with every subsequent Event. Events are
+// delivered one at a time from a single goroutine, in the order of their Seq,
+// and never block the Workers they describe: if subscribers fall more than
+// eventQueueSize Events behind, further Events are dropped and counted by
+// DroppedEvents.
+func (w *WorkerSet) Subscribe(fn func(Event)) {
+	q := &w.events
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.ch == nil {
+		q.ch = make(chan Event, eventQueueSize)
+	}
+	q.subs = append(q.subs, fn)
+}
+
+// DroppedEvents returns the number of Events discarded because the
+// subscriber queue was full.
+func (w *WorkerSet) DroppedEvents() int64 {
+	return atomic.LoadInt64(&w.events.dropped)
+}
+
+// publish numbers e and queues it for delivery without blocking. The caller
+// must hold w.mu and have taken e's Time under it, so Events are numbered in
+// the order of the changes they describe and their Times never go backwards.
+func (w *WorkerSet) publish(e Event) {
+	q := &w.events
+	q.mu.Lock()
+	if q.ch == nil || q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.seq++
+	e.Seq = q.seq
+	select {
+	case q.ch <- e:
+	default:
+		atomic.AddInt64(&q.dropped, 1)
+	}
+	q.mu.Unlock()
+	q.kick()
+}
+
+// kick starts the delivery goroutine unless one is already running. The
+// goroutine exits once the queue is empty, re-checking after it releases the
+// flag so no Event is stranded.
+func (q *eventQueue) kick() {
+	if !atomic.CompareAndSwapInt32(&q.draining, 0, 1) {
+		return
+	}
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		atomic.StoreInt32(&q.draining, 0)
+		return
+	}
+	q.running.Add(1)
+	q.mu.Unlock()
+	go func() {
+		defer q.running.Done()
+		for {
+			q.drain()
+			atomic.StoreInt32(&q.draining, 0)
+			if len(q.ch) == 0 || !atomic.CompareAndSwapInt32(&q.draining, 0, 1) {
+				return
+			}
+		}
+	}()
+}
+
+// close stops delivering Events once the delivery goroutine, if running,
+// has exited
+func (q *eventQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.running.Wait()
+}
+
+func (q *eventQueue) drain() {
+	for {
+		select {
+		case e := <-q.ch:
+			q.mu.Lock()
+			subs := q.subs
+			q.mu.Unlock()
+			for _, fn := range subs {
+				fn(e)
+			}
+		default:
+			return
+		}
+	}
+}
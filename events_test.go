@@ -0,0 +1,137 @@
+package multistatus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventOrder(t *testing.T) {
+	const n = 2000
+	ws := New(WithSilent(true))
+	var mu sync.Mutex
+	var events []Event
+	ws.Subscribe(func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	})
+	workers := ws.AddBatch(make([]string, n))
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w *Worker) {
+			defer wg.Done()
+			w.SetStatus("finishing")
+			w.Done()
+		}(w)
+	}
+	wg.Wait()
+	ws.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i := 1; i < len(events); i++ {
+		prev, e := events[i-1], events[i]
+		if e.Seq <= prev.Seq {
+			t.Fatalf("Seq %d delivered after %d", e.Seq, prev.Seq)
+		}
+		if e.Time.Before(prev.Time) {
+			t.Fatalf("Seq %d at %v is before Seq %d at %v", e.Seq, e.Time, prev.Seq, prev.Time)
+		}
+	}
+	if got := int64(len(events)) + ws.DroppedEvents(); got != 2*n {
+		t.Errorf("delivered and dropped %d Events, want %d", got, 2*n)
+	}
+}
+
+func TestEventOrderStopping(t *testing.T) {
+	ws := New(WithSilent(true), WithStopTimeout(time.Second))
+	var mu sync.Mutex
+	var stopped []Event
+	ws.Subscribe(func(e Event) {
+		if e.To != Stopping {
+			return
+		}
+		mu.Lock()
+		stopped = append(stopped, e)
+		mu.Unlock()
+	})
+	for i := 0; i < 50; i++ {
+		ws.Go("job", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	ws.Print(ctx)
+	ws.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(stopped) != 50 {
+		t.Fatalf("%d Workers stopped, want 50", len(stopped))
+	}
+	for i := 1; i < len(stopped); i++ {
+		prev, e := stopped[i-1], stopped[i]
+		if !e.Time.Equal(prev.Time) || e.ID <= prev.ID {
+			t.Fatalf("stopping Events out of order: #%d at %v after #%d at %v", e.ID, e.Time, prev.ID, prev.Time)
+		}
+	}
+}
+
+func TestEventQueueBounded(t *testing.T) {
+	const n = 10000
+	ws := New(WithSilent(true))
+	release := make(chan struct{})
+	ws.Subscribe(func(Event) { <-release })
+	for _, w := range ws.AddBatch(make([]string, n)) {
+		w.Done()
+	}
+	// at most the queue and the Event being delivered can be held
+	if dropped := ws.DroppedEvents(); dropped < n-eventQueueSize-1 {
+		t.Errorf("%d Events dropped, want at least %d", dropped, n-eventQueueSize-1)
+	}
+	close(release)
+	ws.Close()
+}
+
+// BenchmarkDone finishes 10k Workers as fast as possible, with a subscriber
+// slower than Done, reporting the time each Done took
+func BenchmarkDone(b *testing.B) {
+	const n = 10000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		ws := New(WithSilent(true))
+		ws.Subscribe(func(Event) { time.Sleep(time.Microsecond) })
+		workers := ws.AddBatch(make([]string, n))
+		b.StartTimer()
+		for _, w := range workers {
+			w.Done()
+		}
+		b.StopTimer()
+		ws.Close()
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/Done")
+}
+
+// BenchmarkDoneParallel finishes Workers from every CPU at once
+func BenchmarkDoneParallel(b *testing.B) {
+	ws := New(WithSilent(true))
+	ws.Subscribe(func(Event) {})
+	workers := ws.AddBatch(make([]string, b.N))
+	var next int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			workers[atomic.AddInt64(&next, 1)-1].Done()
+		}
+	})
+	b.StopTimer()
+	ws.Close()
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func main() {
+	ws := ms.New()
+
+	for i := 0; i < 10; i++ {
+		cmd := exec.Command("sleep", fmt.Sprint(i%4+1))
+		if i%5 == 3 {
+			cmd = exec.Command("sh", "-c", "sleep 2; echo 'something went wrong' >&2; false")
+		}
+		ws.Command(fmt.Sprintf("Command #%d", i), cmd)
+	}
+
+	ws.Print(context.Background())
+
+	for _, w := range ws.Workers {
+		if err := w.Err(); err != nil {
+			fmt.Printf("%s: %v\n", w.Name, err)
+		}
+	}
+}
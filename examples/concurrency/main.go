@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func main() {
+	// at most 3 Workers run at once; the rest show as waiting
+	ws := ms.New(ms.WithConcurrency(3), ms.WithHeader("Downloading"))
+
+	for i := 0; i < 12; i++ {
+		w := ws.Go(fmt.Sprintf("file-%02d.tar.gz", i), func(ctx context.Context) error {
+			select {
+			case <-time.After(time.Millisecond * time.Duration(500+rand.Intn(2000))):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		// later files are started first
+		w.SetPriority(i)
+	}
+
+	ws.Print(context.Background())
+}
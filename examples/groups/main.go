@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func main() {
+	// the header rolls up the progress of each group
+	ws := ms.New(ms.WithHeader("Release"))
+
+	groups := map[string]int{"build": 3, "test": 5, "package": 2}
+	for _, group := range []string{"build", "test", "package"} {
+		for i := 0; i < groups[group]; i++ {
+			w := ws.Go(fmt.Sprintf("%s #%d", group, i), func(ctx context.Context) error {
+				time.Sleep(time.Millisecond * time.Duration(rand.Intn(4000)))
+				return nil
+			})
+			w.SetGroup(group)
+		}
+	}
+
+	ws.Print(context.Background())
+}
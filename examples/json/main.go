@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func main() {
+	ws := ms.New(ms.WithSnapshotHistory(true))
+
+	for i := 0; i < 5; i++ {
+		ws.Go(fmt.Sprintf("Task #%d", i), func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * time.Duration(rand.Intn(2000)))
+			if rand.Intn(3) == 0 {
+				return errors.New("something went wrong")
+			}
+			return nil
+		})
+	}
+	ws.Print(context.Background())
+
+	// the WorkerSet encodes as its Summary, with each Worker's history
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ws); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -9,10 +9,11 @@ import (
 	"time"
 
 	ms "github.com/zikes/multistatus"
+	"github.com/zikes/multistatus/terminal"
 )
 
 func main() {
-	ws := ms.New()
+	ws := ms.New(ms.WithTerminal(terminal.New()))
 
 	for i := 0; i < 10; i++ {
 		w := ws.Add(fmt.Sprintf("Task #%d", i))
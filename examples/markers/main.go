@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+const timeout = 6 * time.Second
+
+func main() {
+	ws := ms.New(ms.WithElapsed(true), ms.WithMarkerFunc(func(v ms.WorkerStatus, frame int) string {
+		// flash a clock for Workers close to their timeout
+		if v.State == ms.Pending && v.Elapsed > timeout*2/3 {
+			if frame%10 < 5 {
+				return "⏰"
+			}
+			return " "
+		}
+		return ""
+	}))
+
+	for i := 0; i < 10; i++ {
+		ws.Go(fmt.Sprintf("Task #%d", i), func(ctx context.Context) error {
+			select {
+			case <-time.After(time.Millisecond * time.Duration(rand.Intn(8000))):
+				return nil
+			case <-time.After(timeout):
+				return fmt.Errorf("timed out after %s", timeout)
+			}
+		})
+	}
+
+	ws.Print(context.Background())
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func work(ctx context.Context) error {
+	select {
+	case <-time.After(time.Millisecond * time.Duration(rand.Intn(2000))):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if rand.Intn(10) == 0 {
+		return errors.New("something went wrong")
+	}
+	return nil
+}
+
+func main() {
+	p := ms.NewPipeline()
+	for _, name := range []string{"build", "test", "package"} {
+		ws := ms.New()
+		for i := 0; i < 4; i++ {
+			ws.Go(fmt.Sprintf("%s #%d", name, i), work)
+		}
+		p.AddStage(name, ws)
+	}
+
+	sum, err := p.Print(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if sum.Failed > 0 {
+		os.Exit(1)
+	}
+}
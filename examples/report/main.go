@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+var requestID = regexp.MustCompile(`req-[0-9]+`)
+
+func main() {
+	// a report that only changes when the outcome of a run does, for
+	// keeping alongside the code
+	ws := ms.New(
+		ms.WithQuiet(true),
+		ms.WithReportRounding(time.Second),
+		ms.WithReportRedact(func(s string) string {
+			return requestID.ReplaceAllString(s, "req-…")
+		}),
+	)
+
+	for _, name := range []string{"web", "api", "db", "cache", "queue"} {
+		name := name
+		ws.Go(name, func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * time.Duration(rand.Intn(1500)))
+			if name == "db" {
+				return fmt.Errorf("request req-%d: %w", rand.Intn(100000), errors.New("connection refused"))
+			}
+			return nil
+		})
+	}
+	ws.Print(context.Background())
+
+	if err := ws.WriteStableReport(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
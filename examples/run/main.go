@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func main() {
+	var tasks []ms.Task
+	for i := 0; i < 10; i++ {
+		tasks = append(tasks, ms.Task{
+			Name: fmt.Sprintf("Task #%d", i),
+			Fn: func(ctx context.Context) error {
+				select {
+				case <-time.After(time.Millisecond * time.Duration(rand.Intn(8000))):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				if rand.Intn(5) == 1 {
+					return errors.New("something went wrong")
+				}
+				return nil
+			},
+		})
+	}
+
+	sum, err := ms.Run(context.Background(), tasks, ms.WithConcurrency(4))
+	fmt.Printf("%d completed, %d failed\n", sum.Completed, sum.Failed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func main() {
+	// Workers may report every item they process; the display only
+	// picks up the latest status every 200ms
+	ws := ms.New(ms.WithStatusThrottle(200*time.Millisecond), ms.WithElapsed(true))
+
+	for i := 0; i < 5; i++ {
+		w := ws.Add(fmt.Sprintf("Importer #%d", i))
+		go func() {
+			items := 500 + rand.Intn(1500)
+			for n := 1; n <= items; n++ {
+				time.Sleep(2 * time.Millisecond)
+				w.SetStatus(fmt.Sprintf("%d/%d rows", n, items))
+			}
+			w.Done()
+		}()
+	}
+
+	ws.Print(context.Background())
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	ms "github.com/zikes/multistatus"
+)
+
+func main() {
+	ws := ms.New(ms.WithName("batch"))
+
+	// Serve the status page ourselves, rather than with ServeStatus, so
+	// it stays up to show the final summary after Print returns
+	go func() {
+		log.Fatal(http.ListenAndServe("localhost:8080", ws.Handler()))
+	}()
+	fmt.Println("watch at http://localhost:8080/")
+
+	for i := 0; i < 8; i++ {
+		ws.Go(fmt.Sprintf("Task #%d", i), func(ctx context.Context) error {
+			select {
+			case <-time.After(time.Duration(5+rand.Intn(20)) * time.Second):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if rand.Intn(4) == 0 {
+				return errors.New("something went wrong")
+			}
+			return nil
+		})
+	}
+	ws.Print(context.Background())
+
+	fmt.Println("finished; serving the final status for another minute")
+	time.Sleep(time.Minute)
+}
@@ -0,0 +1,22 @@
+package multistatus
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestExamplesBuild compiles every program under examples so they can't fall
+// behind the API
+func TestExamplesBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds every example")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	out, err := exec.Command(gobin, "build", "-o", t.TempDir(), "./examples/...").CombinedOutput()
+	if err != nil {
+		t.Fatalf("building examples: %v\n%s", err, out)
+	}
+}
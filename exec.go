@@ -0,0 +1,164 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// stderrTailLines is the number of lines of stderr included in the error of a
+// failed Command
+const stderrTailLines = 5
+
+// ErrFinished is returned by Worker.Do when the Worker has already finished
+var ErrFinished = errors.New("multistatus: worker has already finished")
+
+// Go adds a Worker and runs fn in a new goroutine, marking the Worker
+// Completed if fn returns nil and Failed with the returned error otherwise.
+// A panic in fn fails the Worker rather than crashing the program. The
+// context passed to fn is canceled if Print is canceled.
+//
+// If the WorkerSet has a concurrency limit, fn may not start right away; see
+// WithConcurrency.
+func (w *WorkerSet) Go(name string, fn func(ctx context.Context) error) *Worker {
+	worker := w.Add(name)
+	w.schedule(worker, fn)
+	return worker
+}
+
+// Do runs fn in the current goroutine as the Worker's work, for Workers whose
+// goroutine already exists, such as one consuming from a channel. Like Go, it
+// times fn from when Do is called, retries it as allowed by WithRetries,
+// turns a panic into a failure, and marks the Worker Completed or Failed. It
+// returns the error the Worker failed with, or ErrFinished without calling
+// fn if the Worker has already finished.
+func (w *Worker) Do(fn func() error) error {
+	ws := w.parent
+	ws.mu.Lock()
+	if w.State.finished() {
+		ws.mu.Unlock()
+		return ErrFinished
+	}
+	w.started = ws.now()
+	w.executing = true
+	ws.mu.Unlock()
+	ws.touch()
+	return w.run(func(context.Context) error { return fn() })
+}
+
+// run calls fn, retrying it as allowed by WithRetries, then calls the
+// Worker's finalizers and reports their outcome on the Worker, returning the
+// error it failed with
+func (w *Worker) run(fn func(ctx context.Context) error) error {
+	defer w.setExecuting(false)
+	var err error
+	for {
+		start := w.beginAttempt()
+		err = w.attempt(fn)
+		w.endAttempt(start)
+		if err == nil || !w.retry(err, start) {
+			break
+		}
+		if !w.backOff(w.attemptCount()) {
+			err = w.parent.ctx.Err()
+			break
+		}
+	}
+	err = w.finalize(err)
+	if err != nil {
+		w.FailWith(err)
+	} else {
+		w.Done()
+	}
+	return err
+}
+
+// setExecuting notes whether the Worker's function is being run
+func (w *Worker) setExecuting(b bool) {
+	w.parent.mu.Lock()
+	w.executing = b
+	w.parent.mu.Unlock()
+}
+
+// Command adds a Worker that runs cmd, which must not have been started. The
+// command's output is captured by the Worker, see Worker.Output, and the
+// command is killed if Print is canceled. The Worker fails if the command
+// exits with a non-zero status, with an error including the end of its
+// stderr. Like Go, Command respects the concurrency limit.
+//
+// An exec.Cmd can only be started once, so an attempt retried as allowed by
+// WithRetries runs a copy of cmd with the same path, arguments, environment,
+// directory and files. Its stdin is shared with the earlier attempts, and the
+// context of exec.CommandContext isn't carried over; the copy is killed when
+// Print is canceled like cmd is.
+func (w *WorkerSet) Command(name string, cmd *exec.Cmd) *Worker {
+	worker := w.Add(name)
+	stdout, stderr := cmd.Stdout, cmd.Stderr
+	started := false
+	w.schedule(worker, func(ctx context.Context) error {
+		c := cmd
+		if started {
+			c = copyCmd(cmd)
+		}
+		started = true
+		return runCommand(ctx, worker, c, stdout, stderr)
+	})
+	return worker
+}
+
+// copyCmd returns an unstarted copy of cmd for another attempt
+func copyCmd(cmd *exec.Cmd) *exec.Cmd {
+	return &exec.Cmd{
+		Path:        cmd.Path,
+		Args:        cmd.Args,
+		Env:         cmd.Env,
+		Dir:         cmd.Dir,
+		Stdin:       cmd.Stdin,
+		ExtraFiles:  cmd.ExtraFiles,
+		SysProcAttr: cmd.SysProcAttr,
+		WaitDelay:   cmd.WaitDelay,
+		Err:         cmd.Err,
+	}
+}
+
+// runCommand starts cmd with its output going to the Worker as well as
+// stdout and stderr, if they aren't nil, and waits for it to exit
+func runCommand(ctx context.Context, w *Worker, cmd *exec.Cmd, stdout, stderr io.Writer) error {
+	tail := &output{}
+	cmd.Stdout = teeWriter(stdout, w)
+	cmd.Stderr = teeWriter(stderr, io.MultiWriter(w, tail))
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	waited := make(chan error, 1)
+	go func() { waited <- cmd.Wait() }()
+	var err error
+	select {
+	case err = <-waited:
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-waited
+		return ctx.Err()
+	}
+
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		if lines := tail.tail(stderrTailLines); len(lines) > 0 {
+			return fmt.Errorf("exit status %d: %s", exit.ExitCode(), strings.Join(lines, "\n"))
+		}
+		return fmt.Errorf("exit status %d", exit.ExitCode())
+	}
+	return err
+}
+
+// teeWriter writes to both w and capture, or just capture if w is nil
+func teeWriter(w, capture io.Writer) io.Writer {
+	if w == nil {
+		return capture
+	}
+	return io.MultiWriter(w, capture)
+}
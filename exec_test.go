@@ -0,0 +1,197 @@
+package multistatus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess isn't a real test: it is run as the subprocess of the
+// Command tests, doing what its arguments after "--" say
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("MULTISTATUS_HELPER") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	code := 0
+	for _, a := range args[1:] {
+		verb, arg, _ := strings.Cut(a, "=")
+		switch verb {
+		case "out":
+			fmt.Println(arg)
+		case "err":
+			fmt.Fprintln(os.Stderr, arg)
+		case "sleep":
+			d, _ := time.ParseDuration(arg)
+			time.Sleep(d)
+		case "exit":
+			fmt.Sscan(arg, &code)
+		}
+	}
+	os.Exit(code)
+}
+
+// helperCommand returns a Command running TestHelperProcess with args
+func helperCommand(args ...string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], append([]string{"-test.run=TestHelperProcess", "--"}, args...)...)
+	// the race detector would otherwise linger a second at exit
+	cmd.Env = append(os.Environ(), "MULTISTATUS_HELPER=1", "GORACE=atexit_sleep_ms=0")
+	return cmd
+}
+
+func TestCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	ok := helperCommand("out=hello", "err=warning", "out=bye")
+	ok.Stdout = &stdout
+	ws := New(WithSilent(true))
+	w := ws.Command("ok", ok)
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if w.State != Completed {
+		t.Fatalf("Command is %v, want %v: %v", w.State, Completed, w.Err())
+	}
+	// stdout and stderr are read separately, so only the order of each
+	// is kept
+	out := strings.Join(w.Output(), "\n")
+	if i, j := strings.Index(out, "hello"), strings.Index(out, "bye"); i < 0 || j < i || !strings.Contains(out, "warning") {
+		t.Errorf("captured output %q, want all three lines", out)
+	}
+	if stdout.String() != "hello\nbye\n" {
+		t.Errorf("cmd.Stdout got %q, want stdout still passed through", stdout.String())
+	}
+}
+
+func TestCommandFailed(t *testing.T) {
+	args := []string{"out=ignored"}
+	for i := 1; i <= 8; i++ {
+		args = append(args, fmt.Sprintf("err=line %d", i))
+	}
+	ws := New(WithSilent(true))
+	w := ws.Command("failed", helperCommand(append(args, "exit=3")...))
+	ws.Print(context.Background())
+	if w.State != Failed {
+		t.Fatalf("Command is %v, want Failed", w.State)
+	}
+	want := "exit status 3: line 4\nline 5\nline 6\nline 7\nline 8"
+	if got := w.Err().Error(); got != want {
+		t.Errorf("error is %q, want the last %d lines of stderr: %q", got, stderrTailLines, want)
+	}
+}
+
+func TestCommandCanceled(t *testing.T) {
+	ws := New(WithSilent(true))
+	w := ws.Command("slow", helperCommand("sleep=1m"))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	ws.Print(ctx)
+	if took := time.Since(start); took > 10*time.Second || w.State != Canceled {
+		t.Errorf("Command is %v after %v, want killed and Canceled", w.State, took)
+	}
+}
+
+func TestCommandRetried(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	marker := filepath.Join(t.TempDir(), "tried")
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(sh, "-c", `if [ -e "$1" ]; then echo second; else touch "$1"; echo first >&2; exit 3; fi`, "sh", marker)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	ws := New(WithSilent(true), WithRetries(2), WithBackoff(func(int) time.Duration { return 0 }))
+	w := ws.Command("retried", cmd)
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if w.State != Completed {
+		t.Fatalf("Command is %v, want %v: %v", w.State, Completed, w.Err())
+	}
+	if got := ws.Snapshot()[0].Attempts; len(got) != 1 || got[0].Err != "exit status 3: first" {
+		t.Errorf("attempts %+v, want one failing with exit status 3", got)
+	}
+	if stdout.String() != "second\n" || stderr.String() != "first\n" {
+		t.Errorf("stdout %q and stderr %q weren't each written once", stdout.String(), stderr.String())
+	}
+	if out := strings.Join(w.Output(), "\n"); out != "first\nsecond" {
+		t.Errorf("captured output %q, want both attempts'", out)
+	}
+}
+
+func TestCommandConcurrency(t *testing.T) {
+	ws := New(WithSilent(true), WithConcurrency(1))
+	for i := 0; i < 3; i++ {
+		ws.Command(fmt.Sprint("sleep ", i), helperCommand("sleep=20ms"))
+	}
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	snap := ws.Snapshot()
+	for i := 1; i < len(snap); i++ {
+		if gap := snap[i].Finished.Sub(snap[i-1].Finished); gap < 20*time.Millisecond {
+			t.Errorf("%s finished %v after %s, want it run after", snap[i].Name, gap, snap[i-1].Name)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock))
+	ok, failed := ws.Add("ok"), ws.Add("failed")
+	if err := ok.Do(func() error { clock.Advance(3 * time.Second); return nil }); err != nil {
+		t.Fatal(err)
+	}
+	broke := errors.New("broke")
+	if err := failed.Do(func() error { clock.Advance(time.Second); return broke }); err != broke {
+		t.Errorf("got %v, want %v", err, broke)
+	}
+	snap := ws.Snapshot()
+	if v := snap[0]; v.State != Completed || v.Elapsed != 3*time.Second {
+		t.Errorf("ok is %v after %v, want Completed after 3s", v.State, v.Elapsed)
+	}
+	if v := snap[1]; v.State != Failed || v.Err != "broke" || v.Elapsed != time.Second {
+		t.Errorf("failed is %v with %q after %v, want Failed with \"broke\" after 1s", v.State, v.Err, v.Elapsed)
+	}
+}
+
+func TestDoPanic(t *testing.T) {
+	ws := New(WithSilent(true))
+	w := ws.Add("panics")
+	err := w.Do(func() error { panic("oops") })
+	if err == nil || !strings.HasPrefix(err.Error(), "panic: oops\n") || !strings.Contains(err.Error(), "TestDoPanic") {
+		t.Fatalf("got %v, want the panic and its stack", err)
+	}
+	if v := ws.Snapshot()[0]; v.State != Failed || v.Err != err.Error() {
+		t.Errorf("worker is %v with %q, want Failed with the panic", v.State, v.Err)
+	}
+}
+
+func TestDoFinished(t *testing.T) {
+	ws := New(WithSilent(true))
+	w := ws.Add("twice")
+	w.Do(func() error { return nil })
+	called := false
+	if err := w.Do(func() error { called = true; return nil }); err != ErrFinished {
+		t.Errorf("got %v, want ErrFinished", err)
+	}
+	if called {
+		t.Error("fn called on a finished worker")
+	}
+	failed := ws.Add("failed")
+	failed.Fail()
+	if err := failed.Do(func() error { called = true; return nil }); err != ErrFinished || called {
+		t.Errorf("got %v, want ErrFinished without calling fn on a failed worker", err)
+	}
+}
@@ -0,0 +1,71 @@
+package multistatus
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetExpectedDuration records how long the Worker is expected to take, such
+// as from earlier runs. Once a pending Worker runs over, its elapsed time is
+// shown on its line, in the Theme's WarningColor and then in FailedColor at
+// twice the expected duration, the header counts the Workers over, and the
+// final report lists every Worker that took longer than expected. A
+// duration of 0 removes the expectation.
+func (w *Worker) SetExpectedDuration(d time.Duration) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.expected = d
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// overrun reports whether v took, or has taken so far, longer than expected
+func (v WorkerStatus) overrun() bool {
+	return v.Expected > 0 && v.Elapsed > v.Expected
+}
+
+// elapsedText formats v's elapsed time for its line, colored once a
+// pending Worker runs over its expected duration. A finished Worker's active
+// time follows when it's materially shorter, such as after backing off
+// between retries.
+func (w *WorkerSet) elapsedText(v WorkerStatus, level ColorLevel) string {
+	s := "(" + w.formatDuration(v.Elapsed) + ")"
+	if v.State.finished() {
+		if idle := v.Elapsed - v.Active; idle >= 100*time.Millisecond && idle*10 >= v.Elapsed {
+			s = "(" + fmt.Sprintf(w.messages.ActiveTime, w.formatDuration(v.Elapsed), w.formatDuration(v.Active)) + ")"
+		}
+		return s
+	}
+	if !v.overrun() {
+		return s
+	}
+	c := w.theme.WarningColor
+	if v.Elapsed >= 2*v.Expected {
+		c = w.theme.FailedColor
+	}
+	return colorize(s, c, level)
+}
+
+// overBudget counts the pending Workers in snap running over their expected
+// durations
+func overBudget(snap []WorkerStatus) int {
+	n := 0
+	for _, v := range snap {
+		if !v.State.finished() && v.overrun() {
+			n++
+		}
+	}
+	return n
+}
+
+// overrunLines lists the finished Workers in snap that took longer than
+// expected, for the end of the final report
+func (w *WorkerSet) overrunLines(snap []WorkerStatus) []string {
+	var lines []string
+	for _, v := range snap {
+		if v.State.finished() && v.overrun() {
+			lines = append(lines, "  "+fmt.Sprintf(w.messages.Overrun, stripControl(v.Name), w.formatDuration(v.Elapsed), w.formatDuration(v.Expected)))
+		}
+	}
+	return lines
+}
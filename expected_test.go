@@ -0,0 +1,59 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpectedDuration(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithClock(clock), WithColorLevel(Color16), WithHeader("build"))
+	w := ws.Add("slow")
+	ws.Add("quick").Done()
+	w.SetExpectedDuration(90 * time.Second)
+	yellow, red := csi+DefaultTheme.WarningColor.sgr(Color16)+"m", csi+DefaultTheme.FailedColor.sgr(Color16)+"m"
+	for _, tc := range []struct {
+		at     time.Duration
+		line   string // without the spinner
+		header bool
+	}{
+		{60 * time.Second, "slow", false},
+		{100 * time.Second, "slow " + yellow + "(1m40s)" + sgrReset, true},
+		// at twice the expected duration
+		{180 * time.Second, "slow " + red + "(3m00s)" + sgrReset, true},
+	} {
+		clock.Set(at(tc.at))
+		_, lines := ws.frame(true, false)
+		if got := strings.TrimLeft(lines[1], " "+defaultSpinner); got != tc.line {
+			t.Errorf("at %v: got %q, want %q", tc.at, got, tc.line)
+		}
+		if over := strings.HasSuffix(lines[0], " · 1 task over budget"); over != tc.header {
+			t.Errorf("at %v: header %q", tc.at, lines[0])
+		}
+	}
+
+	w.Done()
+	_, lines := ws.frame(true, true)
+	if got := lines[len(lines)-1]; got != "  slow took 3m00s, expected 1m30s" {
+		t.Errorf("final report ends %q", got)
+	}
+	b, _ := json.Marshal(ws.Snapshot()[0])
+	if !strings.Contains(string(b), `"elapsed":180000000000`) || !strings.Contains(string(b), `"expected":90000000000`) {
+		t.Errorf("export lacks the expected or actual duration: %s", b)
+	}
+}
+
+func TestExpectedDurationRemoved(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithClock(clock), WithColor(false))
+	w := ws.Add("a")
+	w.SetExpectedDuration(time.Second)
+	clock.Advance(time.Minute)
+	w.SetExpectedDuration(0)
+	w.Done()
+	if _, lines := ws.frame(false, true); len(lines) != 1 {
+		t.Errorf("overrun reported without an expectation: %q", lines)
+	}
+}
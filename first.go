@@ -0,0 +1,42 @@
+package multistatus
+
+// FirstError returns the error of the Worker that failed first, along with
+// the Worker, or nil for both if none has failed. Workers are compared by the
+// time they failed rather than the order they were added, with ties going to
+// the Worker added first. The error is nil if the Worker failed with Fail
+// rather than FailWith.
+func (w *WorkerSet) FirstError() (error, *Worker) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var first *Worker
+	for _, worker := range w.members() {
+		if worker.State != Failed {
+			continue
+		}
+		if first == nil || worker.finished.Before(first.finished) ||
+			worker.finished.Equal(first.finished) && worker.id < first.id {
+			first = worker
+		}
+	}
+	if first == nil {
+		return nil, nil
+	}
+	return first.err, first
+}
+
+// firstFailure returns the Worker in snap that failed first, as FirstError
+// chooses it
+func firstFailure(snap []WorkerStatus) (WorkerStatus, bool) {
+	var first WorkerStatus
+	found := false
+	for _, v := range snap {
+		if v.State != Failed {
+			continue
+		}
+		if !found || v.Finished.Before(first.Finished) ||
+			v.Finished.Equal(first.Finished) && v.ID < first.ID {
+			first, found = v, true
+		}
+	}
+	return first, found
+}
@@ -0,0 +1,59 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFirstError(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock))
+	a, b, c, d := ws.Add("a"), ws.Add("b"), ws.Add("c"), ws.Add("d")
+	if err, w := ws.FirstError(); err != nil || w != nil || ws.Summary().FirstFailed != -1 {
+		t.Fatalf("FirstError returned %v, %v before any failure", err, w)
+	}
+
+	clock.Advance(time.Second)
+	c.Done()
+	clock.Advance(time.Second)
+	d.FailWith(errors.New("d broke"))
+	clock.Advance(time.Second)
+	b.FailWith(errors.New("b broke"))
+	if err, w := ws.FirstError(); w != d || err.Error() != "d broke" {
+		t.Errorf("FirstError returned %v from %v, want d's", err, w)
+	}
+
+	// a tie, as with a coarse clock, goes to the Worker added first
+	clock.Advance(-time.Second)
+	a.Fail()
+	if err, w := ws.FirstError(); w != a || err != nil {
+		t.Errorf("FirstError returned %v from %v, want a with no error", err, w)
+	}
+	if s := ws.Summary(); s.FirstFailed != 0 {
+		t.Errorf("Summary has FirstFailed %d, want 0", s.FirstFailed)
+	}
+}
+
+func TestFirstErrorExported(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock))
+	a, b := ws.Add("a"), ws.Add("b")
+	b.FailWith(errors.New("root cause"))
+	clock.Advance(time.Second)
+	a.FailWith(errors.New("knock-on"))
+
+	s := ws.Summary()
+	if s.FirstFailed != 1 || s.FirstError != "root cause" {
+		t.Errorf("Summary has FirstFailed %d, FirstError %q", s.FirstFailed, s.FirstError)
+	}
+	j, err := json.Marshal(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(j), `"first_failed":1`) || !strings.Contains(string(j), `"first_error":"root cause"`) {
+		t.Errorf("JSON lacks the first failure: %s", j)
+	}
+}
@@ -0,0 +1,55 @@
+package multistatus
+
+import "errors"
+
+// ErrForeignWorker is recorded when Workers holds a Worker that belongs to
+// another WorkerSet or is listed twice, such as after appending to it
+// directly. The Worker is dropped from Workers, leaving the accounting of
+// both WorkerSets alone, and the error is reported by Summary.
+var ErrForeignWorker = errors.New("multistatus: Workers holds a worker of another WorkerSet or a duplicate")
+
+// members returns Workers, first dropping any Worker that doesn't belong in
+// it: one added to another WorkerSet, or one listed again. The Workers added
+// to a WorkerSet are kept in ID order, so either shows up as a Worker out of
+// order. The caller must hold the lock.
+func (w *WorkerSet) members() []*Worker {
+	last := int64(-1)
+	for i, worker := range w.Workers {
+		if stray(w, worker, last) {
+			w.dropStrays(i)
+			break
+		}
+		last = worker.id
+	}
+	return w.Workers
+}
+
+// stray reports whether worker doesn't belong in w's Workers after a Worker
+// with the ID last
+func stray(w *WorkerSet, worker *Worker, last int64) bool {
+	return worker == nil || worker.parent != w || worker.removed || worker.id <= last
+}
+
+// dropStrays rebuilds Workers without the strays from index i on, recording
+// ErrForeignWorker
+func (w *WorkerSet) dropStrays(i int) {
+	kept := append([]*Worker(nil), w.Workers[:i]...)
+	last := int64(-1)
+	if i > 0 {
+		last = kept[i-1].id
+	}
+	dropped := 0
+	for _, worker := range w.Workers[i:] {
+		if stray(w, worker, last) {
+			dropped++
+			continue
+		}
+		kept = append(kept, worker)
+		last = worker.id
+	}
+	w.Workers = kept
+	if w.misuse == nil {
+		w.misuse = ErrForeignWorker
+	}
+	w.log.printf("dropped %d workers from Workers: %v", dropped, ErrForeignWorker)
+}
@@ -0,0 +1,79 @@
+package multistatus
+
+import (
+	"context"
+	"testing"
+)
+
+func TestForeignWorker(t *testing.T) {
+	a := New(WithSilent(true))
+	b := New(WithSilent(true))
+	shared := a.Add("shared")
+	own := b.Add("own")
+	b.Workers = append(b.Workers, shared)
+
+	shared.Done()
+	own.Fail()
+	if err := a.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sa, sb := a.Summary(), b.Summary()
+	if len(sa.Workers) != 1 || sa.Completed != 1 || sa.Failed != 0 || !sa.OK || sa.Misuse != "" {
+		t.Errorf("the owning set has %+v", sa)
+	}
+	if len(sb.Workers) != 1 || sb.Workers[0].Name != "own" || sb.Completed != 0 || sb.Failed != 1 {
+		t.Errorf("the set given a foreign worker has %+v", sb)
+	}
+	if sb.Misuse != ErrForeignWorker.Error() {
+		t.Errorf("got misuse %q, want %q", sb.Misuse, ErrForeignWorker)
+	}
+	if n := len(b.Workers); n != 1 {
+		t.Errorf("Workers still holds %d workers", n)
+	}
+}
+
+func TestDuplicateWorker(t *testing.T) {
+	ws := New(WithSilent(true))
+	first := ws.Add("first")
+	second := ws.Add("second")
+	ws.Workers = append(ws.Workers, first, nil)
+
+	first.Done()
+	second.Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sum := ws.Summary()
+	if got := workerNames(ws); len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("got workers %v", got)
+	}
+	if sum.Completed != 2 || sum.Pending != 0 || !sum.OK {
+		t.Errorf("got %+v", sum)
+	}
+	if sum.Misuse != ErrForeignWorker.Error() {
+		t.Errorf("got misuse %q, want %q", sum.Misuse, ErrForeignWorker)
+	}
+}
+
+func TestRemovedWorkerReadded(t *testing.T) {
+	ws := New(WithSilent(true))
+	gone := ws.Add("gone")
+	kept := ws.Add("kept")
+	gone.Done()
+	if err := ws.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	ws.Workers = append(ws.Workers, gone)
+
+	kept.Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if sum := ws.Summary(); len(sum.Workers) != 1 || !sum.OK || sum.Misuse == "" {
+		t.Errorf("got %+v", sum)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/zikes/multistatus
+
+go 1.24.0
+
+require golang.org/x/term v0.36.0
+
+require golang.org/x/sys v0.37.0 // indirect
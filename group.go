@@ -0,0 +1,76 @@
+package multistatus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupSummary counts the Workers of one group by state. Stopping Workers
+// are counted as Pending.
+type GroupSummary struct {
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Canceled  int `json:"canceled,omitempty"`
+	Pending   int `json:"pending"`
+}
+
+// SetGroup files the Worker under the named group, or under none if name is
+// empty. Once any Worker has a group the header, Summary and JSON include a
+// roll-up of each group.
+func (w *Worker) SetGroup(name string) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.group = name
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// groupSummaries rolls up the Workers in snap by group, returning the groups
+// in the order they first appear. Workers without a group are left out.
+func groupSummaries(snap []WorkerStatus) (names []string, groups map[string]GroupSummary) {
+	for _, v := range snap {
+		if v.Group == "" || v.Info {
+			continue
+		}
+		if groups == nil {
+			groups = make(map[string]GroupSummary)
+		}
+		g, ok := groups[v.Group]
+		if !ok {
+			names = append(names, v.Group)
+		}
+		switch v.State {
+		case Completed:
+			g.Completed++
+		case Failed:
+			g.Failed++
+		case Canceled:
+			g.Canceled++
+		default:
+			g.Pending++
+		}
+		groups[v.Group] = g
+	}
+	return names, groups
+}
+
+// groupsLine formats the progress of each group for the header, such as
+// "build 4/4 ✔ · test 7/9"
+func (w *WorkerSet) groupsLine(snap []WorkerStatus, level ColorLevel) string {
+	names, groups := groupSummaries(snap)
+	parts := make([]string, 0, len(names))
+	t := w.theme
+	for _, name := range names {
+		g := groups[name]
+		total := g.Completed + g.Failed + g.Canceled + g.Pending
+		p := fmt.Sprintf("%s %d/%d", stripControl(name), g.Completed+g.Failed+g.Canceled, total)
+		switch {
+		case g.Failed > 0:
+			p += " " + colorize(t.Failed, t.FailedColor, level)
+		case g.Completed == total:
+			p += " " + colorize(t.Completed, t.CompletedColor, level)
+		}
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,71 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// groupedSet returns a WorkerSet with two groups and a Worker in neither
+func groupedSet(opts ...Option) *WorkerSet {
+	ws := New(opts...)
+	for _, g := range []struct{ name, group string }{
+		{"compile", "build"}, {"lint", "build"}, {"unit", "test"}, {"e2e", "test"}, {"notify", ""},
+	} {
+		w := ws.Add(g.name)
+		w.SetGroup(g.group)
+	}
+	ws.Workers[0].Done()
+	ws.Workers[1].Done()
+	ws.Workers[2].Fail()
+	return ws
+}
+
+func TestGroupsHeader(t *testing.T) {
+	ws := groupedSet(WithHeader("ci"))
+	header := ws.frameLines(false, false)[0]
+	if want := " · build 2/2 ✔ · test 1/2 ✗"; !strings.HasSuffix(header, want) {
+		t.Errorf("header %q doesn't end %q", header, want)
+	}
+
+	ws = New(WithHeader("ci"))
+	ws.Add("a").Done()
+	if header := ws.frameLines(false, false)[0]; strings.Contains(header, "·") {
+		t.Errorf("header %q has groups without any", header)
+	}
+}
+
+func TestGroupsSummary(t *testing.T) {
+	ws := groupedSet(WithSilent(true))
+	want := map[string]GroupSummary{
+		"build": {Completed: 2},
+		"test":  {Failed: 1, Pending: 1},
+	}
+	if got := ws.Summary().Groups; len(got) != 2 || got["build"] != want["build"] || got["test"] != want["test"] {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	b, err := json.Marshal(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Groups map[string]GroupSummary `json:"groups"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Groups["build"] != want["build"] || v.Groups["test"] != want["test"] {
+		t.Errorf("JSON has groups %+v", v.Groups)
+	}
+
+	// without groups the shapes are unchanged
+	ws = New(WithSilent(true))
+	ws.Add("a").Done()
+	if ws.Summary().Groups != nil {
+		t.Errorf("Summary has groups without any")
+	}
+	if b, _ := json.Marshal(ws); strings.Contains(string(b), `"groups"`) {
+		t.Errorf("JSON has groups without any: %s", b)
+	}
+}
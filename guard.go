@@ -0,0 +1,51 @@
+package multistatus
+
+// LineGuard decides how the first frame on a terminal makes sure it starts
+// at the beginning of a line, in case something was printed before Print
+// without a final newline
+type LineGuard int
+
+// Available LineGuards
+const (
+	// GuardErase returns to the start of the line and erases it, losing
+	// any text printed on it before Print
+	GuardErase LineGuard = iota
+	// GuardNewline asks the terminal where the cursor is, and starts a
+	// new line unless it is already at the start of one, keeping any
+	// text printed before Print. If the terminal doesn't answer within
+	// 200ms it falls back to GuardErase.
+	GuardNewline
+	// GuardNone starts the first frame wherever the cursor is
+	GuardNone
+)
+
+// WithLineGuard sets how the first frame on a terminal makes sure it starts
+// at the beginning of a line, GuardErase by default. Without a guard, text
+// printed before Print without a final newline offsets the whole block and
+// leaves stray characters behind as it is redrawn. Either way, a block
+// starting on the terminal's last row scrolls it up to make room.
+func WithLineGuard(g LineGuard) Option {
+	return func(w *WorkerSet) {
+		w.lineGuard = g
+	}
+}
+
+// guard writes the escapes placing the cursor at the start of a line before
+// the first frame
+func (w *WorkerSet) guard(e *escWriter) {
+	switch w.lineGuard {
+	case GuardNone:
+		return
+	case GuardNewline:
+		col, err := cursorColumn(w.terminal, w.out, probeTimeout)
+		if err == nil {
+			if col > 1 {
+				e.WriteString("\n")
+			}
+			return
+		}
+		w.debug.printf("terminal: cursor position unavailable, erasing the line: %v", err)
+	}
+	e.WriteString("\r")
+	e.EraseLine()
+}
@@ -0,0 +1,76 @@
+package multistatus
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// guardedRun prints two Workers with the guard g on a screen of height h
+// already holding prior, and returns what is left on the screen along with
+// what was written
+func guardedRun(g LineGuard, h int, prior string) ([]string, string) {
+	var out syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithSize(80, h), WithColor(false),
+		WithMarkerStyle(MarkerWords), WithLineGuard(g), WithRefreshInterval(time.Millisecond))
+	ws.Add("alpha").Done()
+	beta := ws.Add("beta")
+	time.AfterFunc(20*time.Millisecond, beta.Done)
+	ws.Print(context.Background())
+	vt := newVT(h)
+	vt.feed(prior + out.String())
+	return vt.lines(), out.String()
+}
+
+func TestLineGuard(t *testing.T) {
+	block := []string{"  [ OK ] alpha", "  [ OK ] beta"}
+	for _, tc := range []struct {
+		name  string
+		guard LineGuard
+		h     int
+		prior string
+		want  []string
+	}{
+		{"erase dirty line", GuardErase, 24, "$ partial", block},
+		{"erase clean line", GuardErase, 24, "$ done\n", append([]string{"$ done"}, block...)},
+		// the block and the line left for the cursor after it scroll the
+		// screen up
+		{"erase last row", GuardErase, 4, "1\n2\n3\n$ partial", append([]string{"3"}, block...)},
+		{"none clean line", GuardNone, 24, "$ done\n", append([]string{"$ done"}, block...)},
+	} {
+		if got, _ := guardedRun(tc.guard, tc.h, tc.prior); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.name, strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+		}
+	}
+
+	// only the guard returns to the start of the line before reserving
+	// the block, for terminals where a newline doesn't
+	if _, out := guardedRun(GuardErase, 24, ""); !strings.HasPrefix(out, "\r\x1b[2K\n") {
+		t.Errorf("GuardErase output starts %q", out[:min(len(out), 12)])
+	}
+	if _, out := guardedRun(GuardNone, 24, ""); !strings.HasPrefix(out, "\n") {
+		t.Errorf("GuardNone output starts %q", out[:min(len(out), 12)])
+	}
+}
+
+func TestParseCursorReply(t *testing.T) {
+	for _, tc := range []struct {
+		reply string
+		col   int
+		ok    bool
+	}{
+		{"\x1b[12;1R", 1, true},
+		{"\x1b[3;40R", 40, true},
+		{"typed\x1b[3;40R", 40, true},
+		{"\x1b[3;4", 0, false},
+		{"", 0, false},
+		{"\x1b[garbageR", 0, true},
+	} {
+		col, ok := parseCursorReply([]byte(tc.reply))
+		if col != tc.col || ok != tc.ok {
+			t.Errorf("%q: got %d, %v, want %d, %v", tc.reply, col, ok, tc.col, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package multistatus
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// headerBarWidth is the width in columns of the header's progress bar
+const headerBarWidth = 20
+
+// WithHeader shows a line above the Workers with the given title, a
+// progress bar, and the number of finished Workers, followed with WithElapsed
+// by the run's Duration. Once any Worker fails the title notes the number of
+// failures and the counts turn red.
+func WithHeader(title string) Option {
+	return func(w *WorkerSet) {
+		w.header = title
+		w.showHeader = true
+	}
+}
+
+// barSegment is one colored run of a progress bar
+type barSegment struct {
+	count int
+	fill  string
+	plain string
+	color Color
+}
+
+// renderBar draws a bar of width cells split between segs in proportion to
+// their counts, like a stacked bar chart. Segment boundaries are rounded from
+// the running total so the segments always add up to exactly width. Without
+// color each segment uses its plain fill character instead.
+func renderBar(width int, segs []barSegment, level ColorLevel) string {
+	total := 0
+	for _, s := range segs {
+		total += s.count
+	}
+	if total == 0 && len(segs) > 0 {
+		// nothing to show yet; draw the bar as entirely remaining
+		segs = segs[len(segs)-1:]
+	}
+	var buf strings.Builder
+	cum, pos := 0, 0
+	for i, s := range segs {
+		cum += s.count
+		end := width
+		if total > 0 && i < len(segs)-1 {
+			end = int(math.Round(float64(cum) / float64(total) * float64(width)))
+		}
+		n := end - pos
+		if n <= 0 {
+			continue
+		}
+		pos = end
+		if level != ColorNone {
+			buf.WriteString(colorize(strings.Repeat(s.fill, n), s.color, level))
+		} else {
+			buf.WriteString(strings.Repeat(s.plain, n))
+		}
+	}
+	return buf.String()
+}
+
+// headerLine formats the header for snap
+func (w *WorkerSet) headerLine(snap []WorkerStatus, level ColorLevel) string {
+	counts, total := w.tally(snap)
+	completed, failed, canceled := counts[Completed], counts[Failed], counts[Canceled]
+
+	title := w.header
+	if title == "" {
+		title = w.name
+	}
+	title = stripControl(title)
+	if failed > 0 {
+		title += w.messages.pluralf(failed, w.messages.Failures, failed)
+	}
+
+	t := w.theme
+	bar := renderBar(headerBarWidth, []barSegment{
+		{count: completed, fill: "█", plain: "=", color: t.CompletedColor},
+		{count: failed, fill: "█", plain: "x", color: t.FailedColor},
+		{count: canceled, fill: "█", plain: "/", color: t.CanceledColor},
+		{count: total - completed - failed - canceled, fill: "░", plain: " ", color: t.DimColor},
+	}, level)
+
+	progress := fmt.Sprintf("%d/%d", completed+failed+canceled, total)
+	if failed > 0 {
+		progress = colorize(progress, t.FailedColor, level)
+	}
+	line := fmt.Sprintf("[%s] %s", bar, progress)
+	if w.showElapsed {
+		d, _ := w.duration(countable(snap), w.now())
+		line += " (" + w.formatDuration(d) + ")"
+	}
+	if title != "" {
+		line = title + " " + line
+	}
+	if groups := w.groupsLine(snap, level); groups != "" {
+		line += " · " + groups
+	}
+	if w.startRate != "" {
+		line += " " + fmt.Sprintf(w.messages.StartRate, w.startRate)
+	}
+	if w.rateBucket > 0 {
+		if rate := w.rateLine(snap); rate != "" {
+			line += " · " + rate
+		}
+	}
+	if n := pausedCount(snap); n > 0 {
+		line += " · " + w.messages.pluralf(n, w.messages.Paused, n)
+	}
+	if n := overBudget(snap); n > 0 {
+		line += " · " + w.messages.pluralf(n, w.messages.OverBudget, n)
+	}
+	if w.isDraining() {
+		left := counts[Pending] + counts[Stopping]
+		line += " · " + w.messages.pluralf(left, w.messages.Draining, left)
+	}
+	return line
+}
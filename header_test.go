@@ -0,0 +1,58 @@
+package multistatus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderBar(t *testing.T) {
+	segs := func(completed, failed, canceled, remaining int) []barSegment {
+		return []barSegment{
+			{count: completed, fill: "█", plain: "=", color: Green},
+			{count: failed, fill: "█", plain: "x", color: Red},
+			{count: canceled, fill: "█", plain: "/", color: Yellow},
+			{count: remaining, fill: "░", plain: " ", color: DefaultColor.Dim()},
+		}
+	}
+	for _, tc := range []struct {
+		segs []barSegment
+		want string
+	}{
+		{segs(0, 0, 0, 0), "                    "},
+		{segs(0, 0, 0, 10), "                    "},
+		{segs(5, 0, 0, 5), "==========          "},
+		{segs(5, 5, 0, 0), "==========xxxxxxxxxx"},
+		{segs(2, 1, 1, 6), "====xx//            "},
+		{segs(1, 1, 1, 0), "=======xxxxxx///////"},
+		{segs(1, 0, 0, 2), "=======             "},
+		{segs(100, 1, 0, 0), "===================="},
+	} {
+		if got := renderBar(20, tc.segs, ColorNone); got != tc.want {
+			t.Errorf("%+v:\n got %q\nwant %q", tc.segs, got, tc.want)
+		}
+	}
+
+	got := renderBar(10, segs(3, 2, 0, 5), Color16)
+	want := colorize("███", Green, Color16) + colorize("██", Red, Color16) + colorize("░░░░░", DefaultColor.Dim(), Color16)
+	if got != want {
+		t.Errorf("colored bar is %q, want %q", got, want)
+	}
+}
+
+func TestHeaderFailures(t *testing.T) {
+	ws := New(WithHeader("deploy"))
+	ws.Add("a").Done()
+	b := ws.Add("b")
+	if h := ws.headerLine(ws.Snapshot(), ColorNone); h != "deploy [==========          ] 1/2" {
+		t.Errorf("header is %q", h)
+	}
+	b.FailWith(errors.New("broke"))
+	ws.Add("c").FailWith(errors.New("broke"))
+	if h := ws.headerLine(ws.Snapshot(), ColorNone); h != "deploy (2 failures) [=======xxxxxxxxxxxxx] 3/3" {
+		t.Errorf("header is %q", h)
+	}
+	if h := ws.headerLine(ws.Snapshot(), Color16); !strings.Contains(h, colorize("3/3", Red, Color16)) {
+		t.Errorf("header %q doesn't show the counts in red", h)
+	}
+}
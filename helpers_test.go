@@ -0,0 +1,100 @@
+package multistatus
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// syncBuf is a bytes.Buffer safe to write from the display and read from
+// the test
+type syncBuf struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (s *syncBuf) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuf) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+// vtScreen is a minimal terminal emulator, enough to replay what the display
+// writes and see what is left on the screen
+type vtScreen struct {
+	rows [][]rune
+	r, c int
+	h    int
+}
+
+func newVT(h int) *vtScreen { return &vtScreen{h: h, rows: make([][]rune, h)} }
+
+var vtCSI = regexp.MustCompile(`^\x1b\[([0-9;?]*)([A-Za-z])`)
+
+// feed writes b to the screen, following cursor movement and erasing but
+// ignoring every other escape
+func (s *vtScreen) feed(b string) {
+	for len(b) > 0 {
+		if m := vtCSI.FindStringSubmatch(b); m != nil {
+			n, err := strconv.Atoi(m[1])
+			if err != nil || n == 0 {
+				n = 1
+			}
+			switch m[2] {
+			case "A":
+				s.r = max(s.r-n, 0)
+			case "B":
+				s.r = min(s.r+n, s.h-1)
+			case "K":
+				if m[1] == "2" {
+					s.rows[s.r] = nil
+				} else if s.c < len(s.rows[s.r]) {
+					s.rows[s.r] = s.rows[s.r][:s.c]
+				}
+			}
+			b = b[len(m[0]):]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(b)
+		b = b[size:]
+		switch r {
+		case '\n':
+			s.c = 0
+			if s.r == s.h-1 {
+				s.rows = append(s.rows[1:], nil)
+			} else {
+				s.r++
+			}
+		case '\r':
+			s.c = 0
+		default:
+			for len(s.rows[s.r]) <= s.c {
+				s.rows[s.r] = append(s.rows[s.r], ' ')
+			}
+			s.rows[s.r][s.c] = r
+			s.c++
+		}
+	}
+}
+
+// lines returns the lines on the screen, without trailing spaces or the
+// empty lines at the bottom
+func (s *vtScreen) lines() []string {
+	var out []string
+	for _, r := range s.rows {
+		out = append(out, strings.TrimRight(string(r), " "))
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package multistatus
+
+import "time"
+
+// defaultHistoryLimit is the number of TransitionRecords kept per Worker
+// unless WithHistoryLimit is given
+const defaultHistoryLimit = 100
+
+// A TransitionRecord is an entry in a Worker's History
+type TransitionRecord struct {
+	From   WorkerState `json:"from"`
+	To     WorkerState `json:"to"`
+	Err    string      `json:"error,omitempty"`
+	Status string      `json:"status,omitempty"`
+	Time   time.Time   `json:"time"`
+}
+
+// WithHistoryLimit sets how many TransitionRecords each Worker keeps in its
+// History, 100 by default. Once the limit is reached the oldest records are
+// dropped. A limit of 0 keeps no History.
+func WithHistoryLimit(n int) Option {
+	return func(w *WorkerSet) {
+		w.historyLimit = n
+	}
+}
+
+// WithSnapshotHistory includes each Worker's History in snapshots, and so in
+// Summaries and the WorkerSet's JSON
+func WithSnapshotHistory(b bool) Option {
+	return func(w *WorkerSet) {
+		w.snapshotHistory = b
+	}
+}
+
+// History returns the Worker's state transitions in the order they happened,
+// each with the status the Worker had at the time
+func (w *Worker) History() []TransitionRecord {
+	w.parent.mu.Lock()
+	defer w.parent.mu.Unlock()
+	return append([]TransitionRecord(nil), w.history...)
+}
+
+// record appends a transition to the Worker's History. The caller must hold
+// the parent's lock, and must already have updated the Worker.
+func (w *Worker) record(from WorkerState, t time.Time) {
+	limit := w.parent.historyLimit
+	if limit <= 0 {
+		return
+	}
+	r := TransitionRecord{From: from, To: w.State, Status: w.status, Time: t}
+	if w.err != nil {
+		r.Err = w.err.Error()
+	}
+	if n := len(w.history) - limit + 1; n > 0 {
+		w.history = append(w.history[:0], w.history[n:]...)
+		w.historyDropped += n
+	}
+	w.history = append(w.history, r)
+}
@@ -0,0 +1,107 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScrollingHistory(t *testing.T) {
+	clock := newFakeClock()
+	var out syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithSize(80, 24), WithScrollingHistory(true),
+		WithColor(false), WithClock(clock), WithRefreshInterval(time.Millisecond))
+	a, b, c := ws.Add("a"), ws.Add("b"), ws.Add("c")
+	screen := func() string {
+		vt := newVT(24)
+		vt.feed(out.String())
+		return strings.Join(vt.lines(), "\n")
+	}
+	var mid string
+	go func() {
+		clock.Advance(2 * time.Second)
+		b.Done()
+		waitFor(func() bool { return strings.Contains(out.String(), "b (2s)") })
+		time.Sleep(20 * time.Millisecond)
+		mid = screen()
+		clock.Advance(3 * time.Second)
+		c.Done()
+		clock.Advance(time.Second)
+		a.Fail()
+	}()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the live block holds only the pending Workers, below the history
+	if lines := strings.Split(mid, "\n"); len(lines) != 3 || lines[0] != "  ✔ b (2s)" ||
+		!strings.HasSuffix(lines[1], " a") || !strings.HasSuffix(lines[2], " c") {
+		t.Errorf("screen while a and c run:\n%s", mid)
+	}
+	if got, want := screen(), "  ✔ b (2s)\n  ✔ c (5s)\n  ✗ a (6s)"; got != want {
+		t.Errorf("screen at the end:\n%s\nwant history in the order finished:\n%s", got, want)
+	}
+	if n := strings.Count(out.String(), "b (2s)"); n != 1 {
+		t.Errorf("b's history line was written %d times, want once", n)
+	}
+}
+
+func TestWorkerHistory(t *testing.T) {
+	clock := newFakeClock()
+	t0 := clock.Now()
+	ws := New(WithSilent(true), WithClock(clock), WithRetries(1),
+		WithBackoff(func(int) time.Duration { return 0 }))
+	tries := 0
+	w := ws.Go("flaky", func(ctx context.Context) error {
+		tries++
+		clock.Advance(time.Second)
+		if tries == 1 {
+			return errors.New("broke")
+		}
+		return nil
+	})
+	ws.Print(context.Background())
+
+	h := w.History()
+	want := []TransitionRecord{
+		{From: Pending, To: Pending, Err: "broke", Time: t0.Add(time.Second)},
+		{From: Pending, To: Completed, Time: t0.Add(2 * time.Second)},
+	}
+	if fmt.Sprint(h) != fmt.Sprint(want) {
+		t.Errorf("got  %+v\nwant %+v", h, want)
+	}
+	if last := h[len(h)-1]; last.To != ws.Snapshot()[0].State {
+		t.Errorf("History ends %v, Worker is %v", last.To, ws.Snapshot()[0].State)
+	}
+	if v := ws.Snapshot()[0]; v.History != nil {
+		t.Errorf("History in snapshot without WithSnapshotHistory")
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	ws := New(WithSilent(true), WithHistoryLimit(2), WithSnapshotHistory(true),
+		WithRetries(4), WithBackoff(func(int) time.Duration { return 0 }))
+	tries := 0
+	ws.Go("flaky", func(ctx context.Context) error {
+		tries++
+		return fmt.Errorf("try %d", tries)
+	})
+	ws.Print(context.Background())
+	v := ws.Snapshot()[0]
+	if len(v.History) != 2 || v.HistoryDropped != 3 {
+		t.Fatalf("kept %d records and dropped %d, want 2 and 3", len(v.History), v.HistoryDropped)
+	}
+	if v.History[0].Err != "try 4" || v.History[1].To != Failed || v.History[1].Err != "try 5" {
+		t.Errorf("kept %+v, want the latest", v.History)
+	}
+
+	ws = New(WithSilent(true), WithHistoryLimit(0))
+	w := ws.Add("none")
+	w.Done()
+	if h := w.History(); len(h) != 0 {
+		t.Errorf("kept %+v with a limit of 0", h)
+	}
+}
@@ -0,0 +1,42 @@
+package multistatus
+
+// A TransitionHook is called as a Worker is about to finish, with the state
+// it is moving from and the state and error it is finishing with. It returns
+// the state and error the Worker should finish with instead, or the ones it
+// was given to leave them unchanged.
+type TransitionHook func(w *Worker, from, to WorkerState, err error) (WorkerState, error)
+
+// WithTransitionHook has fn decide how each Worker finishes, for example to
+// mark known flaky tasks Completed despite failing, or to redact secrets from
+// errors. fn is also consulted before a failed Worker is retried, which it
+// isn't if fn rewrites the failure to another state.
+//
+// fn is called while the WorkerSet is locked, so it must be fast, mustn't
+// block, and mustn't call methods of the Worker or WorkerSet. A state other
+// than Completed, Failed or Canceled is ignored, as is the outcome of a call
+// that panics.
+func WithTransitionHook(fn TransitionHook) Option {
+	return func(w *WorkerSet) {
+		w.transitionHook = fn
+	}
+}
+
+// hooked passes the Worker's transition to the WorkerSet's TransitionHook,
+// if any. The caller must hold the parent's lock.
+func (w *Worker) hooked(from, to WorkerState, err error) (hookTo WorkerState, hookErr error) {
+	ws := w.parent
+	if ws.transitionHook == nil {
+		return to, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			ws.log.printf("#%d %s: transition hook panicked: %v", w.id, stripControl(w.Name), r)
+			hookTo, hookErr = to, err
+		}
+	}()
+	hookTo, hookErr = ws.transitionHook(w, from, to, err)
+	if !hookTo.finished() {
+		return to, err
+	}
+	return hookTo, hookErr
+}
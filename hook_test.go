@@ -0,0 +1,89 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransitionHookRewrite(t *testing.T) {
+	secret := regexp.MustCompile(`token=\w+`)
+	ws := New(WithSilent(true), WithTransitionHook(func(w *Worker, from, to WorkerState, err error) (WorkerState, error) {
+		switch {
+		case w.Name == "flaky" && to == Failed:
+			return Completed, nil
+		case err != nil:
+			return to, errors.New(secret.ReplaceAllString(err.Error(), "token=REDACTED"))
+		}
+		return to, err
+	}))
+	flaky := ws.Add("flaky")
+	leaky := ws.Add("leaky")
+	ok := ws.Add("ok")
+	flaky.FailWith(errors.New("broke"))
+	leaky.FailWith(errors.New("GET /api?token=abc123: forbidden"))
+	ok.Done()
+
+	snap := ws.Snapshot()
+	if snap[0].State != Completed || snap[0].Err != "" {
+		t.Errorf("flaky is %v with %q, want Completed", snap[0].State, snap[0].Err)
+	}
+	if snap[1].State != Failed || snap[1].Err != "GET /api?token=REDACTED: forbidden" {
+		t.Errorf("leaky is %v with %q", snap[1].State, snap[1].Err)
+	}
+	if snap[2].State != Completed {
+		t.Errorf("ok is %v", snap[2].State)
+	}
+	if ws.Count(Completed) != 2 || ws.Count(Failed) != 1 {
+		t.Errorf("counts %d completed, %d failed", ws.Count(Completed), ws.Count(Failed))
+	}
+}
+
+func TestTransitionHookPanic(t *testing.T) {
+	var log syncBuf
+	ws := New(WithSilent(true), WithLogWriter(&log), WithTransitionHook(func(*Worker, WorkerState, WorkerState, error) (WorkerState, error) {
+		panic("oops")
+	}))
+	w := ws.Add("task")
+	w.FailWith(errors.New("broke"))
+	if v := ws.Snapshot()[0]; v.State != Failed || v.Err != "broke" {
+		t.Errorf("task is %v with %q, want the original transition", v.State, v.Err)
+	}
+	ws.Print(context.Background())
+	if !strings.Contains(log.String(), "transition hook panicked: oops") {
+		t.Errorf("panic not logged:\n%s", log.String())
+	}
+}
+
+func TestTransitionHookIgnoresPending(t *testing.T) {
+	ws := New(WithSilent(true), WithTransitionHook(func(*Worker, WorkerState, WorkerState, error) (WorkerState, error) {
+		return Pending, nil
+	}))
+	ws.Add("task").Done()
+	if v := ws.Snapshot()[0]; v.State != Completed {
+		t.Errorf("task is %v, want Completed", v.State)
+	}
+}
+
+func TestTransitionHookRetry(t *testing.T) {
+	// a failure the hook rewrites isn't retried
+	ws := New(WithSilent(true), WithRetries(3), WithBackoff(func(int) time.Duration { return 0 }),
+		WithTransitionHook(func(w *Worker, from, to WorkerState, err error) (WorkerState, error) {
+			if err != nil && err.Error() == "flaky" {
+				return Completed, nil
+			}
+			return to, err
+		}))
+	tries := 0
+	ws.Go("task", func(context.Context) error {
+		tries++
+		return errors.New("flaky")
+	})
+	ws.Print(context.Background())
+	if v := ws.Snapshot()[0]; tries != 1 || v.State != Completed {
+		t.Errorf("ran %d times and ended %v, want once and Completed", tries, v.State)
+	}
+}
@@ -0,0 +1,73 @@
+package multistatus
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+)
+
+// statusPage is served at / by Handler. It polls /status, so it needs no
+// other assets.
+//
+//go:embed status.html
+var statusPage []byte
+
+// Handler returns an http.Handler serving the WorkerSet's status: its JSON
+// snapshot, as produced by MarshalJSON, at /status and a self-contained page
+// at / that polls it every second, drawing the Workers and a progress bar. The
+// page stops polling once the run has finished, and shows when the server
+// can't be reached. The snapshot reports the run finished only once Print has
+// completed the status file, recording and run log. It is safe to use at any
+// time, before, during or after Print.
+func (w *WorkerSet) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(rw http.ResponseWriter, r *http.Request) {
+		v := w.jsonValue()
+		w.mu.Lock()
+		v.Finished = w.tornDown
+		w.mu.Unlock()
+		b, err := json.Marshal(v)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write(b)
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(rw, r)
+			return
+		}
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		rw.Write(statusPage)
+	})
+	return mux
+}
+
+// ServeStatus serves Handler on addr in the background until Print returns or
+// the WorkerSet is closed. It returns an error if addr can't be listened on.
+func (w *WorkerSet) ServeStatus(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: w.Handler()}
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		srv.Serve(l)
+	}()
+	w.onFinish(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if srv.Shutdown(ctx) != nil {
+			srv.Close()
+		}
+		<-served
+	})
+	return nil
+}
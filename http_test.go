@@ -0,0 +1,115 @@
+package multistatus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// getStatus fetches and decodes /status from the server at url
+func getStatus(t *testing.T, url string) (setJSON, *http.Response) {
+	t.Helper()
+	resp, err := http.Get(url + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var v setJSON
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v, resp
+}
+
+func TestHandler(t *testing.T) {
+	ws := New(WithSilent(true))
+	srv := httptest.NewServer(ws.Handler())
+	defer srv.Close()
+
+	if v, resp := getStatus(t, srv.URL); len(v.Workers) != 0 || v.Finished || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("before Print: %+v", v)
+	}
+	ws.Add("a<b>").Done()
+	c := ws.Add("c")
+	if v, _ := getStatus(t, srv.URL); len(v.Workers) != 2 || v.Completed != 1 || v.Pending != 1 || v.Finished {
+		t.Errorf("mid-run: %+v", v)
+	}
+	c.Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := getStatus(t, srv.URL); v.Completed != 2 || !v.Finished || v.Workers[0].Name != "a<b>" {
+		t.Errorf("after Print: %+v", v)
+	}
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/html; charset=utf-8" || !strings.Contains(string(b), `"status"`) || strings.Contains(string(b), "a<b>") {
+		t.Errorf("page isn't self-contained HTML polling status: %.200s", b)
+	}
+	resp, err = http.Get(srv.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("/missing returned %v", resp.Status)
+	}
+}
+
+func TestServeStatus(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	ws := New(WithSilent(true))
+	if err := ws.ServeStatus(addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().ServeStatus(addr); err == nil {
+		t.Error("ServeStatus on an address in use returned nil")
+	}
+	w := ws.Add("task")
+	if v, _ := getStatus(t, "http://"+addr); v.Pending != 1 {
+		t.Errorf("mid-run: %+v", v)
+	}
+	w.Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := http.Get("http://" + addr + "/status"); err == nil {
+		t.Error("server still up after Print returned")
+	}
+}
+
+func TestStatusPage(t *testing.T) {
+	page := string(statusPage)
+	if len(page) > 16<<10 {
+		t.Errorf("page is %d bytes", len(page))
+	}
+	// nothing fetched but the status itself
+	for _, external := range []string{"<script src", "<link", "@import", "http://", "https://"} {
+		if strings.Contains(page, external) {
+			t.Errorf("page loads external assets: has %q", external)
+		}
+	}
+	// polling stops once the run finishes, and an unreachable server is
+	// shown as disconnected
+	for _, want := range []string{`req.open("GET", "status")`, "last.finished", `"disconnected"`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+}
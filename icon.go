@@ -0,0 +1,13 @@
+package multistatus
+
+// SetIcon shows icon, such as a glyph for the kind of task, between the
+// Worker's marker and its name. Icons are left out in the word style, used
+// by default when the output isn't a terminal, and dropped on a narrow
+// terminal before the name is shortened. An empty icon removes it.
+func (w *Worker) SetIcon(icon string) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.icon = icon
+	ws.mu.Unlock()
+	ws.touch()
+}
@@ -0,0 +1,88 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// iconSet returns a WorkerSet drawn width columns wide holding a pending
+// Worker named task with icon
+func iconSet(width int, icon string) (*WorkerSet, *Worker) {
+	ws := New(WithClock(newFakeClock()), WithColor(false), WithSize(width, 24))
+	worker := ws.Add("task")
+	worker.SetIcon(icon)
+	return ws, worker
+}
+
+func TestIconWidths(t *testing.T) {
+	for _, tc := range []struct {
+		icon  string
+		width int
+	}{
+		{"λ", 1},
+		{"📦", 2},
+	} {
+		ws, _ := iconSet(80, tc.icon)
+		want := "  ⠋ " + tc.icon + " task"
+		if got := ws.frameLines(true, false)[0]; got != want {
+			t.Errorf("%s: got %q, want %q", tc.icon, got, want)
+		}
+		if got := stringWidth(want); got != 9+tc.width {
+			t.Errorf("%s: line is %d columns, want %d", tc.icon, got, 9+tc.width)
+		}
+
+		// the icon goes before the name is shortened, and no width overflows
+		fits := 9 + tc.width
+		if got := iconLine(fits, tc.icon); got != want {
+			t.Errorf("%s at width %d: got %q, want %q", tc.icon, fits, got, want)
+		}
+		if got := iconLine(fits-1, tc.icon); got != "  ⠋ task" {
+			t.Errorf("%s at width %d: got %q, want the icon dropped", tc.icon, fits-1, got)
+		}
+		for width := 6; width <= fits; width++ {
+			if line := iconLine(width, tc.icon); stringWidth(line) > width {
+				t.Errorf("%s at width %d: %q overflows", tc.icon, width, line)
+			}
+		}
+	}
+}
+
+// iconLine returns the line drawn for a Worker with icon width columns wide
+func iconLine(width int, icon string) string {
+	ws, _ := iconSet(width, icon)
+	return ws.frameLines(true, false)[0]
+}
+
+func TestIconWords(t *testing.T) {
+	ws, _ := iconSet(80, "📦")
+	if got, want := ws.frameLines(false, false)[0], "  [ .. ] task"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// the word style leaves icons out on a terminal too
+	ws = New(WithClock(newFakeClock()), WithColor(false), WithSize(80, 24), WithMarkerStyle(MarkerWords))
+	ws.Add("task").SetIcon("📦")
+	if got, want := ws.frameLines(true, false)[0], "  [ .. ] task"; got != want {
+		t.Errorf("on a terminal got %q, want %q", got, want)
+	}
+}
+
+func TestIconRemoved(t *testing.T) {
+	ws, worker := iconSet(80, "📦")
+	worker.SetIcon("")
+	if got := ws.frameLines(true, false)[0]; got != "  ⠋ task" {
+		t.Errorf("got %q after removing the icon", got)
+	}
+}
+
+func TestIconJSON(t *testing.T) {
+	ws, _ := iconSet(80, "📦")
+	b, err := json.Marshal(ws.Snapshot()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"icon":"📦"`) {
+		t.Errorf("icon missing from %s", b)
+	}
+}
@@ -0,0 +1,71 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIDsConcurrent(t *testing.T) {
+	const goroutines, each = 16, 200
+	ws := New(WithSilent(true))
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				ws.Add("retry")
+			}
+		}()
+	}
+	wg.Wait()
+	snap := ws.Snapshot()
+	if len(snap) != goroutines*each {
+		t.Fatalf("%d Workers, want %d", len(snap), goroutines*each)
+	}
+	for i, v := range snap {
+		// IDs are assigned in the order Workers are appended
+		if v.ID != int64(i) {
+			t.Fatalf("Worker %d has ID %d", i, v.ID)
+		}
+		w, ok := ws.ByID(v.ID)
+		if !ok || w.ID() != v.ID {
+			t.Fatalf("ByID(%d) returned %v, %v", v.ID, w, ok)
+		}
+	}
+	if _, ok := ws.ByID(int64(len(snap))); ok {
+		t.Error("ByID found an ID never assigned")
+	}
+}
+
+func TestIDStableAcrossRetries(t *testing.T) {
+	ws := New(WithSilent(true), WithRetries(2), WithBackoff(func(int) time.Duration { return 0 }))
+	var ids []int64
+	var events []Event
+	ws.Subscribe(func(e Event) { events = append(events, e) })
+	tries := 0
+	var w *Worker
+	assigned := make(chan struct{})
+	w = ws.Go("flaky", func(ctx context.Context) error {
+		<-assigned
+		ids = append(ids, w.ID())
+		if tries++; tries < 3 {
+			return errors.New("broke")
+		}
+		return nil
+	})
+	close(assigned)
+	ws.Print(context.Background())
+	ws.Close()
+	for _, id := range ids {
+		if id != w.ID() {
+			t.Errorf("ID changed across attempts: %v", ids)
+		}
+	}
+	if len(events) == 0 || events[len(events)-1].ID != w.ID() {
+		t.Errorf("events don't carry the ID: %+v", events)
+	}
+}
@@ -0,0 +1,84 @@
+package multistatus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithIndexColumn shows each Worker's index, its ID plus one, in a column
+// before its name, such as "[ 17]", padded to the width of the largest
+// index. The index is included in snapshots, and the final report ends with
+// the indices of the failed Workers as given by FailedIndexSpec. The column
+// is the first part of the line dropped on a narrow terminal.
+func WithIndexColumn(b bool) Option {
+	return func(w *WorkerSet) {
+		w.indexColumn = b
+	}
+}
+
+// FailedIndexSpec returns the indices of the failed Workers, as shown by
+// WithIndexColumn, as a compact list of numbers and ranges such as
+// "3,7,12-15", or an empty string when none failed
+func (w *WorkerSet) FailedIndexSpec() string {
+	return indexSpec(failedIndices(w.Snapshot()))
+}
+
+// failedIndices returns the indices of the failed Workers in snap
+func failedIndices(snap []WorkerStatus) []int64 {
+	var failed []int64
+	for _, v := range snap {
+		if v.State == Failed {
+			failed = append(failed, v.ID+1)
+		}
+	}
+	return failed
+}
+
+// indexSpec compacts the ascending indices into a list of numbers and ranges
+func indexSpec(indices []int64) string {
+	var parts []string
+	for i := 0; i < len(indices); {
+		j := i
+		for j+1 < len(indices) && indices[j+1] == indices[j]+1 {
+			j++
+		}
+		s := strconv.FormatInt(indices[i], 10)
+		if j > i {
+			s += "-" + strconv.FormatInt(indices[j], 10)
+		}
+		parts = append(parts, s)
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// indexDigits returns the number of digits in the largest index in snap
+func indexDigits(snap []WorkerStatus) int {
+	var max int64
+	for _, v := range snap {
+		if v.ID+1 > max {
+			max = v.ID + 1
+		}
+	}
+	return len(strconv.FormatInt(max, 10))
+}
+
+// indexText formats v's index for the index column, or blanks of the same
+// width for an information row
+func indexText(v WorkerStatus) string {
+	if v.Info {
+		return strings.Repeat(" ", v.indexDigits+2)
+	}
+	return fmt.Sprintf("[%*d]", v.indexDigits, v.Index)
+}
+
+// failedIndexLine lists the indices of the failed Workers in snap at the end
+// of the final report, or returns an empty string if none failed
+func (w *WorkerSet) failedIndexLine(snap []WorkerStatus) string {
+	failed := failedIndices(snap)
+	if len(failed) == 0 {
+		return ""
+	}
+	return "  " + fmt.Sprintf(w.messages.FailedIndices, indexSpec(failed))
+}
@@ -0,0 +1,73 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestIndexSpec(t *testing.T) {
+	for _, tc := range []struct {
+		indices []int64
+		want    string
+	}{
+		{nil, ""},
+		{[]int64{4}, "4"},
+		{[]int64{3, 7}, "3,7"},
+		{[]int64{1, 2}, "1-2"},
+		{[]int64{12, 13, 14, 15}, "12-15"},
+		{[]int64{3, 7, 12, 13, 14, 15}, "3,7,12-15"},
+		{[]int64{1, 2, 3, 5, 8, 9, 100}, "1-3,5,8-9,100"},
+	} {
+		if got := indexSpec(tc.indices); got != tc.want {
+			t.Errorf("%v: got %q, want %q", tc.indices, got, tc.want)
+		}
+	}
+}
+
+func TestIndexColumn(t *testing.T) {
+	ws := New(WithColor(false), WithIndexColumn(true))
+	for i := 1; i <= 120; i++ {
+		w := ws.Add(fmt.Sprintf("task %d", i))
+		if i == 3 || i == 7 || i >= 12 && i <= 15 {
+			w.Fail()
+		} else {
+			w.Done()
+		}
+	}
+	ws.AddInfo("context")
+	if got := ws.FailedIndexSpec(); got != "3,7,12-15" {
+		t.Errorf("spec %q", got)
+	}
+
+	// padded to the width of the largest index, 120
+	_, lines := ws.frame(false, true)
+	for _, want := range []string{"  [ OK ] [  1] task 1", "  [FAIL] [ 15] task 15", "  [ OK ] [120] task 120", "  [INFO]       context"} {
+		if !strings.Contains(strings.Join(lines, "\n"), want) {
+			t.Errorf("no line %q in\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+	if last := lines[len(lines)-1]; last != "  failed: 3,7,12-15" {
+		t.Errorf("report ends %q", last)
+	}
+	b, _ := json.Marshal(ws.Snapshot()[2])
+	if !strings.Contains(string(b), `"index":3`) {
+		t.Errorf("export lacks the index: %s", b)
+	}
+}
+
+func TestIndexColumnNarrow(t *testing.T) {
+	ws := New(WithColor(false), WithIndexColumn(true), WithSize(40, 24))
+	ws.Add("deploy").SetStatus("uploading")
+	_, lines := ws.frame(true, false)
+	if !strings.HasSuffix(lines[0], " [1] deploy uploading") {
+		t.Errorf("at 40 columns got %q", lines[0])
+	}
+	ws = New(WithColor(false), WithIndexColumn(true), WithSize(20, 24))
+	ws.Add("deploy").SetStatus("uploading")
+	// the index goes before the status
+	if _, lines = ws.frame(true, false); !strings.HasSuffix(lines[0], " deploy uploading") || strings.Contains(lines[0], "[1]") {
+		t.Errorf("at 20 columns got %q", lines[0])
+	}
+}
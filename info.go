@@ -0,0 +1,57 @@
+package multistatus
+
+// AddInfo adds a row showing text, such as the cache or cluster in use, that
+// is drawn with the Workers but marked as information. The row counts as
+// neither finished nor pending: it's left out of the totals, the header's
+// progress, summaries and the plan, and Print doesn't wait for it. Its
+// status may be changed with SetStatus, and it may be removed with Remove at
+// any time; Done and Fail have no effect. It is drawn without its elapsed
+// time.
+func (w *WorkerSet) AddInfo(text string) *Worker {
+	now := w.now()
+	worker := &Worker{State: Completed, parent: w, started: now, finished: now, queueIndex: -1, info: true, static: true, hideElapsed: true, done: make(chan struct{})}
+	close(worker.done)
+	worker.out.set = w
+	w.mu.Lock()
+	worker.id = w.nextID
+	worker.Name, worker.requested = text, text
+	w.nextID++
+	w.Workers = append(w.Workers, worker)
+	w.mu.Unlock()
+	w.touch()
+	w.log.printf("#%d %s: added as information", worker.id, stripControl(worker.Name))
+	return worker
+}
+
+// infoMarker returns the marker for information rows, colored on a terminal
+func (w *WorkerSet) infoMarker(isTerm bool) string {
+	t := w.theme
+	m := t.Info
+	if w.words(isTerm) {
+		m = t.InfoLabel
+	}
+	if m == "" {
+		m = "i"
+	}
+	return colorize(m, t.DimColor, w.level(isTerm))
+}
+
+// countable returns the statuses in snap that aren't information rows
+func countable(snap []WorkerStatus) []WorkerStatus {
+	n := 0
+	for _, v := range snap {
+		if !v.Info {
+			n++
+		}
+	}
+	if n == len(snap) {
+		return snap
+	}
+	vs := make([]WorkerStatus, 0, n)
+	for _, v := range snap {
+		if !v.Info {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
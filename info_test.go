@@ -0,0 +1,81 @@
+package multistatus
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInfoRows(t *testing.T) {
+	ws := New(WithColor(false), WithHeader("build"))
+	info := ws.AddInfo("using cache")
+	a := ws.Add("a")
+	ws.Add("b").Done()
+	info.SetStatus("at /tmp/x")
+	info.Fail() // no effect on information rows
+
+	for _, tc := range []struct {
+		tty  bool
+		want []string
+	}{
+		{true, []string{"build [==========          ] 1/2", "  ℹ using cache at /tmp/x", "  ⠋ a", "  ✔ b"}},
+		{false, []string{"build [==========          ] 1/2", "  [INFO] using cache at /tmp/x", "  [ .. ] a", "  [ OK ] b"}},
+	} {
+		if _, lines := ws.frame(tc.tty, false); !reflect.DeepEqual(lines, tc.want) {
+			t.Errorf("tty %v: got %q, want %q", tc.tty, lines, tc.want)
+		}
+	}
+	if n := ws.CountFast(Completed) + ws.CountFast(Pending); n != 2 {
+		t.Errorf("%d workers counted, want 2", n)
+	}
+
+	var plan strings.Builder
+	ws.PrintPlan(&plan)
+	if strings.Contains(plan.String(), "using cache") {
+		t.Errorf("information row in the plan:\n%s", plan.String())
+	}
+
+	a.Done()
+	sum := ws.Summary()
+	if sum.Completed != 2 || sum.Failed != 0 || len(sum.Workers) != 2 {
+		t.Errorf("summary counts %d completed, %d failed of %d", sum.Completed, sum.Failed, len(sum.Workers))
+	}
+	if code := ws.ExitCode(); code != 0 {
+		t.Errorf("exit code %d", code)
+	}
+}
+
+func TestInfoDoesntHoldPrint(t *testing.T) {
+	ws := New(WithSilent(true))
+	ws.AddInfo("connected to prod-eu")
+	ws.Add("a").Done()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := ws.Print(ctx); err != nil {
+		t.Fatalf("Print returned %v", err)
+	}
+
+	// only information left: nothing to wait for
+	ws = New(WithSilent(true))
+	ws.AddInfo("nothing to do")
+	if err := ws.Print(ctx); err != nil {
+		t.Fatalf("Print returned %v", err)
+	}
+}
+
+func TestInfoRemove(t *testing.T) {
+	ws := New(WithColor(false))
+	info := ws.AddInfo("warming up")
+	ws.Add("a")
+	if err := ws.Remove(info); err != nil {
+		t.Fatal(err)
+	}
+	if _, lines := ws.frame(false, false); !reflect.DeepEqual(lines, []string{"  [ .. ] a"}) {
+		t.Errorf("got %q after removing the information row", lines)
+	}
+	if n := ws.CountFast(Completed); n != 0 {
+		t.Errorf("removing the information row left %d completed", n)
+	}
+}
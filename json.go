@@ -0,0 +1,54 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// setJSON is the JSON representation of a WorkerSet
+type setJSON struct {
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Summary
+	Finished bool `json:"finished"`
+}
+
+// MarshalJSON encodes a snapshot of the WorkerSet, including per-state counts
+// and whether Print has returned. It extends the Summary's JSON, schema field
+// included, so DecodeSummary can read it.
+func (w *WorkerSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.jsonValue())
+}
+
+// WithJSONOutput has Print write, in place of any frames, the snapshot
+// encoded by MarshalJSON as a single line once the Workers have finished,
+// for programs whose output is read by another program.
+func WithJSONOutput(b bool) Option {
+	return func(w *WorkerSet) {
+		w.jsonOutput = b
+		w.explicit |= envFormat
+	}
+}
+
+// printJSON writes the final snapshot for WithJSONOutput, reported as
+// finished since nothing more is drawn
+func (w *WorkerSet) printJSON() error {
+	v := w.jsonValue()
+	v.Finished = true
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w.out, "%s\n", b)
+	return err
+}
+
+// jsonValue takes the snapshot encoded by MarshalJSON
+func (w *WorkerSet) jsonValue() setJSON {
+	v := setJSON{Summary: w.Summary()}
+	w.mu.Lock()
+	v.Finished = w.finished
+	v.Name, v.Labels = w.name, w.labels
+	w.mu.Unlock()
+	return v
+}
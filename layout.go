@@ -0,0 +1,147 @@
+package multistatus
+
+import "strings"
+
+// A Decoration is an optional part of a Worker's line that may be shrunk or
+// dropped when the line doesn't fit the terminal. The state marker and the
+// elapsed time are never shrunk.
+type Decoration int
+
+// Available Decorations
+const (
+	// DecorStatus is the Worker's status text; it is dropped entirely
+	DecorStatus Decoration = iota
+	// DecorName is the Worker's name; it is truncated in the middle, down
+	// to a minimum of 8 columns
+	DecorName
+	// DecorAttempts notes a retried Worker's earlier attempts; it is
+	// dropped entirely
+	DecorAttempts
+	// DecorSteps summarizes a Worker's steps when there isn't room to
+	// list them; it is dropped entirely
+	DecorSteps
+	// DecorIndex is the index column shown with WithIndexColumn; it is
+	// dropped entirely
+	DecorIndex
+	// DecorIcon is the icon set with SetIcon; it is dropped entirely
+	DecorIcon
+)
+
+// defaultShrinkOrder is the order Decorations are shrunk in unless
+// WithShrinkOrder is given
+var defaultShrinkOrder = []Decoration{DecorIndex, DecorAttempts, DecorSteps, DecorStatus, DecorIcon, DecorName}
+
+// WithShrinkOrder sets the order in which Decorations are shrunk or dropped
+// to fit narrow terminals. Decorations left out are never shrunk, though the
+// line is still cut at the terminal edge as a last resort.
+func WithShrinkOrder(order ...Decoration) Option {
+	return func(w *WorkerSet) {
+		w.shrinkOrder = order
+	}
+}
+
+// WithElapsed shows how long each Worker has been running, or ran for, at the
+// end of its line
+func WithElapsed(b bool) Option {
+	return func(w *WorkerSet) {
+		w.showElapsed = b
+	}
+}
+
+// segment is one space-separated part of a line
+type segment struct {
+	dec  Decoration
+	text string
+
+	// min is the narrowest the segment may be shrunk to, 0 meaning it may
+	// be dropped; fixed segments are never touched
+	min   int
+	fixed bool
+}
+
+// layoutLine builds the default line for v, shrinking its decorations in the
+// configured order until it fits within width columns. A width of 0 means
+// the line is never shrunk. The elapsed time is included if elapsed is set,
+// or if a pending Worker is running over its expected duration, unless it is
+// hidden with SetShowElapsed.
+func (w *WorkerSet) layoutLine(v WorkerStatus, marker string, width int, elapsed bool, level ColorLevel) string {
+	segs := []segment{{text: marker, fixed: true}}
+	if v.indexDigits > 0 {
+		segs = append(segs, segment{dec: DecorIndex, text: indexText(v)})
+	}
+	if v.Icon != "" {
+		segs = append(segs, segment{dec: DecorIcon, text: v.Icon})
+	}
+	segs = append(segs, segment{dec: DecorName, text: v.Name, min: 8})
+	if !v.State.finished() {
+		if a := w.attemptsText(v); a != "" {
+			segs = append(segs, segment{dec: DecorAttempts, text: a})
+		}
+		if s := w.stepsText(v); s != "" {
+			segs = append(segs, segment{dec: DecorSteps, text: s})
+		}
+	}
+	status := v.Status
+	if !v.RetryAt.IsZero() && !v.State.finished() {
+		status = w.backoffText(v)
+	}
+	if status == "" && v.Counter != nil {
+		status = w.counterLine(v)
+	}
+	if status == "" && v.Waiting {
+		status = w.messages.Waiting
+	}
+	if status == "" && v.State == Stopping {
+		status = w.messages.Stopping
+	}
+	if v.Finalizing {
+		status = w.messages.Finalizing
+	}
+	if status != "" {
+		segs = append(segs, segment{dec: DecorStatus, text: status})
+	}
+	if !v.HideElapsed && (elapsed || !v.State.finished() && v.overrun()) {
+		segs = append(segs, segment{text: w.elapsedText(v, level), fixed: true})
+	}
+
+	if width > 0 {
+		over := 2 - width
+		for _, s := range segs {
+			over += stringWidth(s.text) + 1
+		}
+		over--
+		for _, dec := range w.shrinkOrder {
+			if over <= 0 {
+				break
+			}
+			for i := range segs {
+				s := &segs[i]
+				if s.fixed || s.dec != dec || s.text == "" {
+					continue
+				}
+				sw := stringWidth(s.text)
+				if s.min == 0 {
+					s.text = ""
+					over -= sw + 1
+					continue
+				}
+				target := sw - over
+				if target < s.min {
+					target = s.min
+				}
+				if target < sw {
+					s.text = truncateMiddle(s.text, target)
+					over -= sw - stringWidth(s.text)
+				}
+			}
+		}
+	}
+
+	parts := make([]string, 0, len(segs))
+	for _, s := range segs {
+		if s.text != "" {
+			parts = append(parts, s.text)
+		}
+	}
+	return "  " + strings.Join(parts, " ")
+}
@@ -0,0 +1,70 @@
+package multistatus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// decorated returns a WorkerSet drawn width columns wide holding a pending
+// Worker with every decoration
+func decorated(width int, opts ...Option) *WorkerSet {
+	clock := newFakeClock()
+	ws := New(append([]Option{WithClock(clock), WithColor(false), WithSize(width, 24),
+		WithIndexColumn(true), WithElapsed(true), WithRetries(3)}, opts...)...)
+	worker := ws.Add("a-rather-long-worker-name")
+	worker.SetIcon("📦")
+	worker.SetStatus("uploading artifacts to the cache")
+	worker.attempts = []Attempt{{Elapsed: 2 * time.Second, Err: "timeout"}}
+	clock.Advance(12 * time.Second)
+	return ws
+}
+
+func TestShrinkOrder(t *testing.T) {
+	const (
+		full  = "  ⠋ [1] 📦 a-rather-long-worker-name (attempt 2; prev 2s ✗) uploading artifacts to the cache (12s)"
+		noIdx = "  ⠋ 📦 a-rather-long-worker-name (attempt 2; prev 2s ✗) uploading artifacts to the cache (12s)"
+		noAtt = "  ⠋ 📦 a-rather-long-worker-name uploading artifacts to the cache (12s)"
+		noSta = "  ⠋ 📦 a-rather-long-worker-name (12s)"
+		noIco = "  ⠋ a-rather-long-worker-name (12s)"
+	)
+	for _, tc := range []struct {
+		width int
+		want  string
+	}{
+		{200, full},
+		{98, full},
+		{97, noIdx},
+		{94, noIdx},
+		{93, noAtt},
+		{71, noAtt},
+		{70, noSta},
+		{38, noSta},
+		{37, noIco},
+		{35, noIco},
+		{34, "  ⠋ a-rather-lon…worker-name (12s)"},
+		{20, "  ⠋ a-rat…name (12s)"},
+	} {
+		if got := decorated(tc.width).frameLines(true, false)[0]; got != tc.want {
+			t.Errorf("width %d:\n got %q\nwant %q", tc.width, got, tc.want)
+		}
+	}
+
+	// every width fits, keeping the marker and elapsed time
+	for width := 20; width <= 200; width++ {
+		line := decorated(width).frameLines(true, false)[0]
+		if stringWidth(line) > width || !strings.HasPrefix(line, "  ⠋ ") || !strings.HasSuffix(line, " (12s)") {
+			t.Errorf("width %d: %q", width, line)
+		}
+	}
+}
+
+func TestShrinkOrderOption(t *testing.T) {
+	// the name is shrunk to its minimum before the status goes, and the
+	// decorations left out are kept
+	ws := decorated(70, WithShrinkOrder(DecorName, DecorStatus))
+	want := "  ⠋ [1] 📦 a-ra…ame (attempt 2; prev 2s ✗) (12s)"
+	if got := ws.frameLines(true, false)[0]; got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
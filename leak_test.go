@@ -0,0 +1,53 @@
+package multistatus
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkGoroutines fails t if more goroutines are running than before, once
+// those on their way out have had a moment to exit
+func checkGoroutines(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			buf = buf[:runtime.Stack(buf, true)]
+			var leaked []string
+			for _, g := range strings.Split(string(buf), "\n\n") {
+				if strings.Contains(g, "multistatus.") && !strings.Contains(g, "testing.tRunner") {
+					leaked = append(leaked, g)
+				}
+			}
+			t.Fatalf("%d goroutines running, %d before:\n%s", runtime.NumGoroutine(), before, strings.Join(leaked, "\n\n"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPrintCanceledLeavesNoGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ws := New(WithSilent(true), WithStopTimeout(10*time.Millisecond))
+	ws.Add("stuck")
+	ws.Subscribe(func(Event) {})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := ws.Print(ctx); err == nil {
+		t.Fatal("canceled Print returned no error")
+	}
+	checkGoroutines(t, before)
+}
+
+func TestBeginCanceledLeavesNoGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	ws, finish := Begin(ctx, WithSilent(true), WithStopTimeout(10*time.Millisecond))
+	ws.Add("stuck")
+	cancel()
+	finish()
+	checkGoroutines(t, before)
+}
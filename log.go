@@ -0,0 +1,136 @@
+package multistatus
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runLog appends timestamped plain-text records to a writer, independent of
+// what is shown on the terminal.
+type runLog struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+
+	// path is the file opened by open, for WithLogFile. Until then records
+	// are held in held.
+	path string
+	held *bytes.Buffer
+
+	// now stamps each record, by the WorkerSet's clock
+	now func() time.Time
+
+	// prefix starts each record, naming the WorkerSet
+	prefix string
+}
+
+// WithLogWriter appends a timestamped plain-text record of every Worker
+// transition to out. The log is flushed when Print returns.
+func WithLogWriter(out io.Writer) Option {
+	return func(w *WorkerSet) {
+		w.log = &runLog{w: bufio.NewWriter(out), now: w.now}
+		w.log.setPrefix(w.name)
+	}
+}
+
+// WithLogFile is like WithLogWriter but appends to the named file, creating
+// it if needed. The file is opened when Print starts, taking the records
+// made before then, and closed when Print returns. An error opening it is
+// returned from Print.
+func WithLogFile(path string) Option {
+	return func(w *WorkerSet) {
+		held := &bytes.Buffer{}
+		w.log = &runLog{w: bufio.NewWriter(held), path: path, held: held, now: w.now}
+		w.log.setPrefix(w.name)
+	}
+}
+
+// open opens the file given with WithLogFile, writing the records held until
+// now to it. It does nothing for other logs, once the file is open, or on a
+// nil runLog.
+func (l *runLog) open() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.path == "" || l.w == nil {
+		return nil
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("multistatus: opening log file: %v", err)
+	}
+	l.path = ""
+	l.w.Flush()
+	l.w.Reset(f)
+	l.w.Write(l.held.Bytes())
+	l.held = nil
+	l.closer = f
+	return nil
+}
+
+// printf writes a single record. It is safe to call on a nil runLog.
+func (l *runLog) printf(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.w == nil {
+		return
+	}
+	fmt.Fprintf(l.w, "%s %s%s\n", l.now().Format(time.RFC3339Nano), l.prefix, fmt.Sprintf(format, args...))
+}
+
+// setPrefix starts each record with the WorkerSet's name. It is safe to
+// call on a nil runLog.
+func (l *runLog) setPrefix(name string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prefix = ""
+	if name != "" {
+		l.prefix = "[" + stripControl(name) + "] "
+	}
+}
+
+// close flushes the log and closes the underlying file, if any. Records
+// written afterwards are discarded.
+func (l *runLog) close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.w == nil {
+		return nil
+	}
+	err := l.w.Flush()
+	if l.closer != nil {
+		if cerr := l.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	l.w = nil
+	return err
+}
+
+// stripControl removes control characters, including the escape character,
+// so user supplied text can't inject terminal sequences.
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,124 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logRecords reads the run log at path, dropping each record's timestamp
+func logRecords(t *testing.T, path string) []string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []string
+	for _, l := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		stamp, rest, ok := strings.Cut(l, " ")
+		if _, err := time.Parse(time.RFC3339Nano, stamp); !ok || err != nil {
+			t.Fatalf("record %q has no timestamp", l)
+		}
+		records = append(records, rest)
+	}
+	return records
+}
+
+func TestLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	var out syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithLogFile(path))
+	fetch, build := ws.Add("fetch"), ws.Add("build \x1b[31mred\x1b[0m")
+	fetch.SetStatus("downloading")
+	fetch.Done()
+	build.FailWith(errors.New("exit status 2"))
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"#0 fetch: added",
+		"#1 build [31mred[0m: added",
+		`#0 fetch: status "downloading"`,
+		"#0 fetch: pending -> completed",
+		"#1 build [31mred[0m: pending -> failed: exit status 2",
+		"print started",
+		"print finished: 1 completed, 1 failed",
+	}
+	got := logRecords(t, path)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("log is\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(out.String(), "\x1b[") {
+		t.Error("terminal output has no escapes")
+	}
+}
+
+func TestLogFileCanceled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	ws := New(WithSilent(true), WithLogFile(path))
+	ws.Go("wait", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ws.Print(ctx)
+	got := strings.Join(logRecords(t, path), "\n")
+	if !strings.Contains(got, "#0 wait: stopping -> canceled") || !strings.HasSuffix(got, "print finished: 0 completed, 0 failed") {
+		t.Errorf("log doesn't record the cancelation:\n%s", got)
+	}
+}
+
+func TestLogFileError(t *testing.T) {
+	ws := New(WithSilent(true), WithLogFile(filepath.Join(t.TempDir(), "missing", "run.log")))
+	ws.Add("task").Done()
+	if err := ws.Print(context.Background()); err == nil || !strings.Contains(err.Error(), "opening log file") {
+		t.Errorf("Print returned %v, want the error opening the log", err)
+	}
+}
+
+func TestLogFileOpenedByPrint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	ws := New(WithSilent(true), WithLogFile(path))
+	ws.Add("task").Done()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("the log file exists before Print: %v", err)
+	}
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"#0 task: added", "#0 task: pending -> completed", "print started", "print finished: 1 completed, 0 failed"}
+	if got := logRecords(t, path); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("log is %q, want %q", got, want)
+	}
+}
+
+func TestLogClock(t *testing.T) {
+	clock := newFakeClock()
+	var log, debug syncBuf
+	// the clock applies to logs given before it
+	ws := New(WithSilent(true), WithLogWriter(&log), WithDebugLog(&debug), WithClock(clock))
+	task := ws.Add("task")
+	ws.debug.printf("checked")
+	clock.Advance(1500 * time.Millisecond)
+	task.Done()
+	ws.Print(context.Background())
+	stamp := func(record string) string {
+		s, _, _ := strings.Cut(record, " ")
+		return s
+	}
+	records := strings.Split(log.String(), "\n")
+	if got, want := stamp(records[0]), "2024-01-01T12:00:00Z"; got != want {
+		t.Errorf("added at %s, want %s", got, want)
+	}
+	if got, want := stamp(records[1]), "2024-01-01T12:00:01.5Z"; got != want {
+		t.Errorf("completed at %s, want %s", got, want)
+	}
+	if got, want := stamp(debug.String()), "2024-01-01T12:00:00Z"; got != want {
+		t.Errorf("debug record at %s, want %s", got, want)
+	}
+}
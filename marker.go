@@ -0,0 +1,141 @@
+package multistatus
+
+import "strings"
+
+// MarkerStyle chooses between glyphs and words for marking Worker states
+type MarkerStyle int
+
+// Available MarkerStyles
+const (
+	// MarkerAuto uses glyphs on a terminal and words otherwise
+	MarkerAuto MarkerStyle = iota
+	// MarkerGlyphs always uses the Theme's glyphs, such as "✔"
+	MarkerGlyphs
+	// MarkerWords always uses the Theme's labels, such as "[ OK ]", which
+	// are easy to find with grep
+	MarkerWords
+)
+
+// WithMarkerStyle sets whether states are marked with glyphs or words,
+// MarkerAuto by default
+func WithMarkerStyle(s MarkerStyle) Option {
+	return func(w *WorkerSet) {
+		w.markerStyle = s
+	}
+}
+
+// words reports whether states are marked with words
+func (w *WorkerSet) words(isTerm bool) bool {
+	switch w.markerStyle {
+	case MarkerGlyphs:
+		return false
+	case MarkerWords:
+		return true
+	}
+	return !isTerm
+}
+
+// plainMarker returns the uncolored marker for state s
+func (w *WorkerSet) plainMarker(s WorkerState, words bool) string {
+	t := w.theme
+	if words {
+		return [numStates]string{
+			Completed: t.CompletedLabel,
+			Failed:    t.FailedLabel,
+			Pending:   t.PendingLabel,
+			Stopping:  t.StoppingLabel,
+			Canceled:  t.CanceledLabel,
+		}[s]
+	}
+	return [numStates]string{
+		Completed: t.Completed,
+		Failed:    t.Failed,
+		Pending:   t.Pending,
+		Stopping:  t.Stopping,
+		Canceled:  t.Canceled,
+	}[s]
+}
+
+// markers returns the marker of each state for a frame, colored on a
+// terminal. With glyphs on a terminal the spinner advances to mark pending
+// Workers.
+func (w *WorkerSet) markers(isTerm bool) [numStates]string {
+	t := w.theme
+	words := w.words(isTerm)
+	level := w.level(isTerm)
+	var m [numStates]string
+	for s := range m {
+		m[s] = w.plainMarker(WorkerState(s), words)
+	}
+	if isTerm && !words {
+		m[Pending] = w.spinner.Next()
+	}
+	m[Completed] = colorize(m[Completed], t.CompletedColor, level)
+	m[Failed] = colorize(m[Failed], t.FailedColor, level)
+	m[Stopping] = colorize(m[Stopping], t.CanceledColor, level)
+	m[Canceled] = colorize(m[Canceled], t.CanceledColor, level)
+	return m
+}
+
+// idleMarker returns the marker for pending Workers that aren't running, such
+// as those backing off, dimmed on a terminal. It is the glyph or, in the word
+// style, the label, falling back to the Pending marker without the spinner.
+func (w *WorkerSet) idleMarker(glyph, label string, isTerm bool) string {
+	m := glyph
+	if w.words(isTerm) {
+		m = label
+	}
+	if m == "" {
+		m = w.plainMarker(Pending, w.words(isTerm))
+	}
+	return colorize(m, w.theme.DimColor, w.level(isTerm))
+}
+
+// WithMarkerFunc chooses each Worker's marker by calling fn every frame with
+// the Worker's status and the number of the frame, counting from 0, so
+// markers can be animated. The Theme's marker is used when fn returns "" or
+// panics. Markers are padded to the width of the widest in the frame so the
+// Workers' names stay lined up.
+func WithMarkerFunc(fn func(WorkerStatus, int) string) Option {
+	return func(w *WorkerSet) {
+		w.markerFunc = fn
+	}
+}
+
+// customMarkers calls the marker func for each of vs, returning a function
+// giving the padded marker of each, and fallback's for any other Worker
+func (w *WorkerSet) customMarkers(vs []WorkerStatus, fallback func(WorkerStatus) string) func(WorkerStatus) string {
+	marks := make(map[int64]string, len(vs))
+	widest := 0
+	for _, v := range vs {
+		m := w.callMarkerFunc(v)
+		if m == "" {
+			m = fallback(v)
+		}
+		marks[v.ID] = m
+		if mw := stringWidth(m); mw > widest {
+			widest = mw
+		}
+	}
+	for id, m := range marks {
+		marks[id] = m + strings.Repeat(" ", widest-stringWidth(m))
+	}
+	return func(v WorkerStatus) string {
+		if m, ok := marks[v.ID]; ok {
+			return m
+		}
+		return fallback(v)
+	}
+}
+
+// callMarkerFunc returns the marker func's result for v, with line breaks
+// removed, or "" if it panics
+func (w *WorkerSet) callMarkerFunc(v WorkerStatus) (m string) {
+	defer func() {
+		if recover() != nil {
+			m = ""
+		}
+	}()
+	m = w.markerFunc(v, w.frameCount)
+	return strings.NewReplacer("\r", "", "\n", "").Replace(m)
+}
@@ -0,0 +1,104 @@
+package multistatus
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMarkerFunc(t *testing.T) {
+	var frames []int
+	ws := New(WithColor(false), WithMarkerFunc(func(v WorkerStatus, frame int) string {
+		if v.Name == "a" {
+			frames = append(frames, frame)
+		}
+		switch v.Name {
+		case "slow":
+			return "⏰"
+		case "broken":
+			panic("oops")
+		case "multi":
+			return "!\n"
+		}
+		return ""
+	}))
+	ws.Add("a")
+	ws.Add("slow")
+	ws.Add("broken").Done()
+	ws.Add("multi")
+
+	ws.frame(true, false)
+	_, lines := ws.frame(true, false)
+	// the width-2 emoji sets the column for every name
+	col := -1
+	for _, l := range lines {
+		i := strings.LastIndex(l, " ") + 1
+		if c := stringWidth(l[:i]); col < 0 {
+			col = c
+		} else if c != col {
+			t.Errorf("names not lined up:\n%s", strings.Join(lines, "\n"))
+			break
+		}
+	}
+	if col != 5 {
+		t.Errorf("names start at column %d, want 5:\n%s", col, strings.Join(lines, "\n"))
+	}
+	if !strings.HasPrefix(lines[1], "  ⏰ slow") {
+		t.Errorf("got %q", lines[1])
+	}
+	// a panic falls back to the Theme's marker
+	if lines[2] != "  ✔  broken" {
+		t.Errorf("got %q", lines[2])
+	}
+	if lines[3] != "  !  multi" {
+		t.Errorf("got %q", lines[3])
+	}
+	if len(frames) != 2 || frames[1] != frames[0]+1 {
+		t.Errorf("called for frames %v", frames)
+	}
+}
+
+func TestMarkerStyleGolden(t *testing.T) {
+	clearEnv(t)
+	for _, tc := range []struct {
+		golden string
+		style  MarkerStyle
+		tty    bool
+	}{
+		{"words_plain.golden", MarkerAuto, false},
+		{"words_tty.golden", MarkerWords, true},
+		{"glyphs_plain.golden", MarkerGlyphs, false},
+	} {
+		var buf bytes.Buffer
+		ws := New(WithOutput(&buf), WithTTY(tc.tty), WithColor(false), WithMarkerStyle(tc.style))
+		goldenWorkers(ws)
+		if err := ws.Print(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		got := buf.Bytes()
+		if tc.tty {
+			// the last frame, without the escapes drawing it
+			vt := newVT(24)
+			vt.feed(buf.String())
+			got = []byte(strings.Join(vt.lines(), "\n") + "\n")
+		}
+		compareGolden(t, tc.golden, got)
+	}
+}
+
+func TestMarkerWordsGrep(t *testing.T) {
+	var buf bytes.Buffer
+	ws := New(WithOutput(&buf), WithTTY(false))
+	goldenWorkers(ws)
+	ws.Print(context.Background())
+	var failed []string
+	for _, l := range strings.Split(buf.String(), "\n") {
+		if strings.Contains(l, "[FAIL]") {
+			failed = append(failed, strings.Fields(l)[1])
+		}
+	}
+	if len(failed) != 1 || failed[0] != "test" {
+		t.Errorf("grep for [FAIL] found %q", failed)
+	}
+}
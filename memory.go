@@ -0,0 +1,57 @@
+package multistatus
+
+import (
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+)
+
+// MemoryUsage estimates the memory held by a WorkerSet's records of its
+// Workers, in bytes
+type MemoryUsage struct {
+	// Output is held by the output captured by Workers, as bounded by
+	// WithOutputLimits
+	Output int64 `json:"output"`
+
+	// Notes is held by the Workers' notes, and History by their
+	// TransitionRecords, as bounded by WithHistoryLimit
+	Notes   int64 `json:"notes"`
+	History int64 `json:"history"`
+}
+
+// Total returns the memory held by all the records
+func (m MemoryUsage) Total() int64 {
+	return m.Output + m.Notes + m.History
+}
+
+// MemoryUsage estimates the memory held by the WorkerSet's records of its
+// Workers, for monitoring long runs
+func (w *WorkerSet) MemoryUsage() MemoryUsage {
+	u := MemoryUsage{Output: atomic.LoadInt64(&w.outputBytes)}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, worker := range w.members() {
+		u.Notes += int64(worker.noteBytes)
+		for _, r := range worker.history {
+			u.History += int64(unsafe.Sizeof(r)) + int64(len(r.Err)+len(r.Status))
+		}
+	}
+	return u
+}
+
+// groupDigits formats n with commas between groups of three digits, as in
+// "4,312"
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	neg := n < 0
+	if neg {
+		s = s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package multistatus
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// heapInUse returns the bytes of live heap after a collection
+func heapInUse() int64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapAlloc)
+}
+
+func TestOutputBounded(t *testing.T) {
+	ws := New(WithSilent(true))
+	w := ws.Add("chatty")
+	line := []byte(strings.Repeat("x", 999) + "\n")
+	before := heapInUse()
+	// 20MB, a line at a time
+	for i := 0; i < 20000; i++ {
+		w.Write(line)
+	}
+	grown := heapInUse() - before
+
+	// 65 lines of 999 bytes fit in 64KiB
+	out := w.Output()
+	if len(out) != 66 || out[0] != "[… 19,935 lines truncated …]" {
+		t.Errorf("got %d lines starting %q", len(out), out[0])
+	}
+	if u := ws.MemoryUsage(); u.Output != 65*999 || u.Total() < u.Output {
+		t.Errorf("usage %+v, want %d bytes of output", u, 65*999)
+	}
+	if grown > 1<<20 {
+		t.Errorf("heap grew by %d bytes keeping 64KiB of output", grown)
+	}
+}
+
+func TestOutputSetLimit(t *testing.T) {
+	ws := New(WithSilent(true), WithOutputLimits(0, 3000))
+	a, b := ws.Add("a"), ws.Add("b")
+	line := []byte(strings.Repeat("y", 99) + "\n")
+	for i := 0; i < 20; i++ {
+		a.Write(line)
+	}
+	for i := 0; i < 20; i++ {
+		b.Write(line)
+	}
+	if u := ws.MemoryUsage(); u.Output > 3000 {
+		t.Errorf("%d bytes kept over the limit of 3000", u.Output)
+	}
+	// b writing more pushed out its own oldest lines once the limit was
+	// reached, not a's
+	if n := a.out.truncated(); n != 0 {
+		t.Errorf("a dropped %d lines for b", n)
+	}
+	if n := b.out.truncated(); n == 0 {
+		t.Error("b kept all its lines over the limit")
+	}
+
+	// a removed Worker's output no longer counts toward the limit
+	a.Done()
+	if err := ws.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if u := ws.MemoryUsage(); u.Output != int64(b.out.size) {
+		t.Errorf("%d bytes counted after removing a, want b's %d", u.Output, b.out.size)
+	}
+}
+
+func TestGroupDigits(t *testing.T) {
+	for n, want := range map[int]string{0: "0", 999: "999", 1000: "1,000", 4312: "4,312", 1234567: "1,234,567", -4312: "-4,312"} {
+		if got := groupDigits(n); got != want {
+			t.Errorf("%d: got %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package multistatus
+
+import "fmt"
+
+// Messages holds the human-readable phrases the WorkerSet prints, so they can
+// be replaced, for example to translate them. Format strings may use explicit
+// argument indexes, such as %[2]d, to reorder their arguments.
+//
+// Phrases that depend on a count are given as a list of plural forms, from
+// which Plural picks one.
+type Messages struct {
+	// Completed, Failed and CanceledState name the finished states in
+	// sentences, and PendingState names the others
+	Completed     string
+	Failed        string
+	CanceledState string
+	PendingState  string
+
+	// Stopping is shown in place of the status of a Stopping Worker
+	Stopping string
+
+	// Finished announces a finished Worker in accessible mode. Its
+	// arguments are the Worker's name, the state name, the number of
+	// Workers finished so far, the total, and the number remaining.
+	Finished string
+
+	// AllFinished ends accessible mode output once every Worker finished.
+	// Its arguments are the total, the number completed, and the number
+	// failed, and its form is chosen by the total.
+	AllFinished []string
+
+	// Canceled ends accessible mode output when Print is canceled
+	Canceled string
+
+	// Compact counts the finished Workers on the single line drawn on a
+	// short terminal. Its arguments are the number finished and the
+	// number of Workers, which chooses the form.
+	Compact []string
+
+	// Failures is appended to the header's title once a Worker fails.
+	// Its argument is the number of failures.
+	Failures []string
+
+	// More follows the Workers shown when not all of them fit. Its
+	// argument is the number left out.
+	More []string
+
+	// Page notes which Workers are shown with WithRotateVisible. Its
+	// arguments are the positions of the first and last Workers shown
+	// and the number of pending Workers, which chooses the form.
+	Page []string
+
+	// Counter shows the progress of a Counter with a total. Its arguments
+	// are the number of tasks counted, the total, and the rate per
+	// second. CounterNoTotal is used without a total, and has no total
+	// argument.
+	Counter        string
+	CounterNoTotal string
+
+	// Rate follows the header with WithRateSparkline. Its arguments are
+	// the sparkline of recent throughput and the latest rate, such as
+	// "2.3k/s".
+	Rate string
+
+	// CounterFailures counts a Counter's failed tasks. Its argument is the
+	// number failed.
+	CounterFailures []string
+
+	// ErrorGroup introduces an error shared by several failed Workers in
+	// the final report. Its arguments are the number of Workers, which
+	// chooses the form, and their names.
+	ErrorGroup []string
+
+	// Plan starts the output of PrintPlan. Its argument is the number of
+	// Workers, which chooses the form. PlanConcurrency follows it with the
+	// concurrency limit, if any, and PlanGroup and PlanPriority note a
+	// Worker's group and priority.
+	Plan            []string
+	PlanConcurrency string
+	PlanGroup       string
+	PlanPriority    string
+
+	// Attempt notes the attempt of a retried Worker on its line. Its
+	// arguments are the attempt number and the durations of the last few
+	// attempts. FailedAttempt lists a failed attempt in the final report;
+	// its arguments are the attempt number, its duration and its error.
+	Attempt       string
+	FailedAttempt string
+
+	// RetryIn replaces the status of a Worker backing off before its next
+	// attempt. Its arguments are the time left, the number of the next
+	// attempt, and the most attempts allowed.
+	RetryIn string
+
+	// Steps summarizes a Worker's steps when there isn't room to list
+	// them. Its arguments are the number of steps checked off and the
+	// number of steps, which chooses the form.
+	Steps []string
+
+	// Finalizing is shown in place of the status of a Worker while the
+	// finalizers registered with Defer run
+	Finalizing string
+
+	// CleanupFailed is noted on a Worker whose finalizer failed with
+	// WithFinalizerWarnings. Its argument is the error.
+	CleanupFailed string
+
+	// Waiting is shown in place of the status of a Worker waiting for its
+	// turn to start
+	Waiting string
+
+	// StartRate is added to the header with WithStartRate. Its argument
+	// is the rate, such as "10/s".
+	StartRate string
+
+	// MoreLines follows an error cut short in the final report. Its
+	// argument is the number of lines left out.
+	MoreLines []string
+
+	// Truncated starts a Worker's Output when earlier lines were dropped.
+	// Its argument is the number dropped, with its digits grouped.
+	Truncated []string
+
+	// MoreNotes follows the notes of a Worker that recorded too many. Its
+	// argument is the number dropped.
+	MoreNotes []string
+
+	// NoteAttempt starts a note recorded by a retried Worker. Its argument
+	// is the attempt number.
+	NoteAttempt string
+
+	// FailureTimeline introduces the failures listed with
+	// WithFailureTimeline. Its arguments are the number of failures,
+	// which chooses the form, and the times of the first and last.
+	FailureTimeline []string
+
+	// RenderStats ends the final report when WithRenderStats is given.
+	// Its arguments are the number of frames, which chooses the form, the
+	// average times taken to lay out and write a frame, and the number of
+	// changes that didn't get a frame of their own.
+	RenderStats []string
+
+	// WithinThreshold ends the final report when the failures are within
+	// the threshold set by WithFailureThreshold or WithFailureRatio. Its
+	// arguments are the number of failures, which chooses the form, and the
+	// number tolerated.
+	WithinThreshold []string
+
+	// ActiveTime replaces the elapsed time of a finished Worker that spent
+	// a good part of it waiting, such as between retries. Its arguments
+	// are the elapsed time and the time spent running.
+	ActiveTime string
+
+	// Paused follows the header while Workers are paused. Its argument is
+	// the number of them.
+	Paused []string
+
+	// OverBudget follows the header while Workers run over their expected
+	// durations. Its argument is the number of them.
+	OverBudget []string
+
+	// Overrun lists a Worker that took longer than expected at the end of
+	// the final report. Its arguments are the Worker's name, how long it
+	// took and how long it was expected to take.
+	Overrun string
+
+	// FailedIndices ends the final report with WithIndexColumn when
+	// Workers failed. Its argument is their indices, as given by
+	// FailedIndexSpec.
+	FailedIndices string
+
+	// Draining follows the header while the WorkerSet drains. Its
+	// argument is the number of Workers left.
+	Draining []string
+
+	// Stage summarizes a finished Pipeline stage. Its arguments are the
+	// stage's name, the number completed, and the number failed.
+	Stage string
+
+	// Skipped marks a Pipeline stage skipped after an earlier stage
+	// failed. Its argument is the stage's name.
+	Skipped string
+
+	// NewlyFailing, Fixed, Added and Removed list Workers in the report
+	// written by SummaryDiff.WriteText. Their arguments are the number of
+	// Workers, which chooses the form, and their names. Slower notes a
+	// Worker that took longer, with its name and how many times as long it
+	// took, and NoChanges is written when nothing differs.
+	NewlyFailing []string
+	Fixed        []string
+	Added        []string
+	Removed      []string
+	Slower       string
+	NoChanges    string
+
+	// Plural returns the form of a phrase to use for the count n, with one
+	// entry in forms for each plural category of the language. If nil,
+	// English rules are used: forms[0] for one and forms[1] otherwise.
+	Plural func(n int, forms []string) string
+}
+
+// DefaultMessages are the English Messages used unless WithMessages is given
+var DefaultMessages = Messages{
+	Completed:       "completed",
+	Failed:          "failed",
+	CanceledState:   "canceled",
+	PendingState:    "pending",
+	Stopping:        "stopping…",
+	Finished:        "%s %s. %d of %d finished, %d remaining.",
+	AllFinished:     []string{"All %d task finished: %d completed, %d failed.", "All %d tasks finished: %d completed, %d failed."},
+	Canceled:        "Canceled.",
+	Compact:         []string{"%d/%d task done", "%d/%d tasks done"},
+	Failures:        []string{" (%d failure)", " (%d failures)"},
+	More:            []string{"  … and %d more", "  … and %d more"},
+	Page:            []string{"  showing %d–%d of %d running", "  showing %d–%d of %d running"},
+	Waiting:         "waiting",
+	Finalizing:      "finalizing…",
+	CleanupFailed:   "cleanup failed: %v",
+	Steps:           []string{"%d/%d step", "%d/%d steps"},
+	Attempt:         "attempt %d; prev %s",
+	FailedAttempt:   "attempt %d failed after %s: %s",
+	RetryIn:         "retrying in %s (attempt %d/%d)",
+	Plan:            []string{"would run %d task", "would run %d tasks"},
+	PlanConcurrency: ", at most %d at a time",
+	PlanGroup:       "group %s",
+	PlanPriority:    "priority %d",
+	ErrorGroup:      []string{"%d worker failed with this error: %s", "%d workers failed with this error: %s"},
+	Counter:         "%d/%d · %.1f/s",
+	CounterNoTotal:  "%d · %.1f/s",
+	Rate:            "rate %s %s",
+	CounterFailures: []string{"%d failed", "%d failed"},
+	StartRate:       "· start rate: %s",
+	MoreLines:       []string{"… %d more line", "… %d more lines"},
+	MoreNotes:       []string{"… %d more note", "… %d more notes"},
+	NoteAttempt:     "attempt %d: ",
+	Truncated:       []string{"[… %s line truncated …]", "[… %s lines truncated …]"},
+	FailureTimeline: []string{"%d failure at %[2]s", "%d failures between %s and %s"},
+	RenderStats:     []string{"rendered %d frame, avg %s layout, %s write, %d skipped", "rendered %d frames, avg %s layout, %s write, %d skipped"},
+	WithinThreshold: []string{"%d failure (within threshold of %d)", "%d failures (within threshold of %d)"},
+	ActiveTime:      "%s, %s active",
+	Paused:          []string{"%d paused", "%d paused"},
+	OverBudget:      []string{"%d task over budget", "%d tasks over budget"},
+	Overrun:         "%s took %s, expected %s",
+	FailedIndices:   "failed: %s",
+	Draining:        []string{"draining — %d task remaining", "draining — %d tasks remaining"},
+	Stage:           "%s: %d completed, %d failed",
+	Skipped:         "%s: skipped",
+	NewlyFailing:    []string{"%d newly failing: %s", "%d newly failing: %s"},
+	Fixed:           []string{"%d fixed: %s", "%d fixed: %s"},
+	Added:           []string{"%d added: %s", "%d added: %s"},
+	Removed:         []string{"%d removed: %s", "%d removed: %s"},
+	Slower:          "%s %.1fx slower",
+	NoChanges:       "no changes",
+	Plural:          englishPlural,
+}
+
+// WithMessages replaces the phrases the WorkerSet prints. Start from a copy
+// of DefaultMessages to replace only some of them.
+func WithMessages(m Messages) Option {
+	return func(w *WorkerSet) {
+		if m.Plural == nil {
+			m.Plural = englishPlural
+		}
+		w.messages = m
+	}
+}
+
+func englishPlural(n int, forms []string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+	if n == 1 || len(forms) == 1 {
+		return forms[0]
+	}
+	return forms[1]
+}
+
+// state returns the name of a finished state
+func (m *Messages) state(s WorkerState) string {
+	switch s {
+	case Failed:
+		return m.Failed
+	case Canceled:
+		return m.CanceledState
+	case Pending, Stopping:
+		return m.PendingState
+	}
+	return m.Completed
+}
+
+// pluralf formats the form of forms chosen for n with args
+func (m *Messages) pluralf(n int, forms []string, args ...interface{}) string {
+	return fmt.Sprintf(m.Plural(n, forms), args...)
+}
@@ -0,0 +1,89 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// polishPlural picks among the one, few and many forms as Polish does
+func polishPlural(n int, forms []string) string {
+	switch {
+	case n == 1:
+		return forms[0]
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 10 || n%100 >= 20):
+		return forms[1]
+	}
+	return forms[2]
+}
+
+func polishMessages() Messages {
+	m := DefaultMessages
+	m.Completed, m.Failed = "ukończone", "nieudane"
+	m.AllFinished = []string{
+		"Wszystkie %d zadanie zakończone: %d ukończone, %d nieudane.",
+		"Wszystkie %d zadania zakończone: %d ukończone, %d nieudane.",
+		"Wszystkie %d zadań zakończonych: %d ukończone, %d nieudane.",
+	}
+	m.Finished = "%[1]s: %[2]s (%[3]d/%[4]d)."
+	m.More = []string{"  … i %d więcej", "  … i %d więcej", "  … i %d więcej"}
+	m.Failures = []string{" (%d błąd)", " (%d błędy)", " (%d błędów)"}
+	m.Plural = polishPlural
+	return m
+}
+
+func TestMessages(t *testing.T) {
+	for _, tc := range []struct {
+		total, failed int
+		want          []string
+	}{
+		{1, 0, []string{"a0: ukończone (1/1).", "Wszystkie 1 zadanie zakończone: 1 ukończone, 0 nieudane."}},
+		{3, 1, []string{"a0: nieudane (1/3).", "Wszystkie 3 zadania zakończone: 2 ukończone, 1 nieudane."}},
+		{5, 2, []string{"Wszystkie 5 zadań zakończonych: 3 ukończone, 2 nieudane."}},
+		{22, 0, []string{"Wszystkie 22 zadania zakończone: 22 ukończone, 0 nieudane."}},
+	} {
+		var out syncBuf
+		ws := New(WithOutput(&out), WithAccessible(true), WithMessages(polishMessages()))
+		for i := 0; i < tc.total; i++ {
+			w := ws.Add("a" + string(rune('0'+i%10)))
+			if i < tc.failed {
+				w.FailWith(errors.New("broke"))
+			} else {
+				w.Done()
+			}
+		}
+		if err := ws.Print(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%d tasks: output doesn't say %q:\n%s", tc.total, want, out.String())
+			}
+		}
+	}
+}
+
+func TestMessagesLive(t *testing.T) {
+	ws := New(WithMessages(polishMessages()), WithHeader("build"), WithMaxRows(4), WithColor(false))
+	for i := 0; i < 6; i++ {
+		ws.Add("task").FailWith(errors.New("broke"))
+	}
+	ws.Add("pending")
+	lines := ws.frameLines(true, false)
+	if !strings.HasPrefix(lines[0], "build (6 błędów) ") {
+		t.Errorf("header is %q", lines[0])
+	}
+	if last := lines[len(lines)-1]; last != "  … i 5 więcej" {
+		t.Errorf("last line is %q", last)
+	}
+}
+
+func TestMessagesDefaultPlural(t *testing.T) {
+	m := DefaultMessages
+	m.Plural = nil
+	ws := New(WithMessages(m))
+	if got := ws.messages.pluralf(2, m.Failures, 2); got != " (2 failures)" {
+		t.Errorf("got %q", got)
+	}
+}
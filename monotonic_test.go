@@ -0,0 +1,67 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClockSetBack(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithRetries(1), WithHeader("run"),
+		WithElapsed(true), WithRateSparkline(time.Second, 5),
+		WithBackoff(func(int) time.Duration { return 0 }))
+	paused := ws.Add("paused")
+	counter := ws.AddCounter("counter", 10)
+	clock.Advance(5 * time.Second)
+	paused.Pause()
+	counter.Incr()
+	ws.Snapshot()
+
+	clock.Set(clock.Now().Add(-time.Hour))
+	paused.Resume()
+	counter.Incr()
+	ws.Snapshot()
+	var calls int32
+	ws.Go("retried", func(ctx context.Context) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			clock.Set(clock.Now().Add(-time.Hour))
+			return errors.New("broke")
+		}
+		return nil
+	})
+	paused.Done()
+	counter.Fail()
+	counter.Finish()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range ws.Snapshot() {
+		if v.Elapsed < 0 || v.Active < 0 {
+			t.Errorf("%s has Elapsed %v and Active %v", v.Name, v.Elapsed, v.Active)
+		}
+		for _, a := range v.Attempts {
+			if a.Elapsed < 0 {
+				t.Errorf("%s has an attempt of %v", v.Name, a.Elapsed)
+			}
+		}
+		if v.Counter != nil {
+			for _, n := range v.Counter.Rates {
+				if n < 0 {
+					t.Errorf("%s has rates %v", v.Name, v.Counter.Rates)
+				}
+			}
+		}
+	}
+	sum := ws.Summary()
+	if sum.Duration < 0 || sum.PausedDuration < 0 {
+		t.Errorf("Summary has Duration %v and PausedDuration %v", sum.Duration, sum.PausedDuration)
+	}
+	if header := ws.frameLines(false, true)[0]; strings.Contains(header, "-") {
+		t.Errorf("header shows a negative time: %q", header)
+	}
+}
@@ -37,15 +37,16 @@ Usage:
 package multistatus
 
 import (
+	"container/heap"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	spin "github.com/tj/go-spin"
-	"golang.org/x/crypto/ssh/terminal"
 )
 
 // WorkerState represent the current state of a Worker
@@ -56,132 +57,1024 @@ const (
 	Completed WorkerState = iota
 	Failed
 	Pending
+
+	// Stopping is a Pending Worker that is winding down after Print was
+	// canceled; see Worker.OnCancel
+	Stopping
+
+	// Canceled is a Worker that failed because Print was canceled
+	Canceled
+
+	numStates
 )
 
+var stateNames = [numStates]string{
+	Completed: "completed",
+	Failed:    "failed",
+	Pending:   "pending",
+	Stopping:  "stopping",
+	Canceled:  "canceled",
+}
+
+// MarshalText encodes the WorkerState as its name, e.g. "completed"
+func (s WorkerState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a WorkerState from its name
+func (s *WorkerState) UnmarshalText(b []byte) error {
+	for i, name := range stateNames {
+		if name == string(b) {
+			*s = WorkerState(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("multistatus: unknown worker state %q", b)
+}
+
+// finished reports whether s is a final state
+func (s WorkerState) finished() bool {
+	return s == Completed || s == Failed || s == Canceled
+}
+
+func (s WorkerState) String() string {
+	if s < 0 || s >= numStates {
+		return fmt.Sprintf("WorkerState(%d)", int(s))
+	}
+	return stateNames[s]
+}
+
 // Worker is used to track the status of a worker task
 type Worker struct {
 	State  WorkerState
 	Name   string
 	parent *WorkerSet
+
+	// guarded by parent.mu
+	status   string
+	err      error
+	started  time.Time
+	finished time.Time
+
+	// nextStatus is the latest status held back by WithStatusThrottle
+	// until statusTimer fires; statusSet is when the status was last set
+	nextStatus  string
+	statusSet   time.Time
+	statusTimer *time.Timer
+
+	history        []TransitionRecord
+	historyDropped int
+	attempts       []Attempt
+	steps          []Step
+
+	// fraction is the progress set with SetProgress, if hasFraction is set
+	fraction    float64
+	hasFraction bool
+
+	// retryAt is when the next attempt starts while the Worker backs off
+	retryAt time.Time
+
+	// executed is set once a Worker started with Go, Command or Do makes
+	// its first attempt. active is the time taken by its attempts that
+	// have ended, and attemptStart is when the current one began, if one
+	// is running.
+	executed     bool
+	active       time.Duration
+	attemptStart time.Time
+
+	// counter is set for Workers added with AddCounter
+	counter *Counter
+
+	// deferred holds the finalizers registered with Defer, and finalizing
+	// is set while they run
+	deferred   []func() error
+	finalizing bool
+
+	notes        []string
+	noteBytes    int
+	notesDropped int
+
+	// watchesCtx and onCancel mark Workers that wind down when canceled
+	watchesCtx bool
+	onCancel   []func()
+
+	out output
+
+	id      int64
+	removed bool // guarded by parent.mu
+
+	// requested is the name the Worker was added with
+	requested string
+
+	// info is set for rows added with AddInfo
+	info bool
+
+	// done is closed once the Worker finishes
+	done chan struct{}
+
+	// expected is set by SetExpectedDuration, guarded by parent.mu
+	expected time.Duration
+
+	// group is set by SetGroup, guarded by parent.mu
+	group string
+
+	// blocked is set by SetBlocked, guarded by parent.mu
+	blocked bool
+
+	// icon is set by SetIcon, guarded by parent.mu
+	icon string
+
+	// static and hideElapsed are set by SetAnimated and SetShowElapsed,
+	// guarded by parent.mu
+	static      bool
+	hideElapsed bool
+
+	// pausedAt is when Pause was called, or zero unless paused, and paused
+	// the time spent paused before then, guarded by parent.mu
+	pausedAt time.Time
+	paused   time.Duration
+
+	// pin orders pinned Workers, 0 for those that aren't, guarded by
+	// parent.mu
+	pin int64
+
+	// scheduling, guarded by parent.mu
+	priority   int
+	queueIndex int
+	fn         func(context.Context) error
+
+	// executing is set while run calls the Worker's function, guarded by
+	// parent.mu
+	executing bool
 }
 
-// Done will set the Worker.State to Completed and decrement the parent
-// WorkerSet's sync.WaitGroup
+// Done will set the Worker.State to Completed, letting Print return once no
+// other Workers are left to finish
 func (w *Worker) Done() {
-	w.State = Completed
-	w.parent.wg.Done()
+	w.transition(Completed, nil)
 }
 
-// Fail will set the Worker.State to Fail and decrement the parent
-// WorkerSet's sync.WaitGroup
+// Fail will set the Worker.State to Fail, letting Print return once no
+// other Workers are left to finish
 func (w *Worker) Fail() {
-	w.State = Failed
-	w.parent.wg.Done()
+	w.transition(Failed, nil)
+}
+
+// FailWith is like Fail, but records err as the reason the Worker failed
+func (w *Worker) FailWith(err error) {
+	w.transition(Failed, err)
 }
 
-// Active will return `true` if the Worker.State is Pending
+// Err returns the error the Worker failed with, if any
+func (w *Worker) Err() error {
+	w.parent.mu.Lock()
+	defer w.parent.mu.Unlock()
+	return w.err
+}
+
+// Context returns a context that is canceled when the WorkerSet's Print is
+// canceled, for Workers to stop early. A Worker that asked for its Context is
+// shown as Stopping once it's canceled, until the Worker finishes.
+func (w *Worker) Context() context.Context {
+	ws := w.parent
+	ws.mu.Lock()
+	w.watchesCtx = true
+	ws.mu.Unlock()
+	return ws.ctx
+}
+
+// transition moves a Pending or Stopping Worker into the given final state.
+// A Worker failing because Print was canceled is Canceled instead, and either
+// may be changed by the TransitionHook. Workers that have already finished
+// are left untouched, so repeated calls to Done or Fail are harmless.
+func (w *Worker) transition(to WorkerState, err error) {
+	ws := w.parent
+	ws.mu.Lock()
+	from := w.State
+	if from.finished() {
+		ws.mu.Unlock()
+		return
+	}
+	if to == Failed && errors.Is(err, context.Canceled) && ws.ctx.Err() != nil {
+		to = Canceled
+	}
+	if to == Failed && err == nil {
+		err = w.stepErr()
+	}
+	to, err = w.hooked(from, to, err)
+	if w.statusTimer != nil && w.statusTimer.Stop() {
+		w.status = w.nextStatus
+	}
+	w.statusTimer = nil
+	w.State = to
+	w.err = err
+	w.finished = ws.now()
+	if w.queueIndex >= 0 {
+		// failed before its turn, so it mustn't use up a start
+		heap.Remove(&ws.queue, w.queueIndex)
+	}
+	close(w.done)
+	w.record(from, w.finished)
+	atomic.AddInt64(&ws.counts[from], -1)
+	atomic.AddInt64(&ws.counts[to], 1)
+	if !w.pausedAt.IsZero() {
+		ws.pausedN--
+	}
+	ws.noteHalt(w.finished)
+	ws.publish(Event{Worker: w, ID: w.id, From: from, To: to, Time: w.finished})
+	ws.settling++
+	ws.mu.Unlock()
+
+	if err != nil {
+		ws.log.printf("#%d %s: %s -> %s: %s", w.id, stripControl(w.Name), from, to, stripControl(err.Error()))
+	} else {
+		ws.log.printf("#%d %s: %s -> %s", w.id, stripControl(w.Name), from, to)
+	}
+	w.flushTee()
+	if to == Failed {
+		ws.checkFailFast()
+	}
+
+	ws.touch()
+
+	// only now may Print return and tear down the log and tee, seeing the
+	// change in its final frame
+	ws.mu.Lock()
+	ws.settling--
+	ws.unfinished--
+	ws.settle()
+	ws.mu.Unlock()
+}
+
+// ID returns the Worker's unique ID within its WorkerSet. IDs are assigned in
+// the order Workers are added, starting from 0.
+func (w *Worker) ID() int64 {
+	return w.id
+}
+
+// Active will return `true` if the Worker.State is Pending or Stopping
 func (w *Worker) Active() bool {
-	return w.State == Pending
+	w.parent.mu.Lock()
+	defer w.parent.mu.Unlock()
+	return !w.State.finished()
+}
+
+// statusAt returns the Worker's WorkerStatus as of now. The caller must hold
+// the parent's lock.
+func (w *Worker) statusAt(now time.Time) WorkerStatus {
+	end := now
+	if w.State.finished() {
+		end = w.finished
+	}
+	ws := WorkerStatus{
+		ID:         w.id,
+		Name:       w.Name,
+		State:      w.State,
+		Status:     w.status,
+		Elapsed:    max(span(w.started, end)-w.pausedFor(end), 0),
+		Priority:   w.priority,
+		Waiting:    w.queueIndex >= 0,
+		Blocked:    w.blocked && !w.State.finished(),
+		Paused:     !w.pausedAt.IsZero() && !w.State.finished(),
+		Finalizing: w.finalizing,
+		Group:      w.group,
+		Icon:       w.icon,
+		Pinned:     w.pin > 0,
+		pin:        w.pin,
+		Info:       w.info,
+		RetryAt:    w.retryAt,
+		Expected:   w.expected,
+
+		NotesDropped: w.notesDropped,
+		Static:       w.static,
+		HideElapsed:  w.hideElapsed,
+		Started:      w.started,
+		Finished:     w.finished,
+	}
+	if w.parent.indexColumn {
+		ws.Index = w.id + 1
+	}
+	ws.Active = ws.Elapsed
+	if w.executed {
+		ws.Active = w.active
+		if !w.attemptStart.IsZero() {
+			ws.Active += span(w.attemptStart, now)
+		}
+	}
+	if w.err != nil {
+		ws.Err = w.err.Error()
+	}
+	if w.requested != w.Name {
+		ws.RequestedName = w.requested
+	}
+	if len(w.notes) > 0 {
+		ws.Notes = append([]string(nil), w.notes...)
+	}
+	if w.counter != nil {
+		ws.Counter = w.counter.status(now)
+	}
+	if len(w.steps) > 0 {
+		ws.Steps = w.steps
+	}
+	ws.fraction, ws.hasFraction = w.fraction, w.hasFraction
+	if len(w.attempts) > 0 {
+		ws.Attempts = append([]Attempt(nil), w.attempts...)
+	}
+	if w.parent.snapshotHistory && len(w.history) > 0 {
+		ws.History = append([]TransitionRecord(nil), w.history...)
+		ws.HistoryDropped = w.historyDropped
+	}
+	return ws
+}
+
+// WorkerStatus is a point-in-time copy of a Worker's state
+type WorkerStatus struct {
+	// ID identifies the Worker within its WorkerSet, unlike Name which
+	// needn't be unique unless WithUniqueNames is given
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+
+	// RequestedName is the name the Worker was added with, if it was
+	// changed to make it unique
+	RequestedName string `json:"requested_name,omitempty"`
+
+	State   WorkerState   `json:"state"`
+	Status  string        `json:"status,omitempty"`
+	Err     string        `json:"error,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+
+	// Active is the time the Worker spent running, which leaves out the
+	// time a Worker started with Go, Command or Do spent waiting to start
+	// or backing off between retries. Elapsed is the wall time since it
+	// was added or Do was called.
+	Active time.Duration `json:"active"`
+
+	// Icon is set with SetIcon
+	Icon string `json:"icon,omitempty"`
+
+	// Static is set for Workers drawn without the spinner with
+	// SetAnimated(false), and HideElapsed for those drawn without their
+	// elapsed time with SetShowElapsed(false)
+	Static      bool `json:"static,omitempty"`
+	HideElapsed bool `json:"hide_elapsed,omitempty"`
+
+	// Index is the Worker's ID plus one, set with WithIndexColumn, and
+	// indexDigits the width of the column it is drawn in
+	Index       int64 `json:"index,omitempty"`
+	indexDigits int
+
+	// Expected is the duration set with SetExpectedDuration
+	Expected time.Duration `json:"expected,omitempty"`
+
+	Priority int    `json:"priority,omitempty"`
+	Group    string `json:"group,omitempty"`
+
+	// Pinned is set for Workers kept at the top of the block with Pin
+	Pinned bool `json:"pinned,omitempty"`
+	pin    int64
+
+	// Info is set for rows added with AddInfo, which aren't counted
+	Info bool `json:"info,omitempty"`
+
+	// Waiting is set while a Worker started with Go or Command waits for
+	// its turn to start, and Blocked while a pending Worker is marked with
+	// SetBlocked
+	Waiting bool `json:"waiting,omitempty"`
+	Blocked bool `json:"blocked,omitempty"`
+
+	// Paused is set while a pending Worker is paused with Pause, and
+	// Elapsed leaves out the time it spent paused
+	Paused bool `json:"paused,omitempty"`
+
+	// Attempts lists the earlier, failed attempts of a Worker retried
+	// with WithRetries
+	Attempts []Attempt `json:"attempts,omitempty"`
+
+	// RetryAt is set while a retried Worker backs off, to when its next
+	// attempt starts
+	RetryAt time.Time `json:"retry_at,omitzero"`
+
+	// Counter holds the counts of a Worker added with AddCounter
+	Counter *CounterStatus `json:"counter,omitempty"`
+
+	// Steps is the Worker's checklist set with Steps, and stepsCollapsed
+	// is set when it is summarized on the Worker's line
+	Steps          []Step `json:"steps,omitempty"`
+	stepsCollapsed bool
+
+	// fraction is the progress set with SetProgress, if hasFraction is set
+	fraction    float64
+	hasFraction bool
+
+	// Finalizing is set while the finalizers registered with Defer run
+	Finalizing bool `json:"finalizing,omitempty"`
+
+	// Notes are the remarks recorded with AddNote, and NotesDropped the
+	// number that didn't fit
+	Notes        []string `json:"notes,omitempty"`
+	NotesDropped int      `json:"notes_dropped,omitempty"`
+
+	// History is the Worker's History, included with WithSnapshotHistory,
+	// and HistoryDropped the number of its oldest records dropped to stay
+	// within the limit set by WithHistoryLimit
+	History        []TransitionRecord `json:"history,omitempty"`
+	HistoryDropped int                `json:"history_dropped,omitempty"`
+
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitzero"`
 }
 
 // A WorkerSet is a collection of Workers
 type WorkerSet struct {
+	// Workers lists the Workers in the order they were added. It should
+	// only be read while no Workers are being added or removed; a Worker
+	// appended to it directly is dropped, see ErrForeignWorker.
 	Workers []*Worker
-	wg      sync.WaitGroup
-	spinner *spin.Spinner
+	spinner *spinner
+
+	// configuration, set by Options
+	out             io.Writer
+	refresh         time.Duration
+	idleRefresh     time.Duration
+	slowRefresh     time.Duration
+	slowFrame       time.Duration
+	stopTimeout     time.Duration
+	statusThrottle  time.Duration
+	failThreshold   int
+	failRatio       float64
+	tolerant        bool
+	tolerantRatio   bool
+	clock           Clock
+	failFast        bool
+	retries         int
+	transitionHook  TransitionHook
+	hideAttempts    bool
+	backoff         func(attempt int) time.Duration
+	groupErrors     int
+	tee             io.Writer
+	teeMu           sync.Mutex
+	teePrefix       func(string) string
+	failureTimeline bool
+	normalizeErr    func(string) string
+	probe           bool
+	advancedEscapes bool
+	name            string
+	labels          map[string]string
+	reportRounding  time.Duration
+	reportRedact    func(string) string
+	historyLimit    int
+	outputLimit     int
+	setOutputLimit  int
+	snapshotHistory bool
+	softFinalizers  bool
+	theme           Theme
+	messages        Messages
+	noColor         bool
+	colorLevel      ColorLevel
+	tty             *bool
+	sizeFunc        func() (width, height int)
+	namePolicy      NamePolicy
+	uniqueNames     bool
+	quiet           bool
+	silent          bool
+	jsonOutput      bool
+	accessible      bool
+	unshared        bool
+	lineFunc        func(Line) string
+	shrinkOrder     []Decoration
+	showElapsed     bool
+	rateBucket      time.Duration
+	rateBuckets     int
+	excludePauses   bool
+	durationFormat  func(time.Duration) string
+	indexColumn     bool
+	header          string
+	showHeader      bool
+	scrollHistory   bool
+	concurrency     int
+	abandonAfter    time.Duration
+	sortOrder       SortOrder
+	keepGoing       bool
+	startInterval   time.Duration
+	startRate       string
+
+	maxRows     int
+	rotate      time.Duration
+	maxErrLines int
+	plainWrap   int
+
+	markerFunc   func(WorkerStatus, int) string
+	markerStyle  MarkerStyle
+	lineGuard    LineGuard
+	compactBelow int
+
+	// finishHooks are the functions given with WithOnFinish
+	finishHooks       []func(context.Context, Summary) error
+	finishHookTimeout time.Duration
+
+	// stats is nil unless WithRenderStats is given
+	stats *renderStats
+
+	// historyDone, rotation, frameCount and watch are accessed only while
+	// rendering
+	historyDone map[int64]bool
+	rotation    rotation
+	frameCount  int
+	watch       frameWatch
+
+	// mu guards Workers and the State of each Worker, along with the
+	// fields below it up to counts
+	mu        sync.Mutex
+	started   bool
+	finished  bool
+	finishers []func()
+
+	// tearing is set once teardown begins, and tornDown once the status
+	// file, recording and run log are complete
+	tearing  bool
+	tornDown bool
+
+	queue   workerQueue
+	nextID  int64
+	nextPin int64
+
+	// names maps each name given to a Worker to the last suffix given to
+	// make it unique, 1 if none has been
+	names nameTable
+
+	keepRemoved   bool
+	removedCounts [numStates]int
+	running       int
+
+	// held keeps queued Workers from starting until a Pipeline reaches
+	// the stage, or with WithStartOnPrint until Print; pipelined is set
+	// for every stage, which a Pipeline draws in place of the stage's own
+	// Print
+	held      bool
+	pipelined bool
+
+	// nextStart is the earliest the next Worker may start with
+	// WithStartRate, and startWaiting is set while a dispatch is scheduled
+	// for then
+	nextStart    time.Time
+	startWaiting bool
+
+	// closed is set by Close. printed is closed once Print returns, and
+	// allDone, set by Print, is closed and cleared once unfinished, the
+	// number of Workers yet to finish, along with Begin's hold, drops to 0.
+	// Workers that have finished but not yet written their log record and
+	// teed output are still counted in unfinished and in settling, as are
+	// queued Workers taken from the queue to be canceled.
+	closed     bool
+	printed    chan struct{}
+	allDone    chan bool
+	unfinished int
+	settling   int
+
+	// misuse is ErrForeignWorker once Workers has been found to hold a
+	// Worker that doesn't belong in it
+	misuse error
+
+	// pausedN is the number of unfinished Workers paused with Pause.
+	// haltedAt is when all of them last were, or zero unless they are now,
+	// and halted the time they all were before then.
+	pausedN  int
+	haltedAt time.Time
+	halted   time.Duration
+
+	// counts, dirty and version are updated atomically on every change so
+	// renderers can cheaply tell whether anything changed. counts is only
+	// updated under mu, along with the States it counts.
+	counts  [numStates]int64
+	dirty   int32
+	version int64
+
+	// outputBytes is the length of the output kept by every Worker,
+	// updated atomically
+	outputBytes int64
+
+	// lastFrame holds the time.Time, by the WorkerSet's clock, the last
+	// frame was laid out, and quietFrames the number of frames in a row
+	// laid out with nothing changed
+	lastFrame   atomic.Value
+	quietFrames int32
+
+	// counters is the number of unfinished Counters, which keep the
+	// display refreshing as they count
+	counters int32
+
+	// disp holds the display drawing the WorkerSet
+	disp atomic.Value
+
+	// terminal is set by WithTerminal
+	terminal Terminal
+
+	// probeOnce guards probing the terminal for WithProbeTerminal, and
+	// sizeLogged is set once a failure to read its size is logged
+	probeOnce  sync.Once
+	probeOK    bool
+	sizeLogged int32
+
+	events     eventQueue
+	log        *runLog
+	debug      *runLog
+	statusFile *statusFile
+	recording  *recording
+
+	// statusInterval is set by WithStatusFileInterval
+	statusInterval time.Duration
+
+	// err holds the first error encountered while applying Options
+	err error
+
+	// fromEnv is set by WithDefaultsFromEnv, applied by applyEnv to the
+	// settings not marked in explicit as made by an Option. envWarnings
+	// notes the environment variables it couldn't understand, to record in
+	// the debug log when Print starts.
+	fromEnv     bool
+	explicit    envField
+	envWarnings []string
+
+	// ctx is canceled along with the context passed to Print
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// stopPrint cancels Print's context once set by Print, and failedFast
+	// is set when WithFailFast has called it
+	stopPrint  context.CancelFunc
+	failedFast bool
+
+	// closing is closed by Close to cancel Print, and bg counts the
+	// background goroutines Close waits for
+	closing chan struct{}
+	bg      sync.WaitGroup
+
+	// draining is set by Drain, and drainTimer is set while it waits for
+	// the drain timeout
+	draining     bool
+	drainTimeout time.Duration
+	drainTimer   *time.Timer
+
+	// unhold lets Print return once the Workers have finished, for a
+	// WorkerSet printing from Begin
+	unhold func()
 }
 
-// New returns an empty WorkerSet
-func New() *WorkerSet {
-	return &WorkerSet{spinner: spin.New()}
+// New returns an empty WorkerSet configured by the Options set by SetDefaults,
+// then the given Options. Without Options the WorkerSet prints to os.Stdout
+// using DefaultTheme, in accessible mode if the ACCESSIBLE environment
+// variable is set.
+func New(opts ...Option) *WorkerSet {
+	ws := &WorkerSet{
+		spinner:           newSpinner(),
+		accessible:        os.Getenv("ACCESSIBLE") != "",
+		shrinkOrder:       defaultShrinkOrder,
+		out:               os.Stdout,
+		terminal:          stdTerminal{},
+		refresh:           100 * time.Millisecond,
+		idleRefresh:       time.Second,
+		slowRefresh:       2 * time.Second,
+		stopTimeout:       5 * time.Second,
+		statusInterval:    2 * time.Second,
+		finishHookTimeout: defaultFinishHookTimeout,
+		theme:             DefaultTheme,
+		messages:          DefaultMessages,
+		colorLevel:        DetectColorLevel(),
+		maxErrLines:       6,
+		groupErrors:       3,
+		plainWrap:         100,
+		historyLimit:      defaultHistoryLimit,
+		outputLimit:       defaultWorkerOutputLimit,
+		setOutputLimit:    defaultSetOutputLimit,
+		compactBelow:      defaultCompactBelow,
+		closing:           make(chan struct{}),
+	}
+	ws.ctx, ws.cancel = context.WithCancel(context.Background())
+	for _, opt := range useDefaults() {
+		opt(ws)
+	}
+	for _, opt := range opts {
+		opt(ws)
+	}
+	ws.applyEnv()
+	return ws
 }
 
-// Add creates and returns a new Worker, and increments the WorkerSet's
-// sync.WaitGroup
+// Add creates and returns a new Worker, which Print waits for until it
+// finishes. The name is trimmed of leading and trailing spaces, and if
+// that leaves it empty it is handled according to WithNamePolicy.
 func (w *WorkerSet) Add(s string) *Worker {
-	w.wg.Add(1)
-	worker := &Worker{Pending, s, w}
-	w.Workers = append(w.Workers, worker)
-	return worker
+	return w.add(s, w.uniqueNames)
 }
 
-// Print initiates the WorkerSet's sync.WaitGroup.Wait() and continuously
-// prints the status of all the Workers in its collection, cancelable via
-// context cancelation.
-//
-// If the stdout is determined to not be a terminal then it will not print
-// until the WaitGroup has finished, and its output will be free of terminal
-// escapes.
-func (w *WorkerSet) Print(ctx context.Context) {
-	done := make(chan bool)
-	go func() {
-		w.wg.Wait()
-		done <- true
-	}()
+// add adds a Worker, making its name unique if unique is set
+func (w *WorkerSet) add(s string, unique bool) *Worker {
+	return w.addAll([]string{s}, unique)[0]
+}
 
-	if terminal.IsTerminal(int(os.Stdout.Fd())) {
-		end := false
-		for !end {
-			select {
-			case <-ctx.Done():
-				w.print(true)
-				return
-			case <-time.After(100 * time.Millisecond):
-				w.print(false)
-			case end = <-done:
-				w.print(true)
-			}
+// addAll adds a Worker for each name under a single lock, making the names
+// unique if unique is set
+func (w *WorkerSet) addAll(names []string, unique bool) []*Worker {
+	now := w.now()
+	workers := make([]*Worker, len(names))
+	for i := range workers {
+		workers[i] = &Worker{State: Pending, parent: w, started: now, queueIndex: -1, done: make(chan struct{})}
+		workers[i].out.set = w
+	}
+	w.mu.Lock()
+	for i, worker := range workers {
+		worker.id = w.nextID
+		worker.requested = w.workerName(names[i], worker.id)
+		worker.Name = w.uniqueName(worker.requested, unique)
+		w.nextID++
+	}
+	w.Workers = append(w.Workers, workers...)
+	w.unfinished += len(workers)
+	closed, draining := w.closed, w.draining
+	atomic.AddInt64(&w.counts[Pending], int64(len(workers)))
+	w.noteHalt(now)
+	w.mu.Unlock()
+	w.touch()
+	for _, worker := range workers {
+		w.log.printf("#%d %s: added", worker.id, stripControl(worker.Name))
+		if closed {
+			worker.FailWith(ErrClosed)
+		} else if draining {
+			worker.transition(Canceled, ErrDraining)
 		}
-	} else {
-		<-done
-		w.print(true)
 	}
+	return workers
 }
 
-func (w *WorkerSet) print(end bool) {
-	failed := "✗"
-	completed := "✔"
-	inProgress := "-"
+// onFinish registers fn to be called when Print returns, once the status
+// file, recording and run log are complete, or calls it immediately if Print
+// already has.
+func (w *WorkerSet) onFinish(fn func()) {
+	w.mu.Lock()
+	if !w.tornDown {
+		w.finishers = append(w.finishers, fn)
+		w.mu.Unlock()
+		return
+	}
+	w.mu.Unlock()
+	fn()
+}
 
-	isTerm := terminal.IsTerminal(int(os.Stdout.Fd()))
+// setErr records err unless an earlier error has already been recorded
+func (w *WorkerSet) setErr(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
 
-	if isTerm {
-		// wipe section
-		fmt.Print(
-			// Ensure the output area is at least N lines long
-			strings.Repeat("\n", len(w.Workers)),
+// isTerminal reports whether output should be animated, either because it was
+// forced by WithTTY or because the output is a terminal.
+func (w *WorkerSet) isTerminal() bool {
+	if w.tty != nil {
+		return *w.tty
+	}
+	f, ok := w.out.(*os.File)
+	if !ok || !w.terminal.IsTerminal(f) {
+		return false
+	}
+	return !w.probe || w.probed()
+}
 
-			// Move cursor up N lines
-			strings.Repeat("\033[A", len(w.Workers)),
+// ByID returns the Worker with the given ID, if it belongs to the WorkerSet
+func (w *WorkerSet) ByID(id int64) (*Worker, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if i := w.indexOf(id); i >= 0 {
+		return w.Workers[i], true
+	}
+	return nil, false
+}
 
-			// Move the cursor down N lines, erasing each line
-			strings.Repeat("\033[B\033[2K", len(w.Workers)),
+// indexOf returns the index in Workers of the Worker with the given ID, or
+// -1. Workers are kept in ID order, so it can search. The caller must hold
+// the lock.
+func (w *WorkerSet) indexOf(id int64) int {
+	members := w.members()
+	i := sort.Search(len(members), func(i int) bool {
+		return members[i].id >= id
+	})
+	if i < len(members) && members[i].id == id {
+		return i
+	}
+	return -1
+}
 
-			// Move cursor up N lines
-			strings.Repeat("\033[A", len(w.Workers)),
-		)
-		failed = "\033[0;31m✗\033[0m"
-		completed = "\033[0;32m✔\033[0m"
-		inProgress = w.spinner.Next()
+// Count returns the number of Workers currently in the given state
+func (w *WorkerSet) Count(state WorkerState) int {
+	return int(w.CountFast(state))
+}
+
+// CountFast returns the number of Workers currently in the given state
+// without taking any lock, for callers polling it on a hot path, such as to
+// decide whether to admit more work. The counts are updated as each Worker
+// changes state, so they always agree with a Snapshot, Summary or frame taken
+// at the same moment, but a reader racing a transition may see the counts of
+// two states before and after it.
+func (w *WorkerSet) CountFast(state WorkerState) int64 {
+	if state < 0 || state >= numStates {
+		return 0
+	}
+	return atomic.LoadInt64(&w.counts[state])
+}
+
+// Snapshot returns a copy of the status of every Worker in the WorkerSet
+func (w *WorkerSet) Snapshot() []WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	members := w.members()
+	snap := make([]WorkerStatus, len(members))
+	for i, v := range members {
+		snap[i] = v.statusAt(now)
 	}
+	return snap
+}
+
+// touch records that a Worker was added or changed, waking the display
+func (w *WorkerSet) touch() {
+	atomic.StoreInt32(&w.dirty, 1)
+	atomic.AddInt64(&w.version, 1)
+	if d, ok := w.disp.Load().(*display); ok && !d.poke() {
+		w.stats.skipped()
+	}
+}
+
+// changed reports whether any Worker has been added or has changed state
+// since the last call, clearing the flag.
+func (w *WorkerSet) changed() bool {
+	return atomic.SwapInt32(&w.dirty, 0) == 1
+}
 
-	for _, v := range w.Workers {
-		p := inProgress
-		if v.State == Completed {
-			p = completed
-		} else if v.State == Failed {
-			p = failed
+// Print waits for every Worker in its collection to finish, continuously
+// printing their status, cancelable via context cancelation.
+//
+// If the output is determined to not be a terminal then it will not print
+// until the Workers have finished, and its output will be free of terminal
+// escapes. WithQuiet prints this way even to a terminal.
+//
+// WorkerSets printing to the same terminal at the same time are stacked
+// vertically and redrawn together; see WithSharedDisplay.
+//
+// In accessible mode a sentence is printed as each Worker finishes, with no
+// animation or terminal escapes regardless of the output.
+//
+// Once canceled, Print cancels the Workers' Context and keeps drawing while
+// Stopping Workers wind down, up to the timeout set by WithStopTimeout. If
+// the context is already canceled when Print is called, every Worker is
+// canceled straight away, including those queued by Go or Command, which
+// never start, and only the final frame is printed.
+//
+// Print returns a *PrintError if it was canceled, stopped by WithFailFast or
+// writing to the output failed, or another error if the WorkerSet was
+// misconfigured or the run log, status file or recording could not be
+// written. It returns ErrClosed after Close.
+func (w *WorkerSet) Print(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+	w.started = true
+	err := w.err
+	held := w.held && !w.pipelined
+	printed := make(chan struct{})
+	w.printed = printed
+	defer close(printed)
+	ctx, stopPrint := context.WithCancel(ctx)
+	defer stopPrint()
+	w.stopPrint = stopPrint
+	w.mu.Unlock()
+	if err == nil {
+		err = w.validate()
+	}
+	if err == nil {
+		err = w.log.open()
+	}
+	if err != nil {
+		return err
+	}
+	if w.theme.Spinner != "" && !w.pipelined {
+		w.spinner.Set(w.theme.Spinner)
+	}
+	if held {
+		w.release()
+	}
+	if len(w.labels) > 0 {
+		w.log.printf("print started: %s", labelString(w.labels))
+	} else {
+		w.log.printf("print started")
+	}
+	for _, s := range w.envWarnings {
+		w.debug.printf("environment: %s, ignored", s)
+	}
+	w.statusFile.start(w)
+	w.checkFailFast()
+	stop, watched := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(stop)
+		<-watched
+	}()
+	go func() {
+		defer close(watched)
+		w.watchAbandoned(stop)
+	}()
+
+	done := make(chan bool)
+	w.mu.Lock()
+	w.allDone = done
+	w.settle()
+	w.mu.Unlock()
+
+	// wait waits for the Workers to finish, or for the context to be
+	// canceled or the WorkerSet closed and the Workers to stop, reporting
+	// whether it was canceled
+	wait := func() bool {
+		select {
+		case <-ctx.Done():
+			w.stop(done)
+			return true
+		case <-w.closing:
+			w.stop(done)
+			return true
+		case <-done:
+			return false
 		}
-		fmt.Printf("  %s %s\n", p, v.Name)
-	}
-	if isTerm {
-		// Hide the cursor
-		fmt.Print("\033[?25l")
-		if end {
-			// Show the cursor
-			fmt.Print("\033[?25h")
+	}
+
+	// A context canceled before Print began cancels every Worker, and only
+	// the final frame is drawn
+	precanceled := ctx.Err() != nil && !w.pipelined
+	if precanceled {
+		w.abort(ctx.Err())
+	}
+
+	canceled := precanceled
+	var werr error
+	writeLine := func(s string) {
+		if _, err := fmt.Fprintln(w.out, s); werr == nil {
+			werr = err
+		}
+	}
+	if w.silent {
+		if !precanceled {
+			canceled = wait()
+		}
+	} else if w.pipelined {
+		canceled = wait()
+	} else if w.jsonOutput {
+		if !precanceled {
+			canceled = wait()
+		}
+		werr = w.printJSON()
+	} else if w.accessible && !w.quiet {
+		canceled = w.printAccessible(ctx, done)
+	} else if !w.quiet && w.isTerminal() {
+		d := w.displayFor()
+		if precanceled {
+			werr = d.flash(w)
 		} else {
-			fmt.Printf("%s", strings.Repeat("\033[A", len(w.Workers)))
+			e := d.attach(w)
+			canceled = wait()
+			werr = d.finish(e)
+		}
+	} else {
+		if !precanceled {
+			canceled = wait()
+		}
+		for _, l := range w.frameLines(false, true) {
+			writeLine(l)
+		}
+		if w.quiet {
+			m := &w.messages
+			if canceled {
+				writeLine(m.Canceled)
+			} else {
+				total := w.Count(Completed) + w.Count(Failed)
+				writeLine(m.pluralf(total, m.AllFinished,
+					total, w.Count(Completed), w.Count(Failed)))
+			}
 		}
 	}
+
+	if canceled {
+		w.log.printf("print canceled: %d pending", w.Count(Pending))
+	}
+	w.log.printf("print finished: %d completed, %d failed", w.Count(Completed), w.Count(Failed))
+
+	w.mu.Lock()
+	w.finished = true
+	w.mu.Unlock()
+	err = w.printError(ctx, canceled, werr)
+	if herr := w.runFinishHooks(); herr != nil {
+		err = errors.Join(err, herr)
+	}
+	if terr := w.teardown(); terr != nil {
+		err = errors.Join(err, terr)
+	}
+	return err
 }
@@ -0,0 +1,67 @@
+package multistatus
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithName names the WorkerSet, to tell it apart from others in the same
+// program. The name is used as the header's title unless WithHeader gives
+// one, and is included in the JSON snapshot, the status file and each record
+// of the run log.
+func WithName(name string) Option {
+	return func(w *WorkerSet) {
+		w.name = name
+		w.log.setPrefix(name)
+		w.debug.setPrefix(name)
+	}
+}
+
+// WithLabel attaches a label to the WorkerSet, such as "env" set to
+// "staging". Labels are included in the JSON snapshot, the status file and
+// the run log, alongside the name set by WithName.
+func WithLabel(key, value string) Option {
+	return func(w *WorkerSet) {
+		labels := make(map[string]string, len(w.labels)+1)
+		for k, v := range w.labels {
+			labels[k] = v
+		}
+		labels[key] = value
+		w.labels = labels
+	}
+}
+
+// Name returns the name set by WithName
+func (w *WorkerSet) Name() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.name
+}
+
+// Labels returns a copy of the labels set by WithLabel
+func (w *WorkerSet) Labels() map[string]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.labels == nil {
+		return nil
+	}
+	labels := make(map[string]string, len(w.labels))
+	for k, v := range w.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// labelString formats labels as "key=value" pairs sorted by key
+func labelString(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = stripControl(k) + "=" + stripControl(labels[k])
+	}
+	return strings.Join(pairs, " ")
+}
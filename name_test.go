@@ -0,0 +1,98 @@
+package multistatus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// namedSet returns a WorkerSet named "db-migrations" labeled env=staging
+// and region=eu
+func namedSet(opts ...Option) *WorkerSet {
+	opts = append([]Option{WithName("db-migrations"), WithLabel("env", "staging"), WithLabel("region", "eu")}, opts...)
+	return New(opts...)
+}
+
+func TestNameAccessors(t *testing.T) {
+	ws := namedSet()
+	if got := ws.Name(); got != "db-migrations" {
+		t.Errorf("Name is %q", got)
+	}
+	labels := ws.Labels()
+	if len(labels) != 2 || labels["env"] != "staging" || labels["region"] != "eu" {
+		t.Errorf("Labels are %v", labels)
+	}
+	labels["env"] = "prod"
+	if got := ws.Labels()["env"]; got != "staging" {
+		t.Errorf("changing the copy changed the label to %q", got)
+	}
+	if New().Labels() != nil {
+		t.Errorf("Labels without any aren't nil")
+	}
+}
+
+func TestNameImmutable(t *testing.T) {
+	ws := namedSet(WithSilent(true))
+	ws.Add("a").Done()
+	ws.Print(context.Background())
+	if err := ws.Configure(WithLabel("env", "prod"), WithName("other")); !errors.Is(err, ErrStarted) {
+		t.Errorf("Configure after Print returned %v", err)
+	}
+	if ws.Name() != "db-migrations" || ws.Labels()["env"] != "staging" {
+		t.Errorf("name and labels changed after Print: %q %v", ws.Name(), ws.Labels())
+	}
+}
+
+func TestNamePropagated(t *testing.T) {
+	dir := t.TempDir()
+	statusPath := filepath.Join(dir, "status.json")
+	logPath := filepath.Join(dir, "run.log")
+	var out syncBuf
+	ws := namedSet(WithOutput(&out), WithTTY(false), WithHeader(""), WithStatusFile(statusPath), WithLogFile(logPath))
+	ws.Add("migrate").Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the header's title
+	if first := strings.Split(out.String(), "\n")[0]; !strings.HasPrefix(first, "db-migrations") {
+		t.Errorf("header is %q", first)
+	}
+
+	// the JSON snapshot and status file
+	j, err := json.Marshal(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := os.ReadFile(statusPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for what, b := range map[string][]byte{"JSON": j, "status file": status} {
+		var v struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		}
+		if err := json.Unmarshal(b, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.Name != "db-migrations" || v.Labels["env"] != "staging" || v.Labels["region"] != "eu" {
+			t.Errorf("%s has name %q and labels %v", what, v.Name, v.Labels)
+		}
+	}
+
+	// every record of the run log
+	records := logRecords(t, logPath)
+	for _, r := range records {
+		if !strings.HasPrefix(r, "[db-migrations] ") {
+			t.Errorf("log record %q lacks the name", r)
+		}
+	}
+	if !strings.Contains(strings.Join(records, "\n"), "print started: env=staging region=eu") {
+		t.Errorf("log lacks the labels:\n%s", strings.Join(records, "\n"))
+	}
+}
@@ -0,0 +1,55 @@
+package multistatus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxNotes and maxNoteBytes bound the notes kept per Worker
+const (
+	maxNotes     = 20
+	maxNoteBytes = 4096
+)
+
+// AddNote records a short remark about the Worker, such as "cache hit",
+// that isn't shown while it runs but is listed under it in the final report
+// and included in snapshots. Notes are kept across retries, those after the
+// first attempt starting with the attempt number. Each Worker keeps at most
+// 20 notes totalling 4KiB; any more are counted but dropped.
+func (w *Worker) AddNote(s string) {
+	ws := w.parent
+	ws.mu.Lock()
+	if n := len(w.attempts); n > 0 {
+		s = fmt.Sprintf(ws.messages.NoteAttempt, n+1) + s
+	}
+	if len(w.notes) >= maxNotes || w.noteBytes+len(s) > maxNoteBytes {
+		w.notesDropped++
+	} else {
+		w.notes = append(w.notes, s)
+		w.noteBytes += len(s)
+	}
+	ws.mu.Unlock()
+	ws.touch()
+	ws.log.printf("#%d %s: note %q", w.id, stripControl(w.Name), s)
+}
+
+// noteLines formats v's notes to follow its line in the final report,
+// dimmed and indented to start under the Worker's name
+func (w *WorkerSet) noteLines(v WorkerStatus, marker string, width int, level ColorLevel) []string {
+	if len(v.Notes) == 0 && v.NotesDropped == 0 {
+		return nil
+	}
+	indent := strings.Repeat(" ", 3+stringWidth(marker))
+	lines := make([]string, 0, len(v.Notes)+1)
+	for _, n := range v.Notes {
+		lines = append(lines, stripControl(n))
+	}
+	if v.NotesDropped > 0 {
+		m := &w.messages
+		lines = append(lines, m.pluralf(v.NotesDropped, m.MoreNotes, v.NotesDropped))
+	}
+	for i, l := range lines {
+		lines[i] = colorize(truncate(indent+l, widthOrMax(width)), w.theme.DimColor, level)
+	}
+	return lines
+}
@@ -0,0 +1,111 @@
+package multistatus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotesFinalOnly(t *testing.T) {
+	ws := New(WithColorLevel(Color16))
+	w := ws.Add("fetch")
+	w.AddNote("cache hit")
+	w.AddNote("skipped 3 files")
+	if _, lines := ws.frame(true, false); len(lines) != 1 {
+		t.Errorf("notes in the live display: %q", lines)
+	}
+	w.Done()
+	_, lines := ws.frame(true, true)
+	want := []string{"  \033[32m✔\033[0m fetch", "\033[2m    cache hit\033[0m", "\033[2m    skipped 3 files\033[0m"}
+	if fmt.Sprint(lines) != fmt.Sprint(want) {
+		t.Errorf("got %q\nwant %q", lines, want)
+	}
+}
+
+func TestNotesCaps(t *testing.T) {
+	ws := New()
+	w := ws.Add("many")
+	for i := 0; i < maxNotes+5; i++ {
+		w.AddNote(fmt.Sprint("note ", i))
+	}
+	big := ws.Add("big")
+	big.AddNote(strings.Repeat("x", maxNoteBytes-10))
+	big.AddNote("this one is too long")
+	big.AddNote("short")
+
+	snap := ws.Snapshot()
+	if v := snap[0]; len(v.Notes) != maxNotes || v.NotesDropped != 5 {
+		t.Errorf("kept %d notes and dropped %d", len(v.Notes), v.NotesDropped)
+	}
+	if v := snap[1]; len(v.Notes) != 2 || v.Notes[1] != "short" || v.NotesDropped != 1 {
+		t.Errorf("kept %q and dropped %d", v.Notes, v.NotesDropped)
+	}
+	lines := ws.frameLines(false, true)
+	if got := strings.TrimSpace(lines[maxNotes+1]); got != "… 5 more notes" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNotesConcurrent(t *testing.T) {
+	ws := New(WithSilent(true))
+	w := ws.Add("busy")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				w.AddNote("n")
+			}
+		}()
+	}
+	wg.Wait()
+	if v := ws.Snapshot()[0]; len(v.Notes)+v.NotesDropped != 80 {
+		t.Errorf("%d kept and %d dropped of 80", len(v.Notes), v.NotesDropped)
+	}
+}
+
+func TestNotesExported(t *testing.T) {
+	ws := New()
+	w := ws.Add("fetch")
+	w.AddNote("cache hit")
+	w.Done()
+
+	b, err := json.Marshal(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"notes":["cache hit"]`) {
+		t.Errorf("notes missing from %s", b)
+	}
+	var buf strings.Builder
+	if err := ws.WriteStableReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "fetch: completed\n    cache hit\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNotesRetried(t *testing.T) {
+	ws := New(WithSilent(true), WithRetries(2), WithBackoff(func(int) time.Duration { return 0 }))
+	n := 0
+	ws.Go("flaky", func(ctx context.Context) error {
+		n++
+		w := ws.Workers[0]
+		w.AddNote(fmt.Sprint("try ", n))
+		if n < 3 {
+			return errors.New("broke")
+		}
+		return nil
+	})
+	ws.Print(context.Background())
+	if got, want := ws.Snapshot()[0].Notes, []string{"try 1", "attempt 2: try 2", "attempt 3: try 3"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultFinishHookTimeout is how long each WithOnFinish function may run
+// unless WithOnFinishTimeout is given
+const defaultFinishHookTimeout = 30 * time.Second
+
+// WithOnFinish has Print call fn once the final frame has been printed and
+// before it returns, such as to upload a report or flush metrics as part of
+// the run. Functions run one after another in the order they were given,
+// with the WorkerSet's Summary and a fresh context that expires after the
+// timeout set by WithOnFinishTimeout, so they can still do their work after
+// Print was canceled. A function still running at the timeout is abandoned.
+// Errors from the functions, including timeouts, are joined to the error
+// Print returns; Summary still decides whether the run succeeded.
+func WithOnFinish(fn func(ctx context.Context, s Summary) error) Option {
+	return func(w *WorkerSet) {
+		w.finishHooks = append(w.finishHooks, fn)
+	}
+}
+
+// WithOnFinishTimeout sets how long each function given with WithOnFinish
+// may run, 30s by default
+func WithOnFinishTimeout(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.finishHookTimeout = d
+	}
+}
+
+// runFinishHooks calls the WithOnFinish functions in turn, returning their
+// errors joined
+func (w *WorkerSet) runFinishHooks() error {
+	if len(w.finishHooks) == 0 {
+		return nil
+	}
+	s := w.Summary()
+	var errs []error
+	for i, fn := range w.finishHooks {
+		if err := w.runFinishHook(fn, s); err != nil {
+			w.log.printf("finish hook %d: %v", i+1, err)
+			errs = append(errs, fmt.Errorf("multistatus: finish hook %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runFinishHook calls fn, giving up on it once its context expires
+func (w *WorkerSet) runFinishHook(fn func(context.Context, Summary) error, s Summary) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.finishHookTimeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- fn(ctx, s)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,115 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnFinishOrder(t *testing.T) {
+	out := &syncBuf{}
+	var calls []string
+	hook := func(name string) Option {
+		return WithOnFinish(func(ctx context.Context, s Summary) error {
+			if !strings.Contains(out.String(), "[ OK ] task") {
+				t.Errorf("%s ran before the final frame: %q", name, out.String())
+			}
+			if s.Completed != 1 || !s.OK {
+				t.Errorf("%s got %+v", name, s)
+			}
+			calls = append(calls, name)
+			return nil
+		})
+	}
+	ws := New(WithOutput(out), hook("first"), hook("second"), hook("third"))
+	ws.Add("task").Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got calls %v, want %v", calls, want)
+	}
+}
+
+func TestOnFinishErrors(t *testing.T) {
+	upload := errors.New("upload failed")
+	flush := errors.New("flush failed")
+	var ran bool
+	ws := New(WithSilent(true),
+		WithOnFinish(func(context.Context, Summary) error { return upload }),
+		WithOnFinish(func(context.Context, Summary) error { ran = true; return nil }),
+		WithOnFinish(func(context.Context, Summary) error { return flush }),
+		WithOnFinish(func(context.Context, Summary) error { panic("boom") }))
+	ws.Add("task").Done()
+	err := ws.Print(context.Background())
+	if !errors.Is(err, upload) || !errors.Is(err, flush) || !strings.Contains(err.Error(), "finish hook 4: panic: boom") {
+		t.Errorf("got %v", err)
+	}
+	if !ran {
+		t.Error("a hook after a failing one didn't run")
+	}
+	if code := ws.ExitCode(); code != 0 {
+		t.Errorf("got exit code %d with every worker done", code)
+	}
+
+	// a failed worker still decides the exit code without a Print error
+	ws = New(WithSilent(true), WithOnFinish(func(context.Context, Summary) error { return nil }))
+	ws.Add("task").Fail()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Errorf("got %v", err)
+	}
+	if code := ws.ExitCode(); code != 1 {
+		t.Errorf("got exit code %d with a failed worker", code)
+	}
+}
+
+func TestOnFinishTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	var next bool
+	ws := New(WithSilent(true), WithOnFinishTimeout(20*time.Millisecond),
+		WithOnFinish(func(ctx context.Context, s Summary) error {
+			<-release
+			return nil
+		}),
+		WithOnFinish(func(ctx context.Context, s Summary) error {
+			next = true
+			return nil
+		}))
+	ws.Add("task").Done()
+	start := time.Now()
+	err := ws.Print(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "finish hook 1") {
+		t.Errorf("got %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Print took %v with a hung hook", d)
+	}
+	if !next {
+		t.Error("the hook after the abandoned one didn't run")
+	}
+}
+
+func TestOnFinishCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var hookErr error
+	var deadline bool
+	ws := New(WithSilent(true), WithOnFinishTimeout(time.Minute),
+		WithOnFinish(func(ctx context.Context, s Summary) error {
+			hookErr = ctx.Err()
+			_, deadline = ctx.Deadline()
+			return nil
+		}))
+	ws.Add("task")
+	err := ws.Print(ctx)
+	if !errors.Is(err, ErrCanceled) {
+		t.Errorf("got %v, want %v", err, ErrCanceled)
+	}
+	if hookErr != nil || !deadline {
+		t.Errorf("hook got a context with error %v, deadline %v", hookErr, deadline)
+	}
+}
@@ -0,0 +1,189 @@
+package multistatus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ErrStarted is returned when configuring a WorkerSet after Print has begun
+var ErrStarted = errors.New("multistatus: WorkerSet is already printing")
+
+// An Option configures a WorkerSet when passed to New or Configure
+type Option func(*WorkerSet)
+
+// Configure applies opts to the WorkerSet. Options may be changed freely until
+// Print is called; afterwards the configuration is fixed and Configure returns
+// ErrStarted without applying anything.
+func (w *WorkerSet) Configure(opts ...Option) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrClosed
+	}
+	if w.started {
+		return ErrStarted
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.applyEnv()
+	return nil
+}
+
+// WithAccessible enables a screen reader friendly mode: no spinner, no cursor
+// escapes, and a complete sentence is printed each time a Worker finishes.
+//
+// Accessible mode is also enabled when the ACCESSIBLE environment variable is
+// set to a non-empty value.
+func WithAccessible(b bool) Option {
+	return func(w *WorkerSet) {
+		w.accessible = b
+		w.explicit |= envAccessible
+	}
+}
+
+// WithOutput sets where the WorkerSet prints its status, os.Stdout by default.
+// Animation is only used when out is an *os.File connected to a terminal,
+// unless overridden with WithTTY.
+func WithOutput(out io.Writer) Option {
+	return func(w *WorkerSet) {
+		w.out = out
+	}
+}
+
+// WithRefreshInterval sets how often the status is redrawn on a terminal
+// while Workers are changing, 100ms by default. See also
+// WithIdleRefreshInterval.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.refresh = d
+		w.explicit |= envRefresh
+	}
+}
+
+// WithTheme sets the markers and colors used to draw each Worker
+func WithTheme(t Theme) Option {
+	return func(w *WorkerSet) {
+		w.theme = t
+	}
+}
+
+// WithColor enables or disables colored output on a terminal. Colors are
+// enabled by default, limited to the terminal's ColorLevel.
+func WithColor(b bool) Option {
+	return func(w *WorkerSet) {
+		w.noColor = !b
+		w.explicit |= envColor
+	}
+}
+
+// WithTTY overrides terminal detection, forcing animated output when b is
+// true and plain output when false.
+func WithTTY(b bool) Option {
+	return func(w *WorkerSet) {
+		w.tty = &b
+		w.explicit |= envFormat
+	}
+}
+
+// WithQuiet prints nothing until every Worker has finished or Print is
+// canceled, then prints the final block as it would be without a terminal,
+// followed by a one-line summary. Nothing is animated and no terminal escapes
+// are written, even to a terminal or in accessible mode.
+func WithQuiet(b bool) Option {
+	return func(w *WorkerSet) {
+		w.quiet = b
+	}
+}
+
+// WithSilent has Print draw nothing at all, not even the final block, for
+// programs using the WorkerSet only to run Workers and report on them through
+// Subscribe, Summary and the like. Nothing is written to the output, which
+// may be nil, and it is never checked for being a terminal. Options writing
+// elsewhere, such as WithLogWriter, WithStatusFile and WithCapturedOutputTee,
+// still do.
+func WithSilent(b bool) Option {
+	return func(w *WorkerSet) {
+		w.silent = b
+	}
+}
+
+// WithDefaultsFromEnv configures the WorkerSet from the environment variables
+// documented by this package, so that scripts wrapping a program can change
+// its output without flags of their own:
+//
+//	ACCESSIBLE               enables accessible mode when non-empty
+//	NO_COLOR                 disables colors when non-empty
+//	MULTISTATUS_FORMAT       "tty" to animate, as WithTTY(true) does,
+//	                         "plain" to print without animation, or "json"
+//	                         to print the JSON snapshot, as WithJSONOutput
+//	                         does
+//	MULTISTATUS_NO_ANIMATION prints without animation when non-empty
+//	MULTISTATUS_REFRESH_MS   sets the refresh interval in milliseconds
+//
+// A value that isn't understood is ignored, leaving the output detected as
+// usual, and noted in the debug log given by WithDebugLog when Print starts.
+// The variables are read once every other Option has been applied, whether
+// given before or after WithDefaultsFromEnv, and make only the settings no
+// Option made: WithAccessible, WithColor and WithRefreshInterval each take
+// precedence over their variable, and WithTTY and WithJSONOutput over
+// MULTISTATUS_FORMAT and MULTISTATUS_NO_ANIMATION.
+func WithDefaultsFromEnv() Option {
+	return func(w *WorkerSet) {
+		w.fromEnv = true
+	}
+}
+
+// envField marks a setting WithDefaultsFromEnv may make, which it leaves
+// alone once an Option has made it
+type envField uint8
+
+const (
+	envAccessible envField = 1 << iota
+	envColor
+	envFormat
+	envRefresh
+)
+
+// applyEnv makes the settings of WithDefaultsFromEnv, if it was given, that
+// no Option has made
+func (w *WorkerSet) applyEnv() {
+	if !w.fromEnv {
+		return
+	}
+	w.envWarnings = nil
+	unset := func(f envField) bool { return w.explicit&f == 0 }
+	tty := func(b bool) { w.tty = &b }
+	if os.Getenv("ACCESSIBLE") != "" && unset(envAccessible) {
+		w.accessible = true
+	}
+	if os.Getenv("NO_COLOR") != "" && unset(envColor) {
+		w.noColor = true
+	}
+	switch f := os.Getenv("MULTISTATUS_FORMAT"); {
+	case f == "" || !unset(envFormat):
+	case f == "tty":
+		tty(true)
+	case f == "plain":
+		tty(false)
+	case f == "json":
+		w.jsonOutput = true
+	default:
+		w.envWarnings = append(w.envWarnings, fmt.Sprintf("MULTISTATUS_FORMAT: unsupported format %q", f))
+	}
+	if os.Getenv("MULTISTATUS_NO_ANIMATION") != "" && unset(envFormat) {
+		tty(false)
+	}
+	if s := os.Getenv("MULTISTATUS_REFRESH_MS"); s != "" && unset(envRefresh) {
+		ms, err := strconv.Atoi(s)
+		if err != nil || ms <= 0 {
+			w.envWarnings = append(w.envWarnings, fmt.Sprintf("MULTISTATUS_REFRESH_MS: invalid interval %q", s))
+		} else {
+			w.refresh = time.Duration(ms) * time.Millisecond
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package multistatus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// compareGolden compares got with the golden file name in testdata,
+// rewriting it instead with -update
+func compareGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n got: %q\nwant: %q", name, got, want)
+	}
+}
+
+// clearEnv unsets the environment variables New and WithDefaultsFromEnv read
+func clearEnv(t *testing.T) {
+	for _, k := range []string{"ACCESSIBLE", "NO_COLOR", "COLORTERM", "TERM", "MULTISTATUS_FORMAT",
+		"MULTISTATUS_NO_ANIMATION", "MULTISTATUS_REFRESH_MS"} {
+		t.Setenv(k, "")
+	}
+}
+
+// goldenWorkers adds a finished Worker of each kind to ws
+func goldenWorkers(ws *WorkerSet) {
+	ws.Add("fetch").Done()
+	build := ws.Add("build")
+	build.SetStatus("compiling")
+	build.Done()
+	ws.Add("test").FailWith(errors.New("2 tests failed\nTestParse: unexpected EOF"))
+	ws.Add("deploy").transition(Canceled, context.Canceled)
+}
+
+func TestNewGolden(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("TERM", "xterm-256color")
+
+	ws := New()
+	var buf bytes.Buffer
+	ws.out = &buf
+	goldenWorkers(ws)
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "new_plain.golden", buf.Bytes())
+
+	ws = New()
+	goldenWorkers(ws)
+	shot, err := ws.Screenshot(80, ScreenshotANSI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "new_ansi.golden", []byte(shot))
+}
+
+func TestConfigure(t *testing.T) {
+	ws := New(WithSilent(true))
+	if err := ws.Configure(WithMaxRows(5)); err != nil || ws.maxRows != 5 {
+		t.Fatalf("Configure before Print returned %v with maxRows %d", err, ws.maxRows)
+	}
+	ws.Add("task").Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.Configure(WithMaxRows(9)); !errors.Is(err, ErrStarted) || ws.maxRows != 5 {
+		t.Errorf("Configure after Print returned %v with maxRows %d, want ErrStarted and 5", err, ws.maxRows)
+	}
+	ws.Close()
+	if err := ws.Configure(WithMaxRows(9)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Configure after Close returned %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,68 @@
+package multistatus
+
+import "time"
+
+// Pause marks the pending Worker as paused, such as when a user has asked
+// for its work to stop for now. Its elapsed time stops counting, it is drawn
+// with the Theme's Paused marker and the header counts it, and WithAbandonAfter
+// leaves it alone. Print still waits for it. Pause returns ErrFinished if the
+// Worker has already finished, and does nothing if it's already paused.
+func (w *Worker) Pause() error {
+	ws := w.parent
+	ws.mu.Lock()
+	if w.State.finished() {
+		ws.mu.Unlock()
+		return ErrFinished
+	}
+	if w.pausedAt.IsZero() {
+		w.pausedAt = ws.now()
+		ws.pausedN++
+		ws.noteHalt(w.pausedAt)
+	}
+	ws.mu.Unlock()
+	ws.touch()
+	return nil
+}
+
+// Resume undoes Pause, and its elapsed time counts again from where it
+// stopped. It returns ErrFinished if the Worker has already finished, and
+// does nothing if it isn't paused.
+func (w *Worker) Resume() error {
+	ws := w.parent
+	ws.mu.Lock()
+	if w.State.finished() {
+		ws.mu.Unlock()
+		return ErrFinished
+	}
+	if !w.pausedAt.IsZero() {
+		now := ws.now()
+		w.paused += span(w.pausedAt, now)
+		w.pausedAt = time.Time{}
+		ws.pausedN--
+		ws.noteHalt(now)
+	}
+	ws.mu.Unlock()
+	ws.touch()
+	return nil
+}
+
+// pausedFor returns how long the Worker had been paused as of end. The
+// caller must hold the parent's lock.
+func (w *Worker) pausedFor(end time.Time) time.Duration {
+	d := w.paused
+	if !w.pausedAt.IsZero() {
+		d += span(w.pausedAt, end)
+	}
+	return d
+}
+
+// pausedCount returns the number of paused Workers in snap
+func pausedCount(snap []WorkerStatus) int {
+	n := 0
+	for _, v := range snap {
+		if v.Paused {
+			n++
+		}
+	}
+	return n
+}
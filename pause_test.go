@@ -0,0 +1,105 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPauseTimer(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithColor(false), WithElapsed(true))
+	worker := ws.Add("task")
+	elapsed := func() time.Duration { return ws.Snapshot()[0].Elapsed }
+
+	clock.Advance(10 * time.Second)
+	worker.Pause()
+	clock.Advance(20 * time.Second)
+	if got := elapsed(); got != 10*time.Second {
+		t.Errorf("paused: got %v, want 10s", got)
+	}
+	if got, want := ws.frameLines(true, false)[0], "  ‖ task (10s)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// later cycles add up, and pausing twice is the same as once
+	worker.Resume()
+	clock.Advance(5 * time.Second)
+	worker.Pause()
+	worker.Pause()
+	clock.Advance(100 * time.Second)
+	worker.Resume()
+	worker.Resume()
+	clock.Advance(5 * time.Second)
+	if got := elapsed(); got != 20*time.Second {
+		t.Errorf("after two cycles: got %v, want 20s", got)
+	}
+	if v := ws.Snapshot()[0]; v.Paused || v.State != Pending {
+		t.Errorf("resumed worker is %v, paused %v", v.State, v.Paused)
+	}
+
+	worker.Pause()
+	clock.Advance(time.Minute)
+	worker.Done()
+	if got := elapsed(); got != 20*time.Second {
+		t.Errorf("finished while paused: got %v, want 20s", got)
+	}
+	if ws.Snapshot()[0].Paused {
+		t.Error("finished worker is still paused")
+	}
+}
+
+func TestPauseHeader(t *testing.T) {
+	ws := New(WithSilent(true), WithClock(newFakeClock()), WithColor(false), WithHeader("run"))
+	a, b := ws.Add("a"), ws.Add("b")
+	ws.Add("c")
+	a.Pause()
+	b.Pause()
+	if _, lines := ws.frame(false, false); !strings.HasSuffix(lines[0], " · 2 paused") {
+		t.Errorf("got header %q", lines[0])
+	}
+	a.Resume()
+	b.Done()
+	if _, lines := ws.frame(false, false); strings.Contains(lines[0], "paused") {
+		t.Errorf("got header %q with none paused", lines[0])
+	}
+}
+
+func TestPauseFinished(t *testing.T) {
+	ws := New(WithSilent(true))
+	worker := ws.Add("task")
+	worker.Pause()
+	worker.Fail()
+	if err := worker.Pause(); !errors.Is(err, ErrFinished) {
+		t.Errorf("Pause returned %v, want %v", err, ErrFinished)
+	}
+	if err := worker.Resume(); !errors.Is(err, ErrFinished) {
+		t.Errorf("Resume returned %v, want %v", err, ErrFinished)
+	}
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if sum := ws.Summary(); sum.Failed != 1 || sum.Pending != 0 {
+		t.Errorf("got %+v", sum)
+	}
+}
+
+func TestPauseWaited(t *testing.T) {
+	ws := New(WithSilent(true))
+	worker := ws.Add("task")
+	worker.Pause()
+	printed := make(chan error)
+	go func() { printed <- ws.Print(context.Background()) }()
+	select {
+	case <-printed:
+		t.Fatal("Print returned while a worker was paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	worker.Resume()
+	worker.Done()
+	if err := <-printed; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,25 @@
+package multistatus
+
+// Pin keeps the Worker at the top of the block whatever its state and the
+// sort order, and on screen when rows are short or finished Workers move to
+// the scrolling history. Pinned Workers are shown in the order they were
+// pinned.
+func (w *Worker) Pin() {
+	ws := w.parent
+	ws.mu.Lock()
+	if w.pin == 0 {
+		ws.nextPin++
+		w.pin = ws.nextPin
+	}
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// Unpin returns a pinned Worker to its usual place
+func (w *Worker) Unpin() {
+	ws := w.parent
+	ws.mu.Lock()
+	w.pin = 0
+	ws.mu.Unlock()
+	ws.touch()
+}
@@ -0,0 +1,64 @@
+package multistatus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPin(t *testing.T) {
+	ws := New(WithSort(SortByPriority))
+	a := ws.Add("a")
+	b := ws.Add("b")
+	c := ws.Add("c")
+	c.SetPriority(5)
+	b.Pin()
+	a.Pin()
+	a.Pin()
+	a.Done()
+	if got, want := strings.Join(ws.frameLines(false, false), "|"), "  [ .. ] b|  [ OK ] a|  [ .. ] c"; got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	b.Unpin()
+	if got, want := strings.Join(ws.frameLines(false, false), "|"), "  [ OK ] a|  [ .. ] c|  [ .. ] b"; got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	var names []string
+	for _, v := range ws.Snapshot() {
+		names = append(names, v.Name)
+	}
+	if got := strings.Join(names, " "); got != "a b c" {
+		t.Errorf("Snapshot in order %s, want the order added", got)
+	}
+}
+
+func TestPinScrollingHistory(t *testing.T) {
+	ws := New(WithScrollingHistory(true), WithColor(false))
+	kept := ws.Add("kept")
+	kept.Pin()
+	kept.Done()
+	ws.Add("gone").Done()
+	ws.Add("running")
+	history, lines := ws.frame(true, false)
+	if len(history) != 1 || !strings.Contains(history[0], "gone") {
+		t.Errorf("history is %q, want gone", history)
+	}
+	if got := rowNames(lines); got != "kept | running" {
+		t.Errorf("lines are %s, want kept | running", got)
+	}
+}
+
+func TestPinMaxRows(t *testing.T) {
+	ws := New(WithMaxRows(4), WithRotateVisible(time.Hour))
+	for i := 0; i < 10; i++ {
+		w := ws.Add(fmt.Sprint("w", i))
+		if i >= 8 {
+			w.Pin()
+		}
+	}
+	_, lines := ws.frame(true, false)
+	if got, want := rowNames(lines), "w8 | w9 | w0 | showing 1–1 of 8 running"; got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
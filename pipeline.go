@@ -0,0 +1,384 @@
+package multistatus
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSkipped is the error of Workers in a Pipeline stage that didn't run
+// because an earlier stage failed
+var ErrSkipped = errors.New("multistatus: skipped after an earlier stage failed")
+
+// A Pipeline runs WorkerSets one after another as the stages of a single
+// display. Finished stages collapse to a one-line summary, the running stage
+// is drawn in full below its name, and stages yet to run are listed dimly.
+//
+// Workers started with Go or Command on a stage wait until the Pipeline
+// reaches it. Unless WithContinueOnFailure is given, once a stage has a
+// failed Worker the stages after it are skipped: their Contexts are canceled
+// and their Workers fail with ErrSkipped.
+type Pipeline struct {
+	// ws holds the display configuration; it has no Workers of its own
+	ws *WorkerSet
+
+	mu      sync.Mutex
+	stages  []*stage
+	current int
+	display *display
+}
+
+type stage struct {
+	name    string
+	ws      *WorkerSet
+	skipped bool
+}
+
+// StageSummary reports how a Pipeline stage ended
+type StageSummary struct {
+	Name      string
+	Completed int
+	Failed    int
+	Canceled  int
+	Pending   int
+	Skipped   bool
+}
+
+// PipelineSummary reports how each stage of a Pipeline ended, along with
+// totals across every stage
+type PipelineSummary struct {
+	Stages    []StageSummary
+	Completed int
+	Failed    int
+	Canceled  int
+	Pending   int
+}
+
+// NewPipeline returns a Pipeline with no stages. The Options configure the
+// display as a whole, such as its output, Theme and Messages; each stage's
+// own Options still control how its Workers are run and drawn.
+func NewPipeline(opts ...Option) *Pipeline {
+	return &Pipeline{ws: New(opts...)}
+}
+
+// WithContinueOnFailure makes a Pipeline run every stage even when an
+// earlier stage has failed Workers
+func WithContinueOnFailure(b bool) Option {
+	return func(w *WorkerSet) {
+		w.keepGoing = b
+	}
+}
+
+// AddStage appends ws to the Pipeline as a stage with the given name. The
+// stage is printed by the Pipeline, so its own Print mustn't be called.
+func (p *Pipeline) AddStage(name string, ws *WorkerSet) {
+	ws.mu.Lock()
+	ws.held = true
+	ws.pipelined = true
+	ws.out = p.ws.out
+	if ws.sizeFunc == nil {
+		ws.sizeFunc = p.ws.Size
+	}
+	ws.mu.Unlock()
+	p.mu.Lock()
+	p.stages = append(p.stages, &stage{name: name, ws: ws})
+	if p.display != nil {
+		ws.setDisplay(p.display)
+	}
+	p.mu.Unlock()
+}
+
+// advance makes the i'th stage the running one and returns it, or nil once
+// there are no more
+func (p *Pipeline) advance(i int) *stage {
+	defer p.ws.touch()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = i
+	if i >= len(p.stages) {
+		return nil
+	}
+	s := p.stages[i]
+	if s.ws.theme.Spinner != "" {
+		s.ws.spinner.Set(s.ws.theme.Spinner)
+	}
+	return s
+}
+
+// Print runs each stage in turn, displaying the progress of the Pipeline as
+// a whole, until the last stage has finished or the context is canceled.
+// Stages not yet started when the context is canceled are skipped, with
+// their Workers failing with the context's error. Print returns a summary of
+// every stage, and the first error returned by a stage's Print.
+func (p *Pipeline) Print(ctx context.Context) (PipelineSummary, error) {
+	pw := p.ws
+	err := pw.err
+	if err == nil {
+		err = pw.validate()
+	}
+	if err != nil {
+		return PipelineSummary{}, err
+	}
+	if pw.theme.Spinner != "" {
+		pw.spinner.Set(pw.theme.Spinner)
+	}
+
+	// with WithSilent nothing is written and the output is never checked
+	var d *display
+	var e *displayEntry
+	if !pw.silent && !pw.accessible && pw.isTerminal() {
+		d = pw.displayFor()
+		e = d.attach(p)
+	}
+
+	failed := false
+	for i := 0; ; i++ {
+		s := p.advance(i)
+		if s == nil {
+			break
+		}
+		switch {
+		case ctx.Err() != nil:
+			p.skip(s, ctx.Err())
+		case failed && !pw.keepGoing:
+			p.skip(s, ErrSkipped)
+		default:
+			s.ws.release()
+		}
+		if serr := s.ws.Print(ctx); err == nil {
+			err = serr
+		}
+		if s.ws.Count(Failed) > 0 {
+			failed = true
+		}
+		if pw.accessible && !pw.silent {
+			p.mu.Lock()
+			l := p.stageLine(s, pw.markers(false), pw.words(false), ColorNone)
+			p.mu.Unlock()
+			fmt.Fprintln(pw.out, l)
+		}
+	}
+
+	if d != nil {
+		if werr := d.finish(e); err == nil && werr != nil {
+			err = fmt.Errorf("%w: %w", ErrOutputClosed, werr)
+		}
+	} else if !pw.accessible && !pw.silent {
+		_, lines := p.frame(false, true)
+		for _, l := range lines {
+			fmt.Fprintln(pw.out, l)
+		}
+	}
+	return p.summary(), err
+}
+
+// skip cancels the stage and fails its pending Workers with err
+func (p *Pipeline) skip(s *stage, err error) {
+	p.mu.Lock()
+	s.skipped = true
+	p.mu.Unlock()
+	ws := s.ws
+	ws.cancel()
+	ws.mu.Lock()
+	pending := make([]*Worker, 0, len(ws.Workers))
+	for _, worker := range ws.members() {
+		if !worker.State.finished() {
+			pending = append(pending, worker)
+		}
+	}
+	for ws.queue.Len() > 0 {
+		heap.Pop(&ws.queue)
+	}
+	ws.mu.Unlock()
+	for _, worker := range pending {
+		worker.FailWith(worker.finalizeIdle(err))
+	}
+}
+
+// release lets the queued Workers of a Pipeline stage start
+func (w *WorkerSet) release() {
+	w.mu.Lock()
+	w.held = false
+	w.mu.Unlock()
+	w.dispatch()
+}
+
+// frame draws every stage: a summary line for each finished stage, the name
+// and Workers of the running stage, and the name of each stage to come
+func (p *Pipeline) frame(isTerm, final bool) (history, lines []string) {
+	p.ws.drew()
+	p.mu.Lock()
+	stages := make([]stage, len(p.stages))
+	for i, s := range p.stages {
+		stages[i] = *s
+	}
+	current := p.current
+	p.mu.Unlock()
+
+	pw := p.ws
+	level := pw.level(isTerm)
+	width := 0
+	if isTerm {
+		width, _ = pw.Size()
+	}
+	markers := pw.markers(isTerm)
+	words := pw.words(isTerm)
+	for i, s := range stages {
+		switch {
+		case i < current || s.skipped:
+			lines = append(lines, p.stageLine(&s, markers, words, level))
+		case i == current:
+			lines = append(lines, markers[Pending]+" "+stripControl(s.name))
+			h, l := s.ws.frame(isTerm, false)
+			history = append(history, h...)
+			lines = append(lines, l...)
+		default:
+			l := pw.plainMarker(Pending, words) + " " + stripControl(s.name)
+			lines = append(lines, colorize(l, pw.theme.DimColor, level))
+		}
+	}
+	for i, l := range lines {
+		lines[i] = truncate(l, widthOrMax(width))
+	}
+	return history, lines
+}
+
+// stageLine summarizes a finished or skipped stage using the given markers
+func (p *Pipeline) stageLine(s *stage, markers [numStates]string, words bool, level ColorLevel) string {
+	pw := p.ws
+	m := &pw.messages
+	if s.skipped {
+		l := pw.plainMarker(Canceled, words) + " " + fmt.Sprintf(m.Skipped, stripControl(s.name))
+		return colorize(l, pw.theme.DimColor, level)
+	}
+	counts, _ := s.ws.tally(s.ws.Snapshot())
+	marker := markers[Completed]
+	if counts[Failed] > 0 {
+		marker = markers[Failed]
+	}
+	return marker + " " + fmt.Sprintf(m.Stage, stripControl(s.name), counts[Completed], counts[Failed])
+}
+
+func (p *Pipeline) refreshInterval() (active, idle, slowest time.Duration) {
+	return p.ws.refreshInterval()
+}
+
+// title follows the progress of the running stage
+func (p *Pipeline) title() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current >= len(p.stages) || !p.ws.advancedEscapes {
+		return ""
+	}
+	s := p.stages[p.current]
+	counts, total := s.ws.tally(s.ws.Snapshot())
+	done := counts[Completed] + counts[Failed] + counts[Canceled]
+	return p.ws.caps().titleSeq(fmt.Sprintf("%s %d/%d", s.name, done, total))
+}
+
+func (p *Pipeline) caps() escapeCaps {
+	return p.ws.caps()
+}
+
+func (p *Pipeline) guard(e *escWriter) {
+	p.ws.guard(e)
+}
+
+func (p *Pipeline) inline() bool {
+	return p.ws.inline()
+}
+
+func (p *Pipeline) degraded() bool {
+	return p.ws.degraded()
+}
+
+func (p *Pipeline) wrote(b []byte, d time.Duration) {
+	p.ws.wrote(b, d)
+}
+
+func (p *Pipeline) logf(format string, args ...interface{}) {
+	p.ws.log.printf(format, args...)
+}
+
+// running returns the WorkerSet of the running stage, or nil once every
+// stage has run
+func (p *Pipeline) running() *WorkerSet {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current >= len(p.stages) {
+		return nil
+	}
+	return p.stages[p.current].ws
+}
+
+// NeedsRedraw reports whether the Pipeline has moved to another stage or
+// the running stage needs redrawing, as WorkerSet.NeedsRedraw does
+func (p *Pipeline) NeedsRedraw() bool {
+	if p.ws.NeedsRedraw() {
+		return true
+	}
+	ws := p.running()
+	return ws != nil && ws.NeedsRedraw()
+}
+
+// NextAnimationDeadline returns the running stage's NextAnimationDeadline,
+// or the zero Time once every stage has run
+func (p *Pipeline) NextAnimationDeadline() time.Time {
+	ws := p.running()
+	if ws == nil {
+		return time.Time{}
+	}
+	return ws.NextAnimationDeadline()
+}
+
+func (p *Pipeline) timing() Clock {
+	return p.ws.timing()
+}
+
+// setDisplay has every stage, including those added later, poke d when it
+// changes
+func (p *Pipeline) setDisplay(d *display) {
+	p.ws.setDisplay(d)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.display = d
+	for _, s := range p.stages {
+		s.ws.setDisplay(d)
+	}
+}
+
+// Refresh redraws the Pipeline straight away, as WorkerSet.Refresh does
+func (p *Pipeline) Refresh() {
+	p.ws.Refresh()
+}
+
+// summary reports how each stage ended
+func (p *Pipeline) summary() PipelineSummary {
+	p.mu.Lock()
+	stages := make([]stage, len(p.stages))
+	for i, s := range p.stages {
+		stages[i] = *s
+	}
+	p.mu.Unlock()
+
+	var sum PipelineSummary
+	for _, s := range stages {
+		counts, _ := s.ws.tally(s.ws.Snapshot())
+		sum.Stages = append(sum.Stages, StageSummary{
+			Name:      s.name,
+			Completed: counts[Completed],
+			Failed:    counts[Failed],
+			Canceled:  counts[Canceled],
+			Pending:   counts[Pending] + counts[Stopping],
+			Skipped:   s.skipped,
+		})
+		sum.Completed += counts[Completed]
+		sum.Failed += counts[Failed]
+		sum.Canceled += counts[Canceled]
+		sum.Pending += counts[Pending] + counts[Stopping]
+	}
+	return sum
+}
@@ -0,0 +1,151 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPipelineAdvance(t *testing.T) {
+	var buf syncBuf
+	p := NewPipeline(WithOutput(&buf), WithTTY(false))
+	build, push := New(), New()
+	p.AddStage("build", build)
+	p.AddStage("push", push)
+
+	var built atomic.Bool
+	var early atomic.Bool
+	build.Go("compile", func(ctx context.Context) error {
+		time.Sleep(30 * time.Millisecond)
+		built.Store(true)
+		return nil
+	})
+	push.Go("upload", func(ctx context.Context) error {
+		if !built.Load() {
+			early.Store(true)
+		}
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond)
+	if push.Count(Pending) != 1 {
+		t.Errorf("push stage started before Print")
+	}
+
+	sum, err := p.Print(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if early.Load() {
+		t.Errorf("push stage ran before build finished")
+	}
+	if sum.Completed != 2 || sum.Failed != 0 || len(sum.Stages) != 2 {
+		t.Errorf("summary %+v", sum)
+	}
+	want := "[ OK ] build: 1 completed, 0 failed\n[ OK ] push: 1 completed, 0 failed\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestPipelineFailureSkips(t *testing.T) {
+	for _, keepGoing := range []bool{false, true} {
+		var buf syncBuf
+		p := NewPipeline(WithOutput(&buf), WithTTY(false), WithContinueOnFailure(keepGoing))
+		build, push, rollout := New(), New(), New()
+		p.AddStage("build", build)
+		p.AddStage("push", push)
+		p.AddStage("rollout", rollout)
+
+		var ran atomic.Bool
+		build.Go("compile", func(ctx context.Context) error { return nil })
+		push.Go("upload", func(ctx context.Context) error { return errors.New("boom") })
+		rollout.Go("deploy", func(ctx context.Context) error {
+			ran.Store(true)
+			return nil
+		})
+
+		sum, err := p.Print(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ran.Load() != keepGoing {
+			t.Errorf("keepGoing %v: rollout ran %v", keepGoing, ran.Load())
+		}
+		last := sum.Stages[2]
+		if last.Skipped == keepGoing {
+			t.Errorf("keepGoing %v: rollout stage %+v", keepGoing, last)
+		}
+		// skipped Workers count as failed
+		if want := map[bool]int{false: 2, true: 1}[keepGoing]; sum.Failed != want {
+			t.Errorf("keepGoing %v: summary %+v", keepGoing, sum)
+		}
+		if keepGoing {
+			continue
+		}
+		if last.Failed != 1 {
+			t.Errorf("skipped stage %+v", last)
+		}
+		if err := rollout.Workers[0].Err(); !errors.Is(err, ErrSkipped) {
+			t.Errorf("skipped Worker error %v", err)
+		}
+		if !strings.Contains(buf.String(), "rollout: skipped") {
+			t.Errorf("output %q", buf.String())
+		}
+	}
+}
+
+func TestPipelineFrame(t *testing.T) {
+	p := NewPipeline(WithColor(false))
+	build, push, rollout := New(), New(), New()
+	p.AddStage("build", build)
+	p.AddStage("push", push)
+	p.AddStage("rollout", rollout)
+	for _, name := range []string{"a", "b", "c"} {
+		build.Add(name).Done()
+		push.Add(name)
+		rollout.Add(name)
+	}
+
+	// the running stage is drawn in full, the others on a line each
+	for i, want := range []int{6, 6, 6, 3} {
+		p.advance(i)
+		_, lines := p.frame(true, false)
+		if len(lines) != want {
+			t.Errorf("stage %d: %d lines, want %d:\n%s", i, len(lines), want, strings.Join(lines, "\n"))
+		}
+		if i < 3 {
+			for _, w := range []*WorkerSet{build, push, rollout}[i].Workers {
+				w.Done()
+			}
+		}
+	}
+	_, lines := p.frame(true, false)
+	if got, want := lines[0], "✔ build: 3 completed, 0 failed"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPipelineSilent(t *testing.T) {
+	for _, opts := range [][]Option{{WithTTY(true)}, {WithTTY(false)}, {WithAccessible(true)}} {
+		out := &syncBuf{}
+		p := NewPipeline(append([]Option{WithSilent(true), WithOutput(out)}, opts...)...)
+		first, second := New(), New()
+		p.AddStage("first", first)
+		p.AddStage("second", second)
+		first.Go("fails", func(context.Context) error { return errors.New("broken") })
+		second.Go("skipped", func(context.Context) error { return nil })
+		sum, err := p.Print(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != "" {
+			t.Errorf("wrote %q", out.String())
+		}
+		if len(sum.Stages) != 2 {
+			t.Errorf("got %+v", sum)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package multistatus
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WithStartOnPrint holds Workers started with Go or Command until Print is
+// called, rather than starting them as they're added, so the WorkerSet can
+// be shown with PrintPlan without running anything
+func WithStartOnPrint(b bool) Option {
+	return func(w *WorkerSet) {
+		w.held = b
+	}
+}
+
+// PrintPlan writes the Workers that Print would run to out, without starting
+// them, as a static block. The concurrency limit and start rate are noted
+// first, then the pending Workers in the order they would start, with their
+// groups and priorities. Workers started with Go or Command only wait for
+// Print with WithStartOnPrint; otherwise they may already be running. The
+// WorkerSet can still be printed afterwards.
+func (w *WorkerSet) PrintPlan(out io.Writer) error {
+	w.mu.Lock()
+	queued := append(workerQueue(nil), w.queue...)
+	sort.Sort(planOrder{queued})
+	var plan []WorkerStatus
+	now := w.now()
+	for _, worker := range queued {
+		plan = append(plan, worker.statusAt(now))
+	}
+	for _, worker := range w.members() {
+		if worker.queueIndex < 0 && !worker.State.finished() {
+			plan = append(plan, worker.statusAt(now))
+		}
+	}
+	w.mu.Unlock()
+
+	m := &w.messages
+	var buf strings.Builder
+	buf.WriteString(m.pluralf(len(plan), m.Plan, len(plan)))
+	if w.concurrency > 0 {
+		buf.WriteString(fmt.Sprintf(m.PlanConcurrency, w.concurrency))
+	}
+	if w.startRate != "" {
+		buf.WriteString(" " + fmt.Sprintf(m.StartRate, w.startRate))
+	}
+	buf.WriteString("\n")
+	for _, v := range plan {
+		line := "  - " + stripControl(v.Name)
+		var notes []string
+		if v.Group != "" {
+			notes = append(notes, fmt.Sprintf(m.PlanGroup, stripControl(v.Group)))
+		}
+		if v.Priority != 0 {
+			notes = append(notes, fmt.Sprintf(m.PlanPriority, v.Priority))
+		}
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, ", ") + ")"
+		}
+		buf.WriteString(line + "\n")
+	}
+	_, err := io.WriteString(out, buf.String())
+	return err
+}
+
+// planOrder sorts a copy of the queue into the order its Workers start,
+// without moving them in the heap
+type planOrder struct {
+	q workerQueue
+}
+
+func (p planOrder) Len() int           { return len(p.q) }
+func (p planOrder) Less(i, j int) bool { return p.q.Less(i, j) }
+func (p planOrder) Swap(i, j int)      { p.q[i], p.q[j] = p.q[j], p.q[i] }
@@ -0,0 +1,60 @@
+package multistatus
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPrintPlan(t *testing.T) {
+	var started atomic.Int32
+	ws := New(WithSilent(true), WithStartOnPrint(true), WithConcurrency(2), WithStartRate(10, time.Second))
+	task := func(context.Context) error {
+		started.Add(1)
+		return nil
+	}
+	ws.Go("fetch", task).SetGroup("deps")
+	ws.Go("lint", task)
+	ws.Go("build", task).SetPriority(5)
+	deploy := ws.Go("deploy", task)
+	deploy.SetGroup("release")
+	deploy.SetPriority(-1)
+	byHand := ws.Add("by hand")
+	ws.Add("finished").Done()
+
+	var buf bytes.Buffer
+	if err := ws.PrintPlan(&buf); err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "plan.golden", buf.Bytes())
+
+	// nothing has started, and the set still runs as usual
+	if n := started.Load(); n != 0 {
+		t.Fatalf("%d workers started before Print", n)
+	}
+	if sum := ws.Summary(); sum.Pending != 5 {
+		t.Errorf("%d pending after the plan, want 5", sum.Pending)
+	}
+	byHand.Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := started.Load(); n != 4 {
+		t.Errorf("%d workers started, want 4", n)
+	}
+	if sum := ws.Summary(); sum.Completed != 6 || !sum.OK {
+		t.Errorf("got %+v", sum)
+	}
+}
+
+func TestPrintPlanSingular(t *testing.T) {
+	ws := New(WithSilent(true))
+	ws.Add("only")
+	var buf bytes.Buffer
+	ws.PrintPlan(&buf)
+	if got, want := buf.String(), "would run 1 task\n  - only\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Reasons for a PrintError, which it matches with errors.Is
+var (
+	// ErrCanceled is the reason Print returned after its context was
+	// canceled. It matches context.Canceled.
+	ErrCanceled = fmt.Errorf("multistatus: print canceled: %w", context.Canceled)
+
+	// ErrDeadline is the reason Print returned after its context's
+	// deadline passed. It matches context.DeadlineExceeded.
+	ErrDeadline = fmt.Errorf("multistatus: print deadline exceeded: %w", context.DeadlineExceeded)
+
+	// ErrOutputClosed is the reason Print returned when writing to the
+	// output failed, such as when a pipe was closed
+	ErrOutputClosed = errors.New("multistatus: output closed")
+
+	// ErrFailFast is the reason Print returned after WithFailFast canceled
+	// the run. The PrintError's Err is then the first failure, prefixed by
+	// its Worker's name.
+	ErrFailFast = errors.New("multistatus: too many failures")
+)
+
+// A PrintError is returned by Print when it didn't see every Worker finish
+// and print the result, with a Summary of the WorkerSet as it was when Print
+// returned. Use errors.Is with its Reason, or with the underlying error,
+// such as context.Canceled or the error writing to the output, to tell why.
+type PrintError struct {
+	// Reason is ErrCanceled, ErrDeadline, ErrClosed, ErrOutputClosed or
+	// ErrFailFast
+	Reason error
+
+	// Err is the underlying error, if any: the context's error, the error
+	// writing to the output, or the first failure
+	Err error
+
+	Summary Summary
+}
+
+func (e *PrintError) Error() string {
+	if (e.Reason == ErrOutputClosed || e.Reason == ErrFailFast) && e.Err != nil {
+		return e.Reason.Error() + ": " + e.Err.Error()
+	}
+	return e.Reason.Error()
+}
+
+// Unwrap returns the Reason and the underlying error
+func (e *PrintError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Reason}
+	}
+	return []error{e.Reason, e.Err}
+}
+
+// firstFailureErr returns the error of the Worker that failed first,
+// prefixed by its name, or nil if none has failed
+func (w *WorkerSet) firstFailureErr() error {
+	err, worker := w.FirstError()
+	switch {
+	case worker == nil:
+		return nil
+	case err == nil:
+		return fmt.Errorf("%s: %s", worker.Name, w.messages.Failed)
+	}
+	return fmt.Errorf("%s: %w", worker.Name, err)
+}
+
+// printError returns the PrintError explaining why Print returned, given
+// whether it was canceled and the first error writing to the output, or nil
+// if it finished normally
+func (w *WorkerSet) printError(ctx context.Context, canceled bool, werr error) error {
+	w.mu.Lock()
+	failedFast := w.failedFast
+	w.mu.Unlock()
+	var e *PrintError
+	switch {
+	case werr != nil:
+		e = &PrintError{Reason: ErrOutputClosed, Err: werr}
+	case failedFast:
+		e = &PrintError{Reason: ErrFailFast, Err: w.firstFailureErr()}
+	case !canceled:
+		return nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		e = &PrintError{Reason: ErrDeadline, Err: ctx.Err()}
+	case ctx.Err() != nil:
+		e = &PrintError{Reason: ErrCanceled, Err: ctx.Err()}
+	default:
+		e = &PrintError{Reason: ErrClosed}
+	}
+	e.Summary = w.Summary()
+	return e
+}
@@ -0,0 +1,93 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// brokenWriter fails every write, like a closed pipe
+type brokenWriter struct{}
+
+var errBrokenPipe = errors.New("broken pipe")
+
+func (brokenWriter) Write(p []byte) (int, error) {
+	return 0, errBrokenPipe
+}
+
+func TestPrintError(t *testing.T) {
+	errTask := errors.New("task failed")
+	for _, tc := range []struct {
+		name   string
+		print  func() (*WorkerSet, error)
+		reason error
+		is     error
+		ok     bool
+	}{
+		{"canceled", func() (*WorkerSet, error) {
+			ws := New(WithSilent(true))
+			ws.Add("stuck")
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+			return ws, ws.Print(ctx)
+		}, ErrCanceled, context.Canceled, false},
+		{"deadline", func() (*WorkerSet, error) {
+			ws := New(WithSilent(true))
+			ws.Add("stuck")
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			return ws, ws.Print(ctx)
+		}, ErrDeadline, context.DeadlineExceeded, false},
+		{"closed", func() (*WorkerSet, error) {
+			ws := New(WithSilent(true))
+			ws.Add("stuck")
+			time.AfterFunc(10*time.Millisecond, func() { ws.Close() })
+			return ws, ws.Print(context.Background())
+		}, ErrClosed, ErrClosed, false},
+		{"output closed", func() (*WorkerSet, error) {
+			ws := New(WithOutput(brokenWriter{}), WithTTY(false))
+			ws.Add("done").Done()
+			return ws, ws.Print(context.Background())
+		}, ErrOutputClosed, errBrokenPipe, true},
+		{"fail fast", func() (*WorkerSet, error) {
+			ws := New(WithSilent(true), WithFailFast(true))
+			ws.Add("stuck")
+			ws.Go("fails", func(context.Context) error { return errTask })
+			return ws, ws.Print(context.Background())
+		}, ErrFailFast, errTask, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err := tc.print()
+			var pe *PrintError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Print returned %v, want a *PrintError", err)
+			}
+			if pe.Reason != tc.reason || !errors.Is(err, tc.reason) {
+				t.Errorf("Reason is %v, want %v", pe.Reason, tc.reason)
+			}
+			if !errors.Is(err, tc.is) {
+				t.Errorf("%v doesn't match %v", err, tc.is)
+			}
+			if pe.Summary.Schema != SchemaVersion || len(pe.Summary.Workers) != len(ws.Summary().Workers) {
+				t.Errorf("Summary isn't populated: %+v", pe.Summary)
+			}
+			if pe.Summary.OK != tc.ok {
+				t.Errorf("Summary has OK %v, want %v", pe.Summary.OK, tc.ok)
+			}
+		})
+	}
+}
+
+func TestPrintErrorFailFastNames(t *testing.T) {
+	ws := New(WithSilent(true), WithFailFast(true))
+	w := ws.Add("by hand")
+	w.Fail()
+	err := ws.Print(context.Background())
+	if got, want := err.Error(), "multistatus: too many failures: by hand: failed"; got != want {
+		t.Errorf("Print returned %q, want %q", got, want)
+	}
+	if pe := (*PrintError)(nil); !errors.As(err, &pe) || pe.Summary.FirstFailed != w.ID() {
+		t.Errorf("Summary doesn't name the first failure")
+	}
+}
@@ -0,0 +1,48 @@
+package multistatus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuietTTY(t *testing.T) {
+	for _, accessible := range []bool{false, true} {
+		var buf syncBuf
+		ws := New(WithOutput(&buf), WithTTY(true), WithQuiet(true), WithAccessible(accessible),
+			WithRefreshInterval(5*time.Millisecond))
+		ws.Add("a").Done()
+		ws.Add("b").Fail()
+		slow := ws.Add("c")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			if s := buf.String(); s != "" {
+				t.Errorf("accessible %v: wrote %q before finishing", accessible, s)
+			}
+			slow.Done()
+		}()
+		ws.Print(context.Background())
+		got := buf.String()
+		if strings.ContainsAny(got, "\033\r") {
+			t.Errorf("accessible %v: escapes in %q", accessible, got)
+		}
+		want := "  [ OK ] a\n  [FAIL] b\n  [ OK ] c\nAll 3 tasks finished: 2 completed, 1 failed.\n"
+		if got != want {
+			t.Errorf("accessible %v: got %q\nwant %q", accessible, got, want)
+		}
+	}
+}
+
+func TestQuietCanceled(t *testing.T) {
+	var buf syncBuf
+	ws := New(WithOutput(&buf), WithTTY(true), WithQuiet(true))
+	ws.Add("a").Done()
+	ws.Add("b")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	ws.Print(ctx)
+	if got, want := buf.String(), "  [ OK ] a\n  [ .. ] b\nCanceled.\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
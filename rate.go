@@ -0,0 +1,155 @@
+package multistatus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSparkline holds the levels of DefaultTheme's Sparkline
+const defaultSparkline = "▁▂▃▄▅▆▇█"
+
+// WithRateSparkline records how many tasks the set's Counters count in each
+// interval of length bucket, keeping the last n intervals, and shows them in
+// the header as a sparkline of recent throughput along with the latest rate,
+// such as "rate ▁▂▄▇▆▃ 2.3k/s". The counts are included in each Counter's
+// CounterStatus. Counters added before WithRateSparkline is given don't
+// record them. The header must be enabled with WithHeader for the sparkline
+// to be shown.
+func WithRateSparkline(bucket time.Duration, n int) Option {
+	return func(w *WorkerSet) {
+		w.rateBucket = bucket
+		w.rateBuckets = n
+	}
+}
+
+// A rateRing counts tasks in fixed intervals, aligned to the clock so the
+// rings of different Counters line up. It is sampled whenever the Counter's
+// status is taken, with the tasks counted since the last sample shared
+// between the intervals in between by time. It is guarded by the
+// WorkerSet's lock.
+type rateRing struct {
+	bucket time.Duration
+	counts []int64
+	head   int
+
+	// start is when the current interval began, and last and at are the
+	// total and time of the last sample
+	start time.Time
+	last  int64
+	at    time.Time
+}
+
+func newRateRing(bucket time.Duration, n int, now time.Time) *rateRing {
+	return &rateRing{bucket: bucket, counts: make([]int64, n+1), start: alignDown(now, bucket), at: now}
+}
+
+// alignDown returns t moved back to a multiple of d, as t.Truncate would, but
+// keeping t's monotonic clock reading so intervals measured from it aren't
+// thrown off by the wall clock being set
+func alignDown(t time.Time, d time.Duration) time.Time {
+	return t.Add(-t.Sub(t.Truncate(d)))
+}
+
+// sample records that total tasks have been counted as of now
+func (r *rateRing) sample(now time.Time, total int64) {
+	delta := float64(total - r.last)
+	r.last = total
+	from := r.at
+	r.at = now
+	if kept := time.Duration(len(r.counts)) * r.bucket; now.Sub(r.start) >= kept {
+		// every interval kept starts after the last sample
+		for i := range r.counts {
+			r.counts[i] = 0
+		}
+		r.start = alignDown(now, r.bucket).Add(r.bucket - kept)
+		if from.Before(r.start) {
+			delta *= float64(now.Sub(r.start)) / float64(now.Sub(from))
+			from = r.start
+		}
+	}
+	for end := r.start.Add(r.bucket); !now.Before(end); end = end.Add(r.bucket) {
+		if span := now.Sub(from); span > 0 {
+			part := delta * float64(end.Sub(from)) / float64(span)
+			r.counts[r.head] += int64(part + 0.5)
+			delta -= part
+		}
+		from = end
+		r.head = (r.head + 1) % len(r.counts)
+		r.counts[r.head] = 0
+		r.start = end
+	}
+	r.counts[r.head] += int64(delta + 0.5)
+}
+
+// completed returns the counts of the intervals that have ended, oldest first
+func (r *rateRing) completed() []int64 {
+	n := len(r.counts) - 1
+	out := make([]int64, n)
+	for i := range out {
+		out[i] = r.counts[(r.head+1+i)%len(r.counts)]
+	}
+	return out
+}
+
+// rateLine shows the throughput of the Counters in snap for the header, or
+// returns an empty string if none record it
+func (w *WorkerSet) rateLine(snap []WorkerStatus) string {
+	var sum []int64
+	for _, v := range snap {
+		if v.Counter == nil || len(v.Counter.Rates) == 0 {
+			continue
+		}
+		if sum == nil {
+			sum = make([]int64, len(v.Counter.Rates))
+		}
+		for i, n := range v.Counter.Rates {
+			sum[i] += n
+		}
+	}
+	if sum == nil {
+		return ""
+	}
+	latest := float64(sum[len(sum)-1]) / w.rateBucket.Seconds()
+	return fmt.Sprintf(w.messages.Rate, sparkline(sum, w.theme.Sparkline), formatThroughput(latest))
+}
+
+// sparkline draws counts scaled to their maximum using levels, from lowest
+// to highest, or lists them as numbers if levels is empty
+func sparkline(counts []int64, levels string) string {
+	steps := []rune(levels)
+	if len(steps) == 0 {
+		s := make([]string, len(counts))
+		for i, n := range counts {
+			s[i] = strconv.FormatInt(n, 10)
+		}
+		return strings.Join(s, " ")
+	}
+	var max int64
+	for _, n := range counts {
+		if n > max {
+			max = n
+		}
+	}
+	var buf strings.Builder
+	for _, n := range counts {
+		i := 0
+		if max > 0 {
+			i = int(n * int64(len(steps)-1) / max)
+		}
+		buf.WriteRune(steps[i])
+	}
+	return buf.String()
+}
+
+// formatThroughput formats a rate per second compactly, such as "2.3k/s"
+func formatThroughput(r float64) string {
+	switch {
+	case r >= 1e6:
+		return fmt.Sprintf("%.1fM/s", r/1e6)
+	case r >= 1e3:
+		return fmt.Sprintf("%.1fk/s", r/1e3)
+	}
+	return fmt.Sprintf("%.1f/s", r)
+}
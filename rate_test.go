@@ -0,0 +1,86 @@
+package multistatus
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rateSet returns a WorkerSet on a fake clock with a Counter that has counted
+// perSecond[i] tasks in the i-th second, sampled once a second
+func rateSet(t *testing.T, perSecond []int, opts ...Option) (*WorkerSet, *Counter) {
+	t.Helper()
+	clock := newFakeClock()
+	opts = append([]Option{WithSilent(true), WithClock(clock), WithHeader("run"),
+		WithRateSparkline(time.Second, len(perSecond))}, opts...)
+	ws := New(opts...)
+	c := ws.AddCounter("counter", 0)
+	for _, n := range perSecond {
+		for i := 0; i < n; i++ {
+			c.Incr()
+		}
+		clock.Advance(time.Second)
+		ws.Snapshot()
+	}
+	return ws, c
+}
+
+func TestRateSparkline(t *testing.T) {
+	ws, _ := rateSet(t, []int{1, 2, 4, 8, 6})
+	_, lines := ws.frame(false, false)
+	if want := " · rate ▁▂▄█▆ 6.0/s"; !strings.HasSuffix(lines[0], want) {
+		t.Errorf("got header %q, want it to end with %q", lines[0], want)
+	}
+	snap := ws.Snapshot()
+	if got, want := snap[0].Counter.Rates, []int64{1, 2, 4, 8, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got rates %v, want %v", got, want)
+	}
+}
+
+func TestRateSparklinePlain(t *testing.T) {
+	theme := DefaultTheme
+	theme.Sparkline = ""
+	ws, _ := rateSet(t, []int{3, 0, 1200}, WithTheme(theme))
+	_, lines := ws.frame(false, false)
+	if want := " · rate 3 0 1200 1.2k/s"; !strings.HasSuffix(lines[0], want) {
+		t.Errorf("got header %q, want it to end with %q", lines[0], want)
+	}
+}
+
+func TestRateRingShared(t *testing.T) {
+	start := at(0)
+	r := newRateRing(time.Second, 4, start)
+	r.sample(start.Add(500*time.Millisecond), 2)
+	r.sample(start.Add(2500*time.Millisecond), 10)
+	r.sample(start.Add(4*time.Second), 13)
+	if got, want := r.completed(), []int64{4, 4, 3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// a gap longer than the ring keeps only the share of the intervals it holds
+	r.sample(start.Add(14*time.Second), 113)
+	if got, want := r.completed(), []int64{10, 10, 10, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after a gap got %v, want %v", got, want)
+	}
+}
+
+func TestSparklineLevels(t *testing.T) {
+	for _, tc := range []struct {
+		counts []int64
+		want   string
+	}{
+		{[]int64{0, 0, 0}, "▁▁▁"},
+		{[]int64{0, 7, 14}, "▁▄█"},
+		{[]int64{5}, "█"},
+	} {
+		if got := sparkline(tc.counts, defaultSparkline); got != tc.want {
+			t.Errorf("sparkline(%v) = %q, want %q", tc.counts, got, tc.want)
+		}
+	}
+	for r, want := range map[float64]string{0.5: "0.5/s", 999: "999.0/s", 2300: "2.3k/s", 4.5e6: "4.5M/s"} {
+		if got := formatThroughput(r); got != want {
+			t.Errorf("formatThroughput(%v) = %q, want %q", r, got, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package multistatus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// recording writes the frames drawn on the terminal as an asciicast v2 file
+type recording struct {
+	out       io.Writer
+	keepalive time.Duration
+	limit     int
+
+	mu      sync.Mutex
+	start   time.Time
+	started bool
+	header  []byte
+
+	// last is the payload of the last frame recorded, and lastAt when the
+	// last event was
+	last   []byte
+	lastAt time.Time
+
+	// events are kept only with a limit, until finish, and dropped counts
+	// those dropped from the front to keep within it
+	events  []castEvent
+	size    int
+	dropped int
+
+	err error
+}
+
+type castEvent struct {
+	at   time.Duration
+	line []byte
+}
+
+// WithRecording records every frame Print draws on a terminal to out as an
+// asciicast v2 file, for replaying the run with asciinema. A frame identical
+// to the last one recorded is left out, so a long idle run stays small, but
+// an empty event is still recorded every 30 seconds, or as set by
+// WithRecordingKeepalive, to keep the pace of the replay. Events are written
+// as they happen unless WithRecordingLimit is given.
+func WithRecording(out io.Writer) Option {
+	return func(w *WorkerSet) {
+		w.recording = &recording{out: out, keepalive: 30 * time.Second}
+	}
+}
+
+// WithRecordingKeepalive sets how often an event is recorded while frames
+// don't change, 30 seconds by default. 0 records none. It has no effect
+// without WithRecording.
+func WithRecordingKeepalive(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		if w.recording != nil {
+			w.recording.keepalive = d
+		}
+	}
+}
+
+// WithRecordingLimit keeps the recording's events within n bytes, dropping
+// the oldest to make room and marking where they were dropped. The events are
+// then held in memory and written when Print returns. The final frame is
+// always kept. It has no effect without WithRecording.
+func WithRecordingLimit(n int) Option {
+	return func(w *WorkerSet) {
+		if w.recording != nil {
+			w.recording.limit = n
+		}
+	}
+}
+
+// frame records p written to the terminal at now, sized as returned by size
+func (r *recording) frame(p []byte, now time.Time, size func() (width, height int)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.started {
+		width, height := size()
+		r.header, _ = json.Marshal(struct {
+			Version   int   `json:"version"`
+			Width     int   `json:"width"`
+			Height    int   `json:"height"`
+			Timestamp int64 `json:"timestamp"`
+		}{2, width, height, now.Unix()})
+		r.header = append(r.header, '\n')
+		r.started, r.start = true, now
+		if r.limit == 0 {
+			r.write(r.header)
+		}
+	} else if bytes.Equal(p, r.last) {
+		if r.keepalive == 0 || now.Sub(r.lastAt) < r.keepalive {
+			return
+		}
+		p = nil
+	}
+	if p != nil {
+		r.last = append(r.last[:0], p...)
+	}
+	r.lastAt = now
+	r.add(now.Sub(r.start), "o", string(p))
+}
+
+// add records an event of the given type, writing it straight away unless
+// there is a limit
+func (r *recording) add(at time.Duration, typ, data string) {
+	line, _ := json.Marshal([]interface{}{at.Seconds(), typ, data})
+	line = append(line, '\n')
+	if r.limit == 0 {
+		r.write(line)
+		return
+	}
+	r.events = append(r.events, castEvent{at, line})
+	r.size += len(line)
+	for r.size > r.limit && len(r.events) > 1 {
+		r.size -= len(r.events[0].line)
+		r.events = r.events[1:]
+		r.dropped++
+	}
+}
+
+// write writes b to the recording, keeping the first error
+func (r *recording) write(b []byte) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = r.out.Write(b)
+}
+
+// finish writes the events held back by a limit, returning the first error
+// writing the recording
+func (r *recording) finish() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.limit == 0 || !r.started {
+		return r.err
+	}
+	events := r.events
+	r.write(r.header)
+	if r.dropped > 0 {
+		r.limit = 0
+		r.add(events[0].at, "m", fmt.Sprintf("%d earlier events dropped", r.dropped))
+	}
+	for _, e := range events {
+		r.write(e.line)
+	}
+	r.events = nil
+	return r.err
+}
@@ -0,0 +1,102 @@
+package multistatus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// castEvents parses an asciicast v2 recording into its header and events
+func castEvents(t *testing.T, s string) (header map[string]interface{}, events [][]interface{}) {
+	t.Helper()
+	sc := bufio.NewScanner(strings.NewReader(s))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		if header == nil {
+			if err := json.Unmarshal(sc.Bytes(), &header); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		var e []interface{}
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("%v: %s", err, sc.Text())
+		}
+		events = append(events, e)
+	}
+	return header, events
+}
+
+func TestRecordingIdle(t *testing.T) {
+	const idle = time.Hour
+	clock := newFakeClock()
+	var out, rec syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithSize(60, 20), WithClock(clock),
+		WithRecording(&rec), WithRefreshInterval(time.Millisecond),
+		WithIdleRefreshInterval(time.Millisecond), WithSharedDisplay(false))
+	worker := ws.Add("task")
+	worker.SetBlocked(true)
+	go func() {
+		for i := time.Duration(0); i < idle; i += 10 * time.Second {
+			clock.Advance(10 * time.Second)
+			time.Sleep(200 * time.Microsecond)
+		}
+		worker.Done()
+	}()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	header, events := castEvents(t, rec.String())
+	if header["version"].(float64) != 2 || header["width"].(float64) != 60 {
+		t.Errorf("header is %v", header)
+	}
+	if max := int(idle/(30*time.Second)) + 10; len(events) > max {
+		t.Errorf("%d events recorded over an idle hour, want at most %d", len(events), max)
+	}
+	last := events[len(events)-1]
+	if at := last[0].(float64); at < (idle - time.Minute).Seconds() {
+		t.Errorf("last event at %vs, want near %vs", at, idle.Seconds())
+	}
+	if frame := last[2].(string); frame == "" || !strings.HasSuffix(out.String(), frame) {
+		t.Errorf("last event %q isn't the final frame drawn", frame)
+	}
+	prev := 0.0
+	for _, e := range events {
+		if at := e[0].(float64); at < prev {
+			t.Fatalf("event at %vs follows one at %vs", at, prev)
+		} else {
+			prev = at
+		}
+	}
+}
+
+func TestRecordingLimit(t *testing.T) {
+	var out, rec syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithSize(60, 20), WithRecording(&rec),
+		WithRecordingLimit(400), WithRefreshInterval(time.Millisecond), WithSharedDisplay(false))
+	worker := ws.Add("task")
+	go func() {
+		for i := 0; i < 30; i++ {
+			worker.SetStatus(strings.Repeat("x", i))
+			time.Sleep(5 * time.Millisecond)
+		}
+		worker.Done()
+	}()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	_, events := castEvents(t, rec.String())
+	if events[0][1] != "m" || !strings.Contains(events[0][2].(string), "dropped") {
+		t.Errorf("first event is %v, want a marker of those dropped", events[0])
+	}
+	if frame := events[len(events)-1][2].(string); !strings.HasSuffix(out.String(), frame) {
+		t.Errorf("last event %q isn't the final frame drawn", frame)
+	}
+	if err := New(WithRecording(&rec), WithRecordingLimit(-1)).validate(); err == nil {
+		t.Error("negative limit accepted")
+	}
+}
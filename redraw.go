@@ -0,0 +1,61 @@
+package multistatus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// NeedsRedraw reports whether a Worker has been added or has changed since
+// the last frame was drawn by Print or taken with Screenshot. Together with
+// NextAnimationDeadline it lets a program drawing the WorkerSet in its own
+// loop sleep until something will visibly change; Print's own loop is built
+// on the two.
+func (w *WorkerSet) NeedsRedraw() bool {
+	return atomic.LoadInt32(&w.dirty) == 1
+}
+
+// NextAnimationDeadline returns when the last frame next needs redrawing to
+// keep the spinner moving, or the zero Time when no Worker is pending so
+// nothing is animated. Before the first frame it returns the current time.
+// The deadline is one refresh interval, as stretched for a slow terminal
+// and reported by AdaptedRefreshInterval, after a frame drawn once something
+// changed, doubling for each frame since that found nothing changed, up to
+// the idle interval.
+func (w *WorkerSet) NextAnimationDeadline() time.Time {
+	if !w.animating() {
+		return time.Time{}
+	}
+	last, ok := w.lastFrame.Load().(time.Time)
+	if !ok {
+		return w.now()
+	}
+	active := w.AdaptedRefreshInterval()
+	_, idle, _ := w.refreshInterval()
+	wait := active
+	for i := atomic.LoadInt32(&w.quietFrames); i > 0 && wait < idle; i-- {
+		wait *= 2
+	}
+	if wait > idle && idle > active {
+		wait = idle
+	}
+	return last.Add(wait)
+}
+
+// drew records that a frame is being laid out, clearing the flag read by
+// NeedsRedraw and counting the frames in a row drawn with nothing changed
+func (w *WorkerSet) drew() {
+	if w.changed() {
+		atomic.StoreInt32(&w.quietFrames, 0)
+	} else {
+		atomic.AddInt32(&w.quietFrames, 1)
+	}
+	w.lastFrame.Store(w.now())
+}
+
+// timing returns the clock pacing the display's frames
+func (w *WorkerSet) timing() Clock {
+	if w.clock == nil {
+		return realClock{}
+	}
+	return w.clock
+}
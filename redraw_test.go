@@ -0,0 +1,176 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNeedsRedraw(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock))
+	if ws.NeedsRedraw() {
+		t.Error("empty WorkerSet needs a redraw")
+	}
+	worker := ws.Add("task")
+	for _, step := range []struct {
+		name   string
+		change func()
+	}{
+		{"Add", func() {}},
+		{"SetStatus", func() { worker.SetStatus("working") }},
+		{"AddNote", func() { worker.AddNote("note") }},
+		{"Done", worker.Done},
+	} {
+		step.change()
+		if !ws.NeedsRedraw() {
+			t.Errorf("no redraw needed after %s", step.name)
+		}
+		if _, err := ws.Screenshot(80, ScreenshotANSI); err != nil {
+			t.Fatal(err)
+		}
+		if ws.NeedsRedraw() {
+			t.Errorf("redraw still needed after the frame following %s", step.name)
+		}
+	}
+}
+
+func TestNextAnimationDeadline(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithRefreshInterval(100*time.Millisecond),
+		WithIdleRefreshInterval(time.Second))
+	if d := ws.NextAnimationDeadline(); !d.IsZero() {
+		t.Errorf("deadline is %v with nothing animating, want zero", d)
+	}
+	worker := ws.Add("task")
+	if d := ws.NextAnimationDeadline(); !d.Equal(clock.Now()) {
+		t.Errorf("deadline before the first frame is %v, want now (%v)", d, clock.Now())
+	}
+
+	// each frame finding nothing changed doubles the wait, up to the idle
+	// interval, and a change brings it back to the refresh interval
+	for i, step := range []struct {
+		change func()
+		want   time.Duration
+	}{
+		{nil, 100 * time.Millisecond},
+		{nil, 200 * time.Millisecond},
+		{nil, 400 * time.Millisecond},
+		{nil, 800 * time.Millisecond},
+		{nil, time.Second},
+		{nil, time.Second},
+		{func() { worker.SetStatus("working") }, 100 * time.Millisecond},
+		{nil, 200 * time.Millisecond},
+	} {
+		if step.change != nil {
+			step.change()
+		}
+		drawn := clock.Now()
+		ws.Screenshot(80, ScreenshotANSI)
+		if d, want := ws.NextAnimationDeadline(), drawn.Add(step.want); !d.Equal(want) {
+			t.Errorf("frame %d: deadline is %v after the frame, want %v", i, d.Sub(drawn), step.want)
+		}
+		clock.Set(ws.NextAnimationDeadline())
+	}
+	worker.Done()
+	if d := ws.NextAnimationDeadline(); !d.IsZero() {
+		t.Errorf("deadline is %v once nothing is pending, want zero", d)
+	}
+}
+
+func TestPrintFollowsDeadlines(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithOutput(&syncBuf{}), WithTTY(true), WithSharedDisplay(false), WithClock(clock),
+		WithRenderStats(true), WithRefreshInterval(100*time.Millisecond), WithIdleRefreshInterval(time.Second))
+	worker := ws.Add("task")
+	printed := make(chan error, 1)
+	go func() { printed <- ws.Print(context.Background()) }()
+	frames := func() int64 { return ws.RenderStats().Frames }
+	if !waitFor(func() bool { return frames() == 1 }) {
+		t.Fatal("no first frame")
+	}
+
+	// Print draws each frame at the deadline the last one set
+	last := clock.Now()
+	var gaps []time.Duration
+	for n := int64(2); n <= 7; n++ {
+		next := ws.NextAnimationDeadline()
+		clock.Set(next)
+		if !waitFor(func() bool { return frames() == n }) {
+			t.Fatalf("frame %d not drawn at %v", n, next.Sub(last))
+		}
+		gaps = append(gaps, next.Sub(last))
+		last = next
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, time.Second, time.Second}
+	if fmt.Sprint(gaps) != fmt.Sprint(want) {
+		t.Errorf("frames drawn %v apart, want %v", gaps, want)
+	}
+	worker.Done()
+	if err := <-printed; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshNotPrinting(t *testing.T) {
+	ws := New(WithSilent(true))
+	ws.Add("task")
+	done := make(chan struct{})
+	go func() {
+		ws.Refresh()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Refresh blocked without Print")
+	}
+}
+
+func TestRefreshConcurrent(t *testing.T) {
+	var buf syncBuf
+	ws := New(WithOutput(&buf), WithTTY(true), WithColor(false), WithRefreshInterval(time.Hour))
+	workers := make([]*Worker, 8)
+	for i := range workers {
+		workers[i] = ws.Add(fmt.Sprint("w", i))
+	}
+	// Events are delivered away from the lock, so subscribers may refresh
+	ws.Subscribe(func(Event) { ws.Refresh() })
+	printed := make(chan struct{})
+	go func() {
+		ws.Print(context.Background())
+		close(printed)
+	}()
+	waitFor(func() bool { return strings.Contains(buf.String(), "w7") })
+
+	var wg sync.WaitGroup
+	for i, w := range workers {
+		wg.Add(1)
+		go func(i int, w *Worker) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				status := fmt.Sprintf("step %d.%d", i, j)
+				w.SetStatus(status)
+				ws.Refresh()
+				// the frame is written by the time Refresh returns
+				if !strings.Contains(buf.String(), status) {
+					t.Errorf("%q not drawn after Refresh", status)
+					return
+				}
+			}
+		}(i, w)
+	}
+	wg.Wait()
+	for _, w := range workers {
+		w.Done()
+	}
+	select {
+	case <-printed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Print didn't return")
+	}
+}
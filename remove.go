@@ -0,0 +1,87 @@
+package multistatus
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	// ErrPending is returned when removing a Worker that hasn't finished
+	ErrPending = errors.New("multistatus: worker is still pending")
+
+	// ErrNotMember is returned when removing a Worker that isn't in the
+	// WorkerSet, including one already removed
+	ErrNotMember = errors.New("multistatus: worker is not in the WorkerSet")
+)
+
+// WithKeepRemovedCounts keeps removed Workers counted in the WorkerSet's
+// totals, as shown by Count, the header, and MarshalJSON, even though they no
+// longer appear individually.
+func WithKeepRemovedCounts(b bool) Option {
+	return func(w *WorkerSet) {
+		w.keepRemoved = b
+	}
+}
+
+// Remove drops a finished Worker from the WorkerSet, so it no longer appears
+// in frames, snapshots or totals (but see WithKeepRemovedCounts).
+// Subscribers receive an Event of type EventRemoved. Removing a pending
+// Worker returns ErrPending, and removing one that isn't in the WorkerSet
+// returns ErrNotMember.
+func (w *WorkerSet) Remove(worker *Worker) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+	if worker.parent != w || worker.removed {
+		w.mu.Unlock()
+		return ErrNotMember
+	}
+	if !worker.State.finished() {
+		w.mu.Unlock()
+		return ErrPending
+	}
+	i := w.indexOf(worker.id)
+	if i < 0 {
+		w.mu.Unlock()
+		return ErrNotMember
+	}
+	w.Workers = append(w.Workers[:i], w.Workers[i+1:]...)
+	worker.removed = true
+	worker.out.detach()
+	state := worker.State
+	if worker.info {
+		// information rows were never counted
+	} else if w.keepRemoved {
+		w.removedCounts[state]++
+	} else {
+		atomic.AddInt64(&w.counts[state], -1)
+	}
+	w.publish(Event{Type: EventRemoved, Worker: worker, ID: worker.id, From: state, To: state, Time: w.now()})
+	w.mu.Unlock()
+	w.touch()
+	w.log.printf("#%d %s: removed", worker.id, stripControl(worker.Name))
+	return nil
+}
+
+// tally counts the Workers in snap by state, including removed Workers when
+// WithKeepRemovedCounts is set
+func (w *WorkerSet) tally(snap []WorkerStatus) (counts [numStates]int, total int) {
+	snap = countable(snap)
+	for _, v := range snap {
+		if v.State >= 0 && v.State < numStates {
+			counts[v.State]++
+		}
+	}
+	total = len(snap)
+	if w.keepRemoved {
+		w.mu.Lock()
+		for s, n := range w.removedCounts {
+			counts[s] += n
+			total += n
+		}
+		w.mu.Unlock()
+	}
+	return counts, total
+}
@@ -0,0 +1,79 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRemove(t *testing.T) {
+	var out syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithSize(80, 24), WithColor(false), WithRefreshInterval(time.Millisecond))
+	var mu sync.Mutex
+	var removed []int64
+	ws.Subscribe(func(e Event) {
+		if e.Type == EventRemoved {
+			mu.Lock()
+			removed = append(removed, e.ID)
+			mu.Unlock()
+		}
+	})
+	probe, task := ws.Add("probe"), ws.Add("task")
+	other := New().Add("other")
+
+	if err := ws.Remove(probe); !errors.Is(err, ErrPending) {
+		t.Errorf("removing a pending Worker returned %v, want ErrPending", err)
+	}
+	if err := ws.Remove(other); !errors.Is(err, ErrNotMember) {
+		t.Errorf("removing another set's Worker returned %v, want ErrNotMember", err)
+	}
+
+	go func() {
+		probe.Done()
+		waitFor(func() bool { return strings.Contains(out.String(), "probe") })
+		if err := ws.Remove(probe); err != nil {
+			t.Errorf("removing mid-run returned %v", err)
+		}
+		if err := ws.Remove(probe); !errors.Is(err, ErrNotMember) {
+			t.Errorf("removing again returned %v, want ErrNotMember", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+		task.Done()
+	}()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ws.Close()
+
+	if snap := ws.Snapshot(); len(snap) != 1 || snap[0].Name != "task" {
+		t.Errorf("snapshot holds %+v", snap)
+	}
+	if sum := ws.Summary(); sum.Completed != 1 {
+		t.Errorf("Summary counts %d completed, want 1", sum.Completed)
+	}
+	vt := newVT(24)
+	vt.feed(out.String())
+	if got := strings.Join(vt.lines(), "\n"); got != "  ✔ task" {
+		t.Errorf("screen after the block shrank:\n%s", got)
+	}
+	if len(removed) != 1 || removed[0] != probe.ID() {
+		t.Errorf("removal Events for %v, want [%d]", removed, probe.ID())
+	}
+}
+
+func TestKeepRemovedCounts(t *testing.T) {
+	ws := New(WithKeepRemovedCounts(true))
+	a := ws.Add("a")
+	a.Done()
+	ws.Add("b").FailWith(errors.New("broke"))
+	ws.Remove(a)
+	if n := ws.Count(Completed); n != 1 {
+		t.Errorf("Count(Completed) is %d, want 1", n)
+	}
+	if sum := ws.Summary(); sum.Completed != 1 || len(sum.Workers) != 1 {
+		t.Errorf("Summary counts %d completed of %d Workers", sum.Completed, len(sum.Workers))
+	}
+}
@@ -0,0 +1,420 @@
+package multistatus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// frameLines formats the current status of every Worker, one line each, after
+// the header if enabled. When isTerm is set the lines are colored, truncated
+// to the terminal width, and the spinner advances. The final frame follows
+// each Worker's line with its error, if it failed, and its notes.
+func (w *WorkerSet) frameLines(isTerm, final bool) []string {
+	history, lines := w.frame(isTerm, final)
+	return append(history, lines...)
+}
+
+// frame formats the current status like frameLines, but with scrolling
+// history enabled on a terminal it splits out the lines of Workers that
+// finished since the last frame, in the order they finished. Those lines are
+// printed once above the live block rather than being redrawn, along with
+// their errors and notes.
+func (w *WorkerSet) frame(isTerm, final bool) (history, lines []string) {
+	armed := w.armed()
+	var start time.Time
+	if w.stats != nil || armed {
+		start = w.now()
+	}
+	w.drew()
+	width, height := 0, 0
+	if isTerm {
+		width, height = w.Size()
+	}
+	history, lines = w.render(isTerm, final, width, height, isTerm && w.scrollHistory)
+	if w.stats != nil || armed {
+		d := span(start, w.now())
+		if w.stats != nil {
+			w.stats.laidOut(d)
+		}
+		if armed {
+			w.watch.layout, w.watch.lines = d, lines
+		}
+	}
+	return history, lines
+}
+
+// render lays out a frame for a terminal of the given size, where a width or
+// height of 0 is unlimited. With scroll, the lines of Workers that finished
+// since the last frame are split out as history.
+func (w *WorkerSet) render(isTerm, final bool, width, height int, scroll bool) (history, lines []string) {
+	snap := w.Snapshot()
+	if w.indexColumn {
+		digits := indexDigits(snap)
+		for i := range snap {
+			snap[i].indexDigits = digits
+		}
+	}
+	if w.words(isTerm) {
+		for i := range snap {
+			snap[i].Icon = ""
+		}
+	}
+	markers := w.markers(isTerm)
+	t := w.theme
+	info := w.infoMarker(isTerm)
+	backoff := w.idleMarker(t.Backoff, t.BackoffLabel, isTerm)
+	blocked := w.idleMarker(t.Blocked, t.BlockedLabel, isTerm)
+	queued := w.idleMarker(t.Queued, t.QueuedLabel, isTerm)
+	paused := w.idleMarker(t.Paused, t.PausedLabel, isTerm)
+	still := w.plainMarker(Pending, w.words(isTerm))
+	themeMarker := func(v WorkerStatus) string {
+		switch {
+		case v.Info:
+			return info
+		case v.State != Pending:
+		case v.Paused:
+			return paused
+		case !v.RetryAt.IsZero():
+			return backoff
+		case v.Blocked:
+			return blocked
+		case v.Waiting:
+			return queued
+		case v.Static:
+			return still
+		}
+		if v.State < 0 || v.State >= numStates {
+			return markers[Pending]
+		}
+		return markers[v.State]
+	}
+
+	if isTerm && !final && w.compact(height) {
+		w.frameCount++
+		return nil, []string{w.compactLine(snap, markers[Pending], width)}
+	}
+
+	lines = make([]string, 0, len(snap)+1)
+	if w.showHeader {
+		lines = append(lines, truncate(w.headerLine(snap, w.level(isTerm)), widthOrMax(width)))
+	}
+	if scroll && w.historyDone == nil {
+		w.historyDone = make(map[int64]bool)
+	}
+	var rows, finished []WorkerStatus
+	for _, i := range w.displayOrder(snap) {
+		v := snap[i]
+		if scroll && v.State.finished() && !v.Pinned {
+			if !w.historyDone[v.ID] {
+				w.historyDone[v.ID] = true
+				finished = append(finished, v)
+			}
+			continue
+		}
+		rows = append(rows, v)
+	}
+	var extra []string
+	if isTerm && !(final && w.compact(height)) {
+		rows, extra = w.fitRows(rows, w.rowBudget(height)-len(lines))
+	}
+	marker := themeMarker
+	if w.markerFunc != nil {
+		shown := append(append([]WorkerStatus(nil), rows...), finished...)
+		marker = w.customMarkers(shown, themeMarker)
+	}
+	if !isTerm || !stepsFit(rows, len(lines)+len(extra), w.rowBudget(height)) {
+		for i := range rows {
+			rows[i].stepsCollapsed = true
+		}
+	}
+	words := w.words(isTerm)
+	w.frameCount++
+	var groups []*errorGroup
+	var grouped map[int64]bool
+	if final {
+		groups, grouped = w.errorGroups(rows)
+	}
+	for _, v := range rows {
+		lines = append(lines, w.formatLine(v, marker(v), width, w.showElapsed, w.level(isTerm)))
+		lines = append(lines, w.stepLines(v, marker(v), markers, words, width, w.level(isTerm))...)
+		if final {
+			if v.State == Failed && !grouped[v.ID] {
+				lines = append(lines, w.errorLines(v, marker(v), width)...)
+			}
+			lines = append(lines, w.attemptLines(v, marker(v), width, w.level(isTerm))...)
+			lines = append(lines, w.noteLines(v, marker(v), width, w.level(isTerm))...)
+		}
+	}
+	for _, g := range groups {
+		lines = append(lines, w.errorGroupLines(g, marker(g.workers[0]), width)...)
+	}
+	if final {
+		if l := w.thresholdLine(snap); l != "" {
+			extra = append(extra, l)
+		}
+		extra = append(extra, w.overrunLines(snap)...)
+		if w.indexColumn {
+			if l := w.failedIndexLine(snap); l != "" {
+				extra = append(extra, l)
+			}
+		}
+		extra = append(extra, w.timelineLines(snap)...)
+		if l := w.statsLine(); l != "" {
+			extra = append(extra, l)
+		}
+	}
+	for _, l := range extra {
+		lines = append(lines, truncate(l, widthOrMax(width)))
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].Finished.Before(finished[j].Finished)
+	})
+	for _, v := range finished {
+		history = append(history, w.formatLine(v, marker(v), width, true, w.level(isTerm)))
+		if v.State == Failed {
+			history = append(history, w.errorLines(v, marker(v), width)...)
+		}
+		history = append(history, w.attemptLines(v, marker(v), width, w.level(isTerm))...)
+		history = append(history, w.noteLines(v, marker(v), width, w.level(isTerm))...)
+	}
+	return history, lines
+}
+
+// printAccessible announces each state change as a complete sentence until
+// all Workers have finished or the context is canceled, reporting whether it
+// was canceled.
+func (w *WorkerSet) printAccessible(ctx context.Context, done chan bool) bool {
+	m := &w.messages
+	var prev []WorkerStatus
+	finished := 0
+	announce := func() {
+		if !w.changed() {
+			return
+		}
+		snap := w.Snapshot()
+		d := DiffSnapshots(prev, snap)
+		prev = snap
+		var now []WorkerStatus
+		for _, v := range d.Added {
+			if v.State.finished() && !v.Info {
+				now = append(now, v)
+			}
+		}
+		for _, c := range d.Changed {
+			if c.Fields.Has(FieldState) && c.New.State.finished() && !c.Old.State.finished() {
+				now = append(now, c.New)
+			}
+		}
+		total := len(countable(snap))
+		for _, v := range now {
+			finished++
+			fmt.Fprintf(w.out, m.Finished+"\n", stripControl(v.Name), m.state(v.State),
+				finished, total, total-finished)
+		}
+	}
+	stopped := func() bool {
+		w.stop(done)
+		announce()
+		fmt.Fprintln(w.out, m.Canceled)
+		return true
+	}
+	if ctx.Err() != nil {
+		// canceled before Print began, so done may be closed already
+		return stopped()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return stopped()
+		case <-time.After(w.refresh):
+			announce()
+		case <-done:
+			announce()
+			counts, total := w.tally(prev)
+			fmt.Fprintln(w.out, m.pluralf(total, m.AllFinished,
+				total, counts[Completed], counts[Failed]))
+			return false
+		}
+	}
+}
+
+// Line holds the values available when formatting a single Worker's line
+type Line struct {
+	WorkerStatus
+
+	// Marker is the glyph indicating the Worker's state, which may include
+	// color escapes
+	Marker string
+
+	// Attempt is the number of the Worker's current or last attempt,
+	// counting from 1
+	Attempt int
+
+	// Progress is the fraction of the Worker's work done, from 0 to 1, or -1
+	// if it isn't known
+	Progress float64
+}
+
+// progress returns the fraction of v's work done, for Line.Progress: all of
+// it once Completed, else as set with SetProgress, the share of its steps or
+// of a Counter's total counted, or -1
+func (v WorkerStatus) progress() float64 {
+	switch {
+	case v.State == Completed:
+		return 1
+	case v.hasFraction:
+		return v.fraction
+	case len(v.Steps) > 0:
+		return stepsProgress(v)
+	case v.Counter != nil && v.Counter.Total > 0:
+		return float64(v.Counter.Done+v.Counter.Failed) / float64(v.Counter.Total)
+	}
+	return -1
+}
+
+// defaultLine formats a Line without regard to the terminal width
+func defaultLine(l Line) string {
+	if l.Status != "" {
+		return "  " + l.Marker + " " + l.Name + " " + l.Status
+	}
+	return "  " + l.Marker + " " + l.Name
+}
+
+// WithDurationFormat formats every duration drawn, such as elapsed times, the
+// header's time and retry countdowns, with fn in place of the default, which
+// keeps to three or four significant figures: "850ms", "12.3s", "4m05s" and
+// "1h12m", with whole seconds shown as "2s". Durations in JSON and the stable
+// report aren't affected.
+func WithDurationFormat(fn func(time.Duration) string) Option {
+	return func(w *WorkerSet) {
+		w.durationFormat = fn
+	}
+}
+
+// formatDuration formats d for display, as set by WithDurationFormat
+func (w *WorkerSet) formatDuration(d time.Duration) string {
+	if w.durationFormat != nil {
+		return w.durationFormat(d)
+	}
+	return humanDuration(d)
+}
+
+// humanDuration formats d with a precision suited to its magnitude
+func humanDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + humanDuration(-d)
+	}
+	switch {
+	case d < time.Second-time.Millisecond/2:
+		return fmt.Sprintf("%dms", d.Round(time.Millisecond)/time.Millisecond)
+	case d < time.Minute-50*time.Millisecond:
+		return strconv.FormatFloat(d.Round(100*time.Millisecond).Seconds(), 'f', -1, 64) + "s"
+	case d < time.Hour-time.Second/2:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%dm%02ds", d/time.Minute, d%time.Minute/time.Second)
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", d/time.Hour, d%time.Hour/time.Minute)
+}
+
+// WithLineTemplate formats each Worker's line using a text/template executed
+// against a Line, e.g. "  {{.Marker}} {{.Name}} ({{duration .Elapsed}})",
+// where the duration function formats as set by WithDurationFormat. A
+// template that fails to parse or execute causes Print to return an error.
+func WithLineTemplate(text string) Option {
+	return func(w *WorkerSet) {
+		funcs := template.FuncMap{"duration": w.formatDuration}
+		t, err := template.New("line").Funcs(funcs).Parse(text)
+		if err == nil {
+			err = t.Execute(&bytes.Buffer{}, Line{})
+		}
+		if err != nil {
+			w.setErr(fmt.Errorf("multistatus: line template: %v", err))
+			return
+		}
+		w.lineFunc = func(l Line) string {
+			var buf bytes.Buffer
+			if err := t.Execute(&buf, l); err != nil {
+				return defaultLine(l)
+			}
+			return buf.String()
+		}
+	}
+}
+
+// WithLineFunc formats each Worker's line using fn
+func WithLineFunc(fn func(Line) string) Option {
+	return func(w *WorkerSet) {
+		w.lineFunc = fn
+	}
+}
+
+// formatLine renders a single line, making sure it can't disturb the layout
+// of the block: user supplied text is stripped of control characters, line
+// breaks are flattened and, when width is positive, the result is truncated
+// to fit.
+func (w *WorkerSet) formatLine(v WorkerStatus, marker string, width int, elapsed bool, level ColorLevel) string {
+	v.Name = stripControl(v.Name)
+	v.Status = stripControl(v.Status)
+	v.Icon = stripControl(v.Icon)
+	if v.Info {
+		elapsed = false
+	}
+	if w.lineFunc == nil {
+		return truncate(w.layoutLine(v, marker, width, elapsed, level), widthOrMax(width))
+	}
+	s := w.lineFunc(Line{WorkerStatus: v, Marker: marker, Attempt: len(v.Attempts) + 1, Progress: v.progress()})
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+	if width > 0 {
+		s = truncate(s, width)
+	}
+	return s
+}
+
+// widthOrMax returns width, or an effectively unlimited width if it is 0
+func widthOrMax(width int) int {
+	if width <= 0 {
+		return int(^uint(0) >> 1)
+	}
+	return width
+}
+
+// WithSize fixes the terminal size used for layout, in place of measuring
+// the terminal
+func WithSize(width, height int) Option {
+	return func(w *WorkerSet) {
+		w.sizeFunc = func() (int, int) { return width, height }
+	}
+}
+
+// WithSizeFunc measures the terminal with fn before each frame, in place of
+// asking the terminal itself
+func WithSizeFunc(fn func() (width, height int)) Option {
+	return func(w *WorkerSet) {
+		w.sizeFunc = fn
+	}
+}
+
+// Size returns the terminal size the layout is computed for: as given by
+// WithSize or WithSizeFunc, or else as reported by the output's terminal,
+// falling back to 80x24 if it can't be determined.
+func (w *WorkerSet) Size() (width, height int) {
+	if w.sizeFunc != nil {
+		return w.sizeFunc()
+	}
+	if f, ok := w.out.(*os.File); ok {
+		width, height, err := w.terminal.Size(f)
+		if err == nil {
+			return width, height
+		}
+		w.logSizeFallback(err)
+	}
+	return 80, 24
+}
@@ -0,0 +1,174 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineTemplate(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithClock(clock), WithColorLevel(ColorNone),
+		WithLineTemplate("{{.Marker}} {{.Name}} [{{.State}}] {{duration .Elapsed}}{{if .Status}} - {{.Status}}{{end}}"))
+	fetch := ws.Add("fetch")
+	fetch.SetStatus("downloading")
+	clock.Advance(1500 * time.Millisecond)
+	fetch.Done()
+	ws.Add("build")
+	clock.Advance(2 * time.Second)
+	lines := ws.frameLines(false, true)
+	for _, want := range []string{"fetch [completed] 1.5s - downloading", "build [pending] 2s"} {
+		if !strings.Contains(strings.Join(lines, "\n"), want) {
+			t.Errorf("lines don't include %q:\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+func TestLineTemplateAttemptProgress(t *testing.T) {
+	ws := New(WithColorLevel(ColorNone),
+		WithLineTemplate(`{{.Name}} attempt {{.Attempt}}{{if ge .Progress 0.0}} {{printf "%.2f" .Progress}}{{end}}`))
+	ws.Add("first")
+	retried := ws.Add("retried")
+	retried.attempts = []Attempt{{Elapsed: time.Second, Err: "timeout"}, {Elapsed: time.Second, Err: "timeout"}}
+	c := ws.AddCounter("counted", 4)
+	c.Incr()
+	c.Fail()
+	c.Incr()
+	ws.Add("done").Done()
+	got := strings.Join(ws.frameLines(false, false), "\n")
+	for _, want := range []string{"first attempt 1", "retried attempt 3", "counted attempt 1 0.75", "done attempt 1 1.00"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("lines don't include %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "first attempt 1 ") {
+		t.Errorf("a Worker with no known progress shows some:\n%s", got)
+	}
+}
+
+func TestLineFunc(t *testing.T) {
+	ws := New(WithLineFunc(func(l Line) string { return strings.ToUpper(l.Name) }))
+	ws.Add("fetch").Done()
+	if lines := ws.frameLines(false, true); len(lines) == 0 || lines[0] != "FETCH" {
+		t.Errorf("lines are %q, want FETCH", lines)
+	}
+}
+
+func TestLineTemplateInvalid(t *testing.T) {
+	for _, text := range []string{"{{.Name", "{{.NoSuchField}}"} {
+		ws := New(WithSilent(true), WithLineTemplate(text))
+		ws.Add("task").Done()
+		if err := ws.Print(context.Background()); err == nil || !strings.Contains(err.Error(), "line template") {
+			t.Errorf("%q: Print returned %v, want a line template error", text, err)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	if w, h := New(WithOutput(&syncBuf{})).Size(); w != 80 || h != 24 {
+		t.Errorf("fallback is %dx%d, want 80x24", w, h)
+	}
+	r, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer pw.Close()
+	if w, h := New(WithOutput(pw)).Size(); w != 80 || h != 24 {
+		t.Errorf("size of a pipe is %dx%d, want 80x24", w, h)
+	}
+	if w, h := New(WithSize(120, 40)).Size(); w != 120 || h != 40 {
+		t.Errorf("WithSize gave %dx%d", w, h)
+	}
+}
+
+func TestSizeFunc(t *testing.T) {
+	width := 30
+	ws := New(WithColor(false), WithSizeFunc(func() (int, int) { return width, 24 }))
+	ws.Add(strings.Repeat("x", 50))
+	for _, width = range []int{30, 20, 60} {
+		_, lines := ws.frame(true, false)
+		want := width
+		if want > 54 {
+			want = 54
+		}
+		if got := stringWidth(lines[0]); got != want {
+			t.Errorf("at width %d the line is %d columns: %q", width, got, lines[0])
+		}
+	}
+}
+
+func TestSizeHeight(t *testing.T) {
+	height := 6
+	ws := New(WithColor(false), WithSizeFunc(func() (int, int) { return 80, height }))
+	for i := 0; i < 12; i++ {
+		ws.Add(fmt.Sprint("w", i))
+	}
+	// a line is kept free for the cursor
+	for _, height = range []int{6, 10, 40} {
+		want := height - 1
+		if want > 12 {
+			want = 12
+		}
+		if _, lines := ws.frame(true, false); len(lines) != want {
+			t.Errorf("%d lines at height %d, want %d", len(lines), height, want)
+		}
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	for _, tc := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0ms"},
+		{850 * time.Millisecond, "850ms"},
+		{999 * time.Millisecond, "999ms"},
+		{999600 * time.Microsecond, "1s"},
+		{2 * time.Second, "2s"},
+		{12345 * time.Millisecond, "12.3s"},
+		{59940 * time.Millisecond, "59.9s"},
+		{59960 * time.Millisecond, "1m00s"},
+		{4*time.Minute + 5*time.Second, "4m05s"},
+		{59*time.Minute + 59*time.Second, "59m59s"},
+		{59*time.Minute + 59600*time.Millisecond, "1h00m"},
+		{time.Hour + 12*time.Minute + 20*time.Second, "1h12m"},
+		{26 * time.Hour, "26h00m"},
+		{-1500 * time.Millisecond, "-1.5s"},
+	} {
+		if got := humanDuration(tc.d); got != tc.want {
+			t.Errorf("humanDuration(%v) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestDurationFormat(t *testing.T) {
+	clock := newFakeClock()
+	format := func(d time.Duration) string { return fmt.Sprintf("<%d>", d/time.Second) }
+	ws := New(WithClock(clock), WithColor(false), WithHeader("run"), WithElapsed(true),
+		WithDurationFormat(format))
+	ws.Add("task")
+	clock.Advance(72*time.Second + 345678*time.Microsecond)
+	lines := ws.frameLines(false, false)
+	if !strings.HasSuffix(lines[0], " (<72>)") || !strings.HasSuffix(lines[1], "task (<72>)") {
+		t.Errorf("got lines %q", lines)
+	}
+
+	// the default is used without the option, and JSON keeps the raw value
+	ws = New(WithClock(clock), WithColor(false), WithElapsed(true))
+	ws.Add("task")
+	clock.Advance(72*time.Second + 345678*time.Microsecond)
+	if got := ws.frameLines(false, false)[0]; !strings.HasSuffix(got, "task (1m12s)") {
+		t.Errorf("got %q", got)
+	}
+	b, err := ws.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf(`"elapsed":%d`, 72*time.Second+345678*time.Microsecond); !strings.Contains(string(b), want) {
+		t.Errorf("JSON %s doesn't include %s", b, want)
+	}
+}
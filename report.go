@@ -0,0 +1,65 @@
+package multistatus
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WithReportRounding includes each Worker's elapsed time in the stable
+// report, rounded to the nearest multiple of d so small variations in timing
+// don't show up as differences. Elapsed times are left out by default.
+func WithReportRounding(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.reportRounding = d
+	}
+}
+
+// WithReportRedact passes each error and note through fn before it's
+// written to the stable report, to remove details that change from run to
+// run, such as request IDs or temporary paths.
+func WithReportRedact(fn func(string) string) Option {
+	return func(w *WorkerSet) {
+		w.reportRedact = fn
+	}
+}
+
+// WriteStableReport writes a plain text report of every Worker meant to be
+// kept under version control and compared between runs: Workers are sorted
+// by name, their states are given as words, and there are no timestamps.
+// Runs with the same outcomes produce identical reports. Errors and notes
+// follow each Worker's line, indented, after any redaction set by
+// WithReportRedact.
+func (w *WorkerSet) WriteStableReport(out io.Writer) error {
+	snap := w.Snapshot()
+	sort.SliceStable(snap, func(i, j int) bool {
+		return snap[i].Name < snap[j].Name
+	})
+	redact := w.reportRedact
+	if redact == nil {
+		redact = func(s string) string { return s }
+	}
+	var buf strings.Builder
+	for _, v := range snap {
+		buf.WriteString(stripControl(v.Name) + ": " + w.messages.state(v.State))
+		if w.reportRounding > 0 && v.State.finished() {
+			buf.WriteString(" (" + v.Elapsed.Round(w.reportRounding).String() + ")")
+		}
+		buf.WriteString("\n")
+		var details []string
+		if v.Err != "" {
+			details = append(details, redact(v.Err))
+		}
+		for _, n := range v.Notes {
+			details = append(details, redact(n))
+		}
+		for _, d := range details {
+			for _, l := range strings.Split(d, "\n") {
+				buf.WriteString("    " + stripControl(l) + "\n")
+			}
+		}
+	}
+	_, err := io.WriteString(out, buf.String())
+	return err
+}
@@ -0,0 +1,53 @@
+package multistatus
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// scriptedRun finishes the same Workers in the given order, each taking
+// about as long as its name says, and returns the stable report
+func scriptedRun(t *testing.T, order []int, requestID string) []byte {
+	clock := newFakeClock()
+	reqID := regexp.MustCompile(`req-[0-9a-f]+`)
+	ws := New(WithSilent(true), WithClock(clock), WithReportRounding(time.Second),
+		WithReportRedact(func(s string) string { return reqID.ReplaceAllString(s, "req-*") }))
+	names := []string{"fetch-3s", "build-10s", "test-7s", "lint-1s", "deploy-2s"}
+	took := []time.Duration{3 * time.Second, 10 * time.Second, 7 * time.Second, time.Second, 2 * time.Second}
+	workers := make([]*Worker, len(names))
+	for i, name := range names {
+		workers[i] = ws.Add(name)
+	}
+	for _, i := range order {
+		// every Worker was added at noon, so each finishes at noon plus its
+		// time, with a few milliseconds for the rounding to hide
+		clock.Set(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(took[i] + time.Duration(i)*time.Millisecond))
+		w := workers[i]
+		switch names[i] {
+		case "test-7s":
+			w.FailWith(errors.New("2 tests failed (" + requestID + ")\nTestParse: unexpected EOF"))
+		case "lint-1s":
+			w.AddNote("3 warnings")
+			w.Done()
+		default:
+			w.Done()
+		}
+	}
+	var buf bytes.Buffer
+	if err := ws.WriteStableReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestStableReport(t *testing.T) {
+	a := scriptedRun(t, []int{3, 4, 0, 2, 1}, "req-1a2b")
+	b := scriptedRun(t, []int{1, 2, 0, 4, 3}, "req-ffe0")
+	if !bytes.Equal(a, b) {
+		t.Errorf("runs with the same outcomes differ:\n%s\n%s", a, b)
+	}
+	compareGolden(t, "stable_report.golden", a)
+}
@@ -0,0 +1,130 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// maxInlineAttempts is the number of earlier attempts listed on a Worker's
+// line
+const maxInlineAttempts = 3
+
+// An Attempt is an earlier, failed attempt of a Worker that was retried
+type Attempt struct {
+	Elapsed time.Duration `json:"elapsed"`
+	Err     string        `json:"error,omitempty"`
+}
+
+// WithRetries runs the function of a Worker started with Go or Command up to
+// n more times when it fails, unless Print has been canceled, waiting
+// between attempts as set by WithBackoff. While a Worker is retried its line
+// notes the attempt and how the last few went, and the final report lists
+// every failed attempt with its error.
+func WithRetries(n int) Option {
+	return func(w *WorkerSet) {
+		w.retries = n
+	}
+}
+
+// WithInlineAttempts controls whether a retried Worker's line notes its
+// earlier attempts, which it does by default. The final report lists them
+// either way.
+func WithInlineAttempts(b bool) Option {
+	return func(w *WorkerSet) {
+		w.hideAttempts = !b
+	}
+}
+
+// beginAttempt records that an attempt starts now, returning the time
+func (w *Worker) beginAttempt() time.Time {
+	ws := w.parent
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	w.attemptStart = ws.now()
+	w.executed = true
+	return w.attemptStart
+}
+
+// endAttempt adds the attempt that began at start to the Worker's active
+// time
+func (w *Worker) endAttempt(start time.Time) {
+	ws := w.parent
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	w.active += span(start, ws.now())
+	w.attemptStart = time.Time{}
+}
+
+// attempt calls fn once, turning a panic into an error
+func (w *Worker) attempt(fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn(w.Context())
+}
+
+// retry records a failed attempt that began at start, reporting whether the
+// Worker should try again
+func (w *Worker) retry(err error, start time.Time) bool {
+	ws := w.parent
+	ws.mu.Lock()
+	if len(w.attempts) >= ws.retries || ws.ctx.Err() != nil {
+		ws.mu.Unlock()
+		return false
+	}
+	if to, _ := w.hooked(w.State, Failed, err); to != Failed {
+		ws.mu.Unlock()
+		return false
+	}
+	now := ws.now()
+	w.attempts = append(w.attempts, Attempt{Elapsed: span(start, now), Err: err.Error()})
+	w.record(w.State, now)
+	if n := len(w.history); n > 0 {
+		w.history[n-1].Err = err.Error()
+	}
+	ws.mu.Unlock()
+	ws.touch()
+	ws.log.printf("#%d %s: attempt %d failed: %s", w.id, stripControl(w.Name), len(w.attempts), stripControl(err.Error()))
+	return true
+}
+
+// attemptCount returns the number of failed attempts so far
+func (w *Worker) attemptCount() int {
+	w.parent.mu.Lock()
+	defer w.parent.mu.Unlock()
+	return len(w.attempts)
+}
+
+// attemptsText notes a retried Worker's attempt number and its last few
+// attempts on its line, most recent first
+func (w *WorkerSet) attemptsText(v WorkerStatus) string {
+	if len(v.Attempts) == 0 || w.hideAttempts || !v.RetryAt.IsZero() {
+		return ""
+	}
+	var prev []string
+	for i := len(v.Attempts) - 1; i >= 0 && len(prev) < maxInlineAttempts; i-- {
+		prev = append(prev, w.formatDuration(v.Attempts[i].Elapsed)+" "+w.theme.Failed)
+	}
+	if len(v.Attempts) > maxInlineAttempts {
+		prev = append(prev, "…")
+	}
+	return "(" + fmt.Sprintf(w.messages.Attempt, len(v.Attempts)+1, strings.Join(prev, ", ")) + ")"
+}
+
+// attemptLines lists v's failed attempts in the final report, dimmed and
+// indented to start under the Worker's name
+func (w *WorkerSet) attemptLines(v WorkerStatus, marker string, width int, level ColorLevel) []string {
+	indent := strings.Repeat(" ", 3+stringWidth(marker))
+	lines := make([]string, 0, len(v.Attempts))
+	for i, a := range v.Attempts {
+		err := strings.SplitN(a.Err, "\n", 2)[0]
+		l := fmt.Sprintf(w.messages.FailedAttempt, i+1, w.formatDuration(a.Elapsed), stripControl(err))
+		lines = append(lines, colorize(truncate(indent+l, widthOrMax(width)), w.theme.DimColor, level))
+	}
+	return lines
+}
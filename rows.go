@@ -0,0 +1,115 @@
+package multistatus
+
+import "time"
+
+// WithMaxRows limits the number of lines the WorkerSet draws on a terminal,
+// including the header. By default the limit is one less than the height of
+// the terminal, so the block can always be redrawn in place. Workers that
+// don't fit are summarized as "… and N more", or shown in turns with
+// WithRotateVisible.
+func WithMaxRows(n int) Option {
+	return func(w *WorkerSet) {
+		w.maxRows = n
+	}
+}
+
+// WithRotateVisible pages through pending Workers when they don't all fit,
+// showing each page for interval before moving on to the next, so every
+// Worker gets screen time. Failed Workers and the header are always shown,
+// and a line notes which page is visible.
+func WithRotateVisible(interval time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.rotate = interval
+	}
+}
+
+// rotation tracks the page of Workers shown by WithRotateVisible. It is only
+// accessed while rendering.
+type rotation struct {
+	start int
+	ids   map[int64]bool
+	until time.Time
+}
+
+// rowBudget returns the number of lines available for the block on a
+// terminal of the given height, or 0 for no limit
+func (w *WorkerSet) rowBudget(height int) int {
+	if w.maxRows > 0 {
+		return w.maxRows
+	}
+	if height > 1 {
+		return height - 1
+	}
+	return 0
+}
+
+// fitRows selects the rows to draw when there are more than budget, and any
+// extra lines describing what was left out. A budget of 0 or less means
+// there's no limit.
+func (w *WorkerSet) fitRows(rows []WorkerStatus, budget int) ([]WorkerStatus, []string) {
+	if budget <= 0 || len(rows) <= budget {
+		return rows, nil
+	}
+	m := &w.messages
+	if w.rotate > 0 {
+		return w.rotateRows(rows, budget)
+	}
+	more := len(rows) - budget + 1
+	return rows[:budget-1], []string{m.pluralf(more, m.More, more)}
+}
+
+// rotateRows shows pinned and failed Workers and the current page of pending
+// Workers. The Workers on a page are chosen when the page is turned, so the
+// page stays put for the whole interval even as its Workers finish.
+func (w *WorkerSet) rotateRows(rows []WorkerStatus, budget int) ([]WorkerStatus, []string) {
+	var pinned, pending []WorkerStatus
+	for _, v := range rows {
+		switch {
+		case v.Pinned, v.State == Failed:
+			pinned = append(pinned, v)
+		case v.State == Pending, v.State == Stopping:
+			pending = append(pending, v)
+		}
+	}
+	keep := budget - 2
+	if keep < 0 {
+		keep = 0
+	}
+	if len(pinned) > keep {
+		pinned = pinned[:keep]
+	}
+	size := budget - len(pinned) - 1
+	if size < 1 {
+		size = 1
+	}
+
+	r := &w.rotation
+	now := time.Now()
+	if r.ids == nil || !now.Before(r.until) {
+		if r.ids != nil {
+			r.start += len(r.ids)
+		}
+		if r.start >= len(pending) {
+			r.start = 0
+		}
+		end := r.start + size
+		if end > len(pending) {
+			end = len(pending)
+		}
+		r.ids = make(map[int64]bool, end-r.start)
+		for _, v := range pending[r.start:end] {
+			r.ids[v.ID] = true
+		}
+		r.until = now.Add(w.rotate)
+	}
+
+	visible := pinned
+	for _, v := range pending {
+		if r.ids[v.ID] {
+			visible = append(visible, v)
+		}
+	}
+	m := &w.messages
+	page := m.pluralf(len(pending), m.Page, r.start+1, r.start+len(r.ids), len(pending))
+	return visible, []string{page}
+}
@@ -0,0 +1,59 @@
+package multistatus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rowNames returns the names on lines drawn for Workers, and the other lines
+// unchanged, dropping the markers whose spinners move from frame to frame
+func rowNames(lines []string) string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = strings.TrimSpace(l)
+		if f := strings.Fields(l); len(f) == 2 {
+			out[i] = f[1]
+		}
+	}
+	return strings.Join(out, " | ")
+}
+
+func TestRotateVisible(t *testing.T) {
+	ws := New(WithMaxRows(6), WithRotateVisible(100*time.Millisecond), WithColor(false))
+	for i := 0; i < 12; i++ {
+		w := ws.Add(fmt.Sprint("w", i))
+		if i == 3 {
+			w.Fail()
+		}
+	}
+	// the page turns at the first frame drawn after the interval
+	for _, want := range []string{
+		"w3 | w0 | w1 | w2 | w4 | showing 1–4 of 11 running",
+		"w3 | w5 | w6 | w7 | w8 | showing 5–8 of 11 running",
+		"w3 | w9 | w10 | w11 | showing 9–11 of 11 running",
+		"w3 | w0 | w1 | w2 | w4 | showing 1–4 of 11 running",
+	} {
+		_, lines := ws.frame(true, false)
+		if got := rowNames(lines); got != want {
+			t.Errorf("got  %s\nwant %s", got, want)
+		}
+		_, lines = ws.frame(true, false)
+		if got := rowNames(lines); got != want {
+			t.Errorf("page turned early: %s", got)
+		}
+		time.Sleep(120 * time.Millisecond)
+	}
+}
+
+func TestMaxRowsMore(t *testing.T) {
+	ws := New(WithMaxRows(6))
+	for i := 0; i < 12; i++ {
+		ws.Add(fmt.Sprint("w", i))
+	}
+	_, lines := ws.frame(true, false)
+	if got, want := rowNames(lines), "w0 | w1 | w2 | w3 | w4 | … and 7 more"; got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+package multistatus
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// WithConcurrency limits the number of Workers started with Go or Command
+// that run at the same time. Workers beyond the limit wait, still Pending,
+// until a running Worker finishes, highest priority first. A limit of 0, the
+// default, runs every Worker immediately.
+func WithConcurrency(n int) Option {
+	return func(w *WorkerSet) {
+		w.concurrency = n
+	}
+}
+
+// WithStartRate limits how often Workers started with Go or Command are
+// started to n per the given duration, spacing their starts evenly, for
+// example to stay under a server's rate limit. Workers waiting for their
+// turn stay Pending and are shown as waiting, and the header, if shown,
+// notes the rate. It may be combined with WithConcurrency. A rate of 0, the
+// default, doesn't limit starts.
+func WithStartRate(n int, per time.Duration) Option {
+	return func(w *WorkerSet) {
+		if n <= 0 || per <= 0 {
+			w.startInterval, w.startRate = 0, ""
+			return
+		}
+		w.startInterval = per / time.Duration(n)
+		w.startRate = formatRate(n, per)
+	}
+}
+
+// formatRate formats n per duration for display, such as "10/s"
+func formatRate(n int, per time.Duration) string {
+	switch per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", n)
+	case time.Minute:
+		return fmt.Sprintf("%d/min", n)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", n)
+	}
+	return fmt.Sprintf("%d/%s", n, per)
+}
+
+// SortOrder is the order in which Workers are displayed
+type SortOrder int
+
+// Available SortOrders
+const (
+	// SortNone displays Workers in the order they were added
+	SortNone SortOrder = iota
+	// SortByPriority displays higher priority Workers first, otherwise in
+	// the order they were added
+	SortByPriority
+)
+
+// WithSort sets the order in which Workers are displayed
+func WithSort(order SortOrder) Option {
+	return func(w *WorkerSet) {
+		w.sortOrder = order
+	}
+}
+
+// SetPriority sets the Worker's priority, 0 by default. Waiting Workers with
+// a higher priority are started first when the WorkerSet has a concurrency
+// limit, and are displayed first with SortByPriority. Once the Worker has
+// started the priority only affects display.
+func (w *Worker) SetPriority(n int) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.priority = n
+	if w.queueIndex >= 0 {
+		heap.Fix(&ws.queue, w.queueIndex)
+	}
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// schedule runs fn for the Worker, immediately or once the concurrency limit
+// allows
+func (w *WorkerSet) schedule(worker *Worker, fn func(context.Context) error) {
+	w.mu.Lock()
+	w.enqueue(worker, fn)
+	w.mu.Unlock()
+	w.dispatch()
+}
+
+// enqueue queues worker to run fn. The caller must hold the lock.
+func (w *WorkerSet) enqueue(worker *Worker, fn func(context.Context) error) {
+	if worker.State.finished() {
+		// canceled as it was added, by Close or Drain
+		return
+	}
+	worker.fn = fn
+	heap.Push(&w.queue, worker)
+}
+
+// dispatch starts as many queued Workers as the concurrency limit and start
+// rate allow, unless the WorkerSet is a Pipeline stage that hasn't started
+// yet. Once Print is canceled, queued Workers fail without being started.
+func (w *WorkerSet) dispatch() {
+	var start, cancel []*Worker
+	w.mu.Lock()
+	for w.queue.Len() > 0 {
+		if w.ctx.Err() != nil {
+			cancel = append(cancel, heap.Pop(&w.queue).(*Worker))
+			w.settling++
+			continue
+		}
+		if w.held || w.concurrency > 0 && w.running >= w.concurrency {
+			break
+		}
+		if w.startInterval > 0 {
+			now := w.now()
+			if now.Before(w.nextStart) {
+				if !w.startWaiting && !w.closed {
+					w.startWaiting = true
+					w.bg.Add(1)
+					go w.dispatchAfter(w.nextStart.Sub(now))
+				}
+				break
+			}
+			w.nextStart = now.Add(w.startInterval)
+		}
+		w.running++
+		worker := heap.Pop(&w.queue).(*Worker)
+		worker.executing = true
+		start = append(start, worker)
+	}
+	w.mu.Unlock()
+
+	for _, worker := range cancel {
+		worker.FailWith(worker.finalizeIdle(w.ctx.Err()))
+	}
+	if len(cancel) > 0 {
+		w.mu.Lock()
+		w.settling -= len(cancel)
+		w.mu.Unlock()
+	}
+	if len(start) > 0 {
+		w.touch()
+	}
+	for _, worker := range start {
+		go func(worker *Worker) {
+			worker.run(worker.fn)
+			w.mu.Lock()
+			w.running--
+			w.mu.Unlock()
+			w.dispatch()
+		}(worker)
+	}
+}
+
+// dispatchAfter dispatches queued Workers once d has passed, unless the
+// WorkerSet is closed first
+func (w *WorkerSet) dispatchAfter(d time.Duration) {
+	defer w.bg.Done()
+	select {
+	case <-w.after(d):
+	case <-w.closing:
+	}
+	w.mu.Lock()
+	w.startWaiting = false
+	w.mu.Unlock()
+	w.dispatch()
+}
+
+// workerQueue is a priority queue of Workers waiting to start, ordered by
+// priority and then by the order they were added
+type workerQueue []*Worker
+
+func (q workerQueue) Len() int { return len(q) }
+
+func (q workerQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].id < q[j].id
+}
+
+func (q workerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].queueIndex = i
+	q[j].queueIndex = j
+}
+
+func (q *workerQueue) Push(x interface{}) {
+	w := x.(*Worker)
+	w.queueIndex = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *workerQueue) Pop() interface{} {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	w.queueIndex = -1
+	*q = old[:len(old)-1]
+	return w
+}
+
+// displayOrder returns the indexes of snap in the order they should be
+// displayed: pinned Workers in the order they were pinned, then the rest in
+// the configured sort order
+func (w *WorkerSet) displayOrder(snap []WorkerStatus) []int {
+	order := make([]int, len(snap))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := snap[order[i]], snap[order[j]]
+		if a.Pinned || b.Pinned {
+			return a.Pinned && (!b.Pinned || a.pin < b.pin)
+		}
+		if w.sortOrder == SortByPriority {
+			return a.Priority > b.Priority
+		}
+		return false
+	})
+	return order
+}
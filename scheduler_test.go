@@ -0,0 +1,201 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorityStartOrder(t *testing.T) {
+	ws := New(WithSilent(true), WithConcurrency(1))
+	var mu sync.Mutex
+	var order []string
+	block := make(chan struct{})
+	ws.Go("blocker", func(context.Context) error { <-block; return nil })
+	for i, p := range []int{3, 1, 5, 2, 5, 0} {
+		name := fmt.Sprintf("%c%d", 'a'+i, p)
+		w := ws.Go(name, func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		})
+		// queued behind the blocker, so the priority still counts
+		w.SetPriority(p)
+	}
+	close(block)
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(order, " "), "c5 e5 a3 d2 b1 f0"; got != want {
+		t.Errorf("started in order %s, want %s", got, want)
+	}
+}
+
+func TestPriorityAfterStart(t *testing.T) {
+	ws := New(WithSilent(true), WithConcurrency(1))
+	var mu sync.Mutex
+	var order []string
+	run := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	first := ws.Go("first", func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	ws.Go("second", run("second")).SetPriority(1)
+	<-started
+	first.SetPriority(9)
+	close(release)
+	ws.Print(context.Background())
+	if len(order) != 1 || order[0] != "second" {
+		t.Errorf("ran %v after first", order)
+	}
+	if v := ws.Snapshot()[0]; v.Priority != 9 {
+		t.Errorf("first has priority %d, want 9 for display", v.Priority)
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	ws := New(WithSort(SortByPriority))
+	for i, p := range []int{0, 2, 1, 2, 0} {
+		ws.Add(fmt.Sprintf("%c%d", 'a'+i, p)).SetPriority(p)
+	}
+	for i := 0; i < 3; i++ {
+		var names []string
+		for _, j := range ws.displayOrder(ws.Snapshot()) {
+			names = append(names, ws.Snapshot()[j].Name)
+		}
+		if got, want := strings.Join(names, " "), "b2 d2 c1 a0 e0"; got != want {
+			t.Errorf("displayed in order %s, want %s", got, want)
+		}
+	}
+}
+
+// startTimes runs n Workers on ws, each noting the time it started by clock
+func startTimes(ws *WorkerSet, clock Clock, n int) (func() []time.Duration, []*Worker) {
+	var mu sync.Mutex
+	var starts []time.Duration
+	t0 := clock.Now()
+	workers := make([]*Worker, n)
+	for i := range workers {
+		workers[i] = ws.Go(fmt.Sprint("w", i), func(context.Context) error {
+			mu.Lock()
+			starts = append(starts, clock.Now().Sub(t0))
+			mu.Unlock()
+			return nil
+		})
+	}
+	return func() []time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Duration(nil), starts...)
+	}, workers
+}
+
+func TestStartRate(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithStartRate(10, time.Second))
+	starts, workers := startTimes(ws, clock, 4)
+	if !waitFor(func() bool { return len(starts()) == 1 && clock.Waiters() == 1 }) {
+		t.Fatalf("started %v", starts())
+	}
+	if v := ws.Snapshot()[1]; !v.Waiting {
+		t.Errorf("queued Worker isn't shown waiting")
+	}
+	for i := 2; i <= 4; i++ {
+		clock.Advance(50 * time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+		if n := len(starts()); n != i-1 {
+			t.Fatalf("%d started after %v", n, 50*time.Millisecond)
+		}
+		clock.Advance(50 * time.Millisecond)
+		if !waitFor(func() bool { return len(starts()) == i }) {
+			t.Fatalf("started %v", starts())
+		}
+	}
+	want := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	if got := starts(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("started at %v, want %v", got, want)
+	}
+	for _, w := range workers {
+		w.Wait(context.Background())
+	}
+	// nothing is left to dispatch, so Print returns straight away
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartRateCanceledInQueue(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithSilent(true), WithClock(clock), WithStartRate(1, time.Second))
+	starts, workers := startTimes(ws, clock, 3)
+	waitFor(func() bool { return len(starts()) == 1 && clock.Waiters() == 1 })
+	// failed while queued, the second Worker gives its turn to the third
+	workers[1].FailWith(context.Canceled)
+	clock.Advance(time.Second)
+	if !waitFor(func() bool { return len(starts()) == 2 }) {
+		t.Fatalf("started %v", starts())
+	}
+	if got := starts()[1]; got != time.Second {
+		t.Errorf("third Worker started at %v, want 1s", got)
+	}
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := ws.Count(Completed); n != 2 {
+		t.Errorf("%d completed, want 2", n)
+	}
+}
+
+func TestStartRateCanceledPrint(t *testing.T) {
+	ws := New(WithSilent(true), WithStartRate(1, time.Hour))
+	for i := 0; i < 3; i++ {
+		ws.Go("w", func(context.Context) error { return nil })
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	ws.Print(ctx)
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Print took %v", d)
+	}
+	if ws.Count(Completed) != 1 || ws.Count(Pending) != 0 {
+		t.Errorf("%d completed, %d pending", ws.Count(Completed), ws.Count(Pending))
+	}
+	if err := ws.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartRateHeader(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		per  time.Duration
+		want string
+	}{
+		{10, time.Second, "10/s"},
+		{30, time.Minute, "30/min"},
+		{5, time.Hour, "5/h"},
+		{3, 10 * time.Second, "3/10s"},
+	} {
+		ws := New(WithStartRate(tc.n, tc.per), WithHeader("deploy"), WithColor(false))
+		ws.Add("w")
+		_, lines := ws.frame(true, false)
+		if want := "· start rate: " + tc.want; !strings.HasSuffix(lines[0], want) {
+			t.Errorf("header %q doesn't end %q", lines[0], want)
+		}
+	}
+}
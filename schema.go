@@ -0,0 +1,33 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion names the layout of the JSON documents the package writes,
+// a Summary and the snapshot of MarshalJSON, which record it in their schema
+// field. It changes whenever a field is renamed, removed or changes meaning;
+// fields may be added without changing it.
+const SchemaVersion = "multistatus/1"
+
+// ErrSchema is returned, along with the schema found, by DecodeSummary for a
+// document written with a different SchemaVersion, or without one
+var ErrSchema = errors.New("multistatus: unsupported schema")
+
+// DecodeSummary reads a Summary encoded as JSON from r, such as one saved by
+// an earlier run for CompareSummaries, or the snapshot written by MarshalJSON,
+// WithStatusFile or Handler. It returns an error wrapping ErrSchema unless the
+// document has the current SchemaVersion.
+func DecodeSummary(r io.Reader) (Summary, error) {
+	var s Summary
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return Summary{}, fmt.Errorf("multistatus: decoding summary: %w", err)
+	}
+	if s.Schema != SchemaVersion {
+		return Summary{}, fmt.Errorf("%w %q", ErrSchema, s.Schema)
+	}
+	return s, nil
+}
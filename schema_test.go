@@ -0,0 +1,183 @@
+package multistatus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenSummary returns a Summary with every field set, as written for the
+// golden files of the current SchemaVersion
+func goldenSummary() Summary {
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	return Summary{
+		Schema: SchemaVersion,
+		Workers: []WorkerStatus{
+			{
+				ID:            0,
+				Name:          "fetch (2)",
+				RequestedName: "fetch",
+				State:         Failed,
+				Status:        "downloading",
+				Err:           "connection reset",
+				Elapsed:       3 * time.Second,
+				Active:        2 * time.Second,
+				Icon:          "⇣",
+				Static:        true,
+				HideElapsed:   true,
+				Index:         1,
+				Expected:      5 * time.Second,
+				Priority:      2,
+				Group:         "network",
+				Pinned:        true,
+				Info:          true,
+				Waiting:       true,
+				Blocked:       true,
+				Paused:        true,
+				Attempts:      []Attempt{{Elapsed: time.Second, Err: "timeout"}},
+				RetryAt:       at(2 * time.Second),
+				Counter:       &CounterStatus{Done: 7, Failed: 1, Total: 10, Rates: []int64{3, 4}},
+				Steps:         []Step{{Name: "resolve", State: Completed}, {Name: "get", State: Failed, Err: "reset"}},
+				Finalizing:    true,
+				Notes:         []string{"cache miss"},
+				NotesDropped:  1,
+				History: []TransitionRecord{
+					{From: Pending, To: Stopping, Err: "canceled", Status: "stopping", Time: at(time.Second)},
+				},
+				HistoryDropped: 2,
+				Started:        start,
+				Finished:       at(3 * time.Second),
+			},
+		},
+		Completed:       4,
+		Failed:          1,
+		Canceled:        1,
+		Pending:         2,
+		TotalDuration:   9 * time.Second,
+		ActiveDuration:  7 * time.Second,
+		Duration:        4 * time.Second,
+		PausedDuration:  time.Second,
+		OK:              true,
+		FirstError:      "connection reset",
+		FirstFailed:     0,
+		DroppedEvents:   3,
+		Misuse:          ErrForeignWorker.Error(),
+		FailureTimeline: &FailureTimeline{Start: start, BucketWidth: time.Second, Buckets: []int{0, 1}},
+		Groups:          map[string]GroupSummary{"network": {Completed: 1, Failed: 1, Canceled: 1, Pending: 1}},
+	}
+}
+
+// checkGolden compares v, encoded as indented JSON, with the named file in
+// testdata, rewriting it instead with -update
+func checkGolden(t *testing.T, name string, v interface{}) []byte {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s has drifted from %s; if the change is intended, bump SchemaVersion when fields were renamed or removed, then rerun with -update:\n%s", name, SchemaVersion, got)
+	}
+	return want
+}
+
+func TestSchemaGolden(t *testing.T) {
+	sum := goldenSummary()
+	for _, tc := range []struct {
+		file string
+		v    interface{}
+	}{
+		{"summary_v1.json", sum},
+		{"snapshot_v1.json", setJSON{Name: "deploy", Labels: map[string]string{"env": "staging"}, Summary: sum, Finished: true}},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			golden := checkGolden(t, tc.file, tc.v)
+			got, err := DecodeSummary(bytes.NewReader(golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, sum) {
+				t.Errorf("decoded %s as\n%+v\nwant\n%+v", tc.file, got, sum)
+			}
+		})
+	}
+}
+
+// TestSchemaGoldenComplete keeps goldenSummary setting every field, so a
+// field added to Summary or WorkerStatus lands in the golden files
+func TestSchemaGoldenComplete(t *testing.T) {
+	// check walks the fields of the structs in vs, requiring each to be set
+	// in at least one of them
+	var check func(path string, vs []reflect.Value)
+	check = func(path string, vs []reflect.Value) {
+		typ := vs[0].Type()
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if !f.IsExported() || f.Name == "FirstFailed" || f.Name == "ID" {
+				continue
+			}
+			var set []reflect.Value
+			for _, v := range vs {
+				fv := v.Field(i)
+				if fv.IsZero() {
+					continue
+				}
+				switch fv.Kind() {
+				case reflect.Ptr:
+					set = append(set, fv.Elem())
+				case reflect.Slice:
+					for j := 0; j < fv.Len(); j++ {
+						set = append(set, fv.Index(j))
+					}
+				default:
+					set = append(set, fv)
+				}
+			}
+			if len(set) == 0 {
+				t.Errorf("goldenSummary leaves %s.%s unset", path, f.Name)
+				continue
+			}
+			if set[0].Kind() == reflect.Struct && set[0].Type() != reflect.TypeOf(time.Time{}) {
+				check(path+"."+f.Name, set)
+			}
+		}
+	}
+	check("Summary", []reflect.Value{reflect.ValueOf(goldenSummary())})
+}
+
+func TestDecodeSummarySchema(t *testing.T) {
+	for _, tc := range []struct {
+		doc    string
+		schema bool
+	}{
+		{`{"workers":[]}`, true},
+		{`{"schema":"multistatus/0","workers":[]}`, true},
+		{`{`, false},
+	} {
+		_, err := DecodeSummary(strings.NewReader(tc.doc))
+		if err == nil || errors.Is(err, ErrSchema) != tc.schema {
+			t.Errorf("decoding %s returned %v, want ErrSchema %v", tc.doc, err, tc.schema)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package multistatus
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// ScreenshotFormat selects how Screenshot encodes the colors of a frame
+type ScreenshotFormat int
+
+// Available ScreenshotFormats
+const (
+	// ScreenshotANSI keeps the color escapes a terminal would be sent
+	ScreenshotANSI ScreenshotFormat = iota
+	// ScreenshotHTML draws the colors with styled spans inside a pre
+	// element
+	ScreenshotHTML
+)
+
+// Screenshot returns the frame a terminal width columns wide would show now,
+// colored as it would be on the terminal, for generating documentation. A
+// width of 0 is unlimited. Every Worker is drawn, however many there are, and
+// the terminal itself is never touched. Screenshot is meant for a WorkerSet
+// that isn't being printed, such as one whose Workers are set up by hand or
+// one drawn by a program's own loop, as the spinner advances with each call.
+func (w *WorkerSet) Screenshot(width int, format ScreenshotFormat) (string, error) {
+	w.mu.Lock()
+	err := w.err
+	w.mu.Unlock()
+	if err == nil {
+		err = w.validate()
+	}
+	switch {
+	case err != nil:
+	case width < 0:
+		err = fmt.Errorf("multistatus: screenshot width %d is negative", width)
+	case format != ScreenshotANSI && format != ScreenshotHTML:
+		err = fmt.Errorf("multistatus: unknown screenshot format %d", format)
+	}
+	if err != nil {
+		return "", err
+	}
+	if w.theme.Spinner != "" {
+		w.spinner.Set(w.theme.Spinner)
+	}
+	w.drew()
+	history, lines := w.render(true, false, width, 0, false)
+	lines = append(history, lines...)
+	if format == ScreenshotANSI {
+		return strings.Join(lines, "\n") + "\n", nil
+	}
+	var buf strings.Builder
+	buf.WriteString(`<pre class="multistatus">`)
+	for _, l := range lines {
+		buf.WriteString(sgrToHTML(l))
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</pre>\n")
+	return buf.String(), nil
+}
+
+// sgrToHTML converts a line drawn with the package's own colors to HTML,
+// turning each colored run into a span. Escapes other than colors are
+// dropped.
+func sgrToHTML(s string) string {
+	var buf strings.Builder
+	open := false
+	for i := 0; i < len(s); {
+		l := escapeLen(s[i:])
+		if l == 0 {
+			j := i + 1
+			for j < len(s) && escapeLen(s[j:]) == 0 {
+				j++
+			}
+			buf.WriteString(html.EscapeString(s[i:j]))
+			i = j
+			continue
+		}
+		seq := s[i : i+l]
+		i += l
+		if !strings.HasSuffix(seq, "m") {
+			continue
+		}
+		if open {
+			buf.WriteString("</span>")
+			open = false
+		}
+		if style := sgrStyle(seq[len(csi) : len(seq)-1]); style != "" {
+			buf.WriteString(`<span style="` + style + `">`)
+			open = true
+		}
+	}
+	if open {
+		buf.WriteString("</span>")
+	}
+	return buf.String()
+}
+
+// sgrStyle returns the CSS for the SGR parameters written by Color.sgr, or an
+// empty string for a reset
+func sgrStyle(params string) string {
+	p := strings.Split(params, ";")
+	var style []string
+	for i := 0; i < len(p); i++ {
+		n, _ := strconv.Atoi(p[i])
+		var c Color
+		switch {
+		case n == 1:
+			style = append(style, "font-weight:bold")
+			continue
+		case n == 2:
+			style = append(style, "opacity:0.6")
+			continue
+		case n >= 30 && n <= 37:
+			c = Palette(uint8(n - 30))
+		case n >= 90 && n <= 97:
+			c = Palette(uint8(n - 90 + 8))
+		case n == 38 && i+2 < len(p) && p[i+1] == "5":
+			v, _ := strconv.Atoi(p[i+2])
+			c = Palette(uint8(v))
+			i += 2
+		case n == 38 && i+4 < len(p) && p[i+1] == "2":
+			var v [3]int
+			for j := range v {
+				v[j], _ = strconv.Atoi(p[i+2+j])
+			}
+			c = RGB(uint8(v[0]), uint8(v[1]), uint8(v[2]))
+			i += 4
+		default:
+			continue
+		}
+		r, g, b := c.rgb()
+		style = append(style, fmt.Sprintf("color:#%02x%02x%02x", r, g, b))
+	}
+	return strings.Join(style, ";")
+}
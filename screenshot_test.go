@@ -0,0 +1,72 @@
+package multistatus
+
+import (
+	"strings"
+	"testing"
+)
+
+// themedSet returns a WorkerSet drawn in true color with a theme of its own
+func themedSet() *WorkerSet {
+	theme := DefaultTheme
+	theme.CompletedColor = RGB(0, 200, 120)
+	theme.FailedColor = Palette(196).Bold()
+	ws := New(WithTheme(theme), WithColorLevel(ColorTrue), WithHeader("release <v2>"))
+	goldenWorkers(ws)
+	ws.Add("publish").SetStatus("uploading")
+	return ws
+}
+
+func TestScreenshotHTML(t *testing.T) {
+	shot, err := themedSet().Screenshot(80, ScreenshotHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(shot, esc) || strings.Contains(shot, "<v2>") {
+		t.Errorf("escape or unescaped text left in the HTML:\n%s", shot)
+	}
+	compareGolden(t, "screenshot.html.golden", []byte(shot))
+}
+
+func TestScreenshotANSI(t *testing.T) {
+	shot, err := themedSet().Screenshot(80, ScreenshotANSI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "screenshot.ansi.golden", []byte(shot))
+
+	// narrower, the lines are cut to fit
+	shot, _ = themedSet().Screenshot(12, ScreenshotANSI)
+	for _, l := range strings.Split(strings.TrimSuffix(shot, "\n"), "\n") {
+		if n := stringWidth(l); n > 12 {
+			t.Errorf("line %q is %d columns", l, n)
+		}
+	}
+}
+
+func TestScreenshotErrors(t *testing.T) {
+	ws := New()
+	if _, err := ws.Screenshot(-1, ScreenshotANSI); err == nil {
+		t.Error("no error for a negative width")
+	}
+	if _, err := ws.Screenshot(80, ScreenshotFormat(9)); err == nil {
+		t.Error("no error for an unknown format")
+	}
+}
+
+func TestSGRStyle(t *testing.T) {
+	for _, tc := range []struct{ params, want string }{
+		{"0", ""},
+		{"32", "color:#00cd00"},
+		{"1;91", "font-weight:bold;color:#ff0000"},
+		{"2", "opacity:0.6"},
+		{"38;5;208", "color:#ff8700"},
+		{"38;2;1;2;3", "color:#010203"},
+	} {
+		if got := sgrStyle(tc.params); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.params, got, tc.want)
+		}
+	}
+	if got, want := sgrToHTML("a "+csi+"32mok"+sgrReset+csi+"K & <b>"), `a <span style="color:#00cd00">ok</span> &amp; &lt;b&gt;`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSilentWritesNothing(t *testing.T) {
+	out := &syncBuf{}
+	ctx, cancel := context.WithCancel(context.Background())
+	var events atomic.Int32
+	ws := New(WithSilent(true), WithOutput(out), WithTTY(true), WithHeader("run"),
+		WithElapsed(true), WithRefreshInterval(1))
+	ws.Subscribe(func(Event) { events.Add(1) })
+	done := ws.Go("done", func(context.Context) error { return nil })
+	failed := ws.Go("failed", func(context.Context) error { return errors.New("broke") })
+	ws.Go("stuck", func(ctx context.Context) error {
+		done.Wait(ctx)
+		failed.Wait(ctx)
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := ws.Print(ctx); !errors.Is(err, ErrCanceled) {
+		t.Errorf("got %v, want %v", err, ErrCanceled)
+	}
+	if out.String() != "" {
+		t.Errorf("wrote %q", out.String())
+	}
+	if sum := ws.Summary(); sum.Completed != 1 || sum.Failed != 1 || len(sum.Workers) != 3 {
+		t.Errorf("got %+v", sum)
+	}
+	if events.Load() == 0 {
+		t.Error("no events published")
+	}
+	if _, err := ws.MarshalJSON(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSilentStdout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	ws, finish := Begin(context.Background(), WithSilent(true), WithOutput(nil))
+	ws.Add("task").Done()
+	if _, err := finish(); err != nil {
+		t.Error(err)
+	}
+	os.Stdout = stdout
+	w.Close()
+	if b, _ := io.ReadAll(r); len(b) != 0 {
+		t.Errorf("wrote %q to stdout", b)
+	}
+}
@@ -0,0 +1,67 @@
+package multistatus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithSlowFrameThreshold sets how long laying out and writing a frame may
+// take before the display is taken to be struggling, such as with names
+// thousands of characters long. The first frame taking longer is noted in the
+// run log, naming the slower of the two phases and the frame's longest line,
+// and from then on only the lines that changed are redrawn, as they are on a
+// slow terminal. Frames are only timed with a threshold given, or with
+// WithRenderStats; 0, the default, turns the check off.
+func WithSlowFrameThreshold(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.slowFrame = d
+	}
+}
+
+// frameWatch times frames until one is slow. It is accessed only while
+// rendering, apart from degraded, which is set atomically.
+type frameWatch struct {
+	// layout is the time taken to lay out the last frame, and lines the
+	// frame itself
+	layout time.Duration
+	lines  []string
+
+	// degraded is set once a frame was slow
+	degraded int32
+}
+
+// armed reports whether frames are still being watched for being slow
+func (w *WorkerSet) armed() bool {
+	return w.slowFrame > 0 && atomic.LoadInt32(&w.watch.degraded) == 0
+}
+
+// checkFrame notes in the run log, once, that the last frame, written in
+// write, took longer than the threshold, and degrades the display
+func (w *WorkerSet) checkFrame(write time.Duration) {
+	if !w.armed() {
+		return
+	}
+	f := &w.watch
+	if f.layout+write <= w.slowFrame {
+		return
+	}
+	atomic.StoreInt32(&f.degraded, 1)
+	phase := "layout"
+	if write > f.layout {
+		phase = "write"
+	}
+	longest := ""
+	for _, l := range f.lines {
+		if len(l) > len(longest) {
+			longest = l
+		}
+	}
+	w.log.printf("slow frame: %v, mostly %s (layout %v, write %v); longest line %d bytes, %q; redrawing only changed lines from now on",
+		f.layout+write, phase, f.layout, write, len(longest), truncate(stripControl(longest), 40))
+	f.lines = nil
+}
+
+// degraded reports whether a frame was slow
+func (w *WorkerSet) degraded() bool {
+	return atomic.LoadInt32(&w.watch.degraded) == 1
+}
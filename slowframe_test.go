@@ -0,0 +1,61 @@
+package multistatus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lagWriter delays every write, like a slow terminal
+type lagWriter struct {
+	buf   syncBuf
+	delay time.Duration
+}
+
+func (l *lagWriter) Write(p []byte) (int, error) {
+	time.Sleep(l.delay)
+	return l.buf.Write(p)
+}
+
+func TestSlowFrame(t *testing.T) {
+	var log syncBuf
+	out := &lagWriter{delay: 30 * time.Millisecond}
+	ws := New(WithOutput(out), WithTTY(true), WithSharedDisplay(false), WithSize(80, 20),
+		WithSlowFrameThreshold(20*time.Millisecond), WithLogWriter(&log),
+		WithRefreshInterval(10*time.Millisecond))
+	a := ws.Add(strings.Repeat("n", 10000))
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		a.Done()
+	}()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	l := log.String()
+	if n := strings.Count(l, "slow frame"); n != 1 {
+		t.Fatalf("noted %d slow frames, want 1:\n%s", n, l)
+	}
+	if !strings.Contains(l, "mostly write") || !strings.Contains(l, "longest line") {
+		t.Errorf("slow frame record lacks its cause:\n%s", l)
+	}
+	if !ws.degraded() {
+		t.Error("display wasn't degraded after a slow frame")
+	}
+}
+
+func TestSlowFrameOffByDefault(t *testing.T) {
+	ws := New()
+	ws.Add("build")
+	if ws.armed() {
+		t.Fatal("frames are watched without a threshold")
+	}
+	ws.frame(true, false)
+	if ws.watch.layout != 0 || ws.watch.lines != nil {
+		t.Error("frame was timed without a threshold or stats")
+	}
+	ws.checkFrame(time.Hour)
+	if ws.degraded() {
+		t.Error("degraded without a threshold")
+	}
+}
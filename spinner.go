@@ -0,0 +1,31 @@
+package multistatus
+
+// defaultSpinner holds the frames of DefaultTheme's Spinner
+const defaultSpinner = "⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏"
+
+// A spinner cycles through the frames of an animation
+type spinner struct {
+	frames []rune
+	pos    int
+}
+
+func newSpinner() *spinner {
+	s := &spinner{}
+	s.Set(defaultSpinner)
+	return s
+}
+
+// Set replaces the frames of the animation
+func (s *spinner) Set(frames string) {
+	s.frames = []rune(frames)
+}
+
+// Next returns the next frame of the animation
+func (s *spinner) Next() string {
+	if len(s.frames) == 0 {
+		return ""
+	}
+	r := s.frames[s.pos%len(s.frames)]
+	s.pos++
+	return string(r)
+}
@@ -0,0 +1,60 @@
+package multistatus
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSpinner(t *testing.T) {
+	s := newSpinner()
+	var got []string
+	for i := 0; i < 11; i++ {
+		got = append(got, s.Next())
+	}
+	if want := strings.Split(defaultSpinner+"⠋", ""); strings.Join(got, "") != strings.Join(want, "") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	s.Set("ab")
+	if got := s.Next() + s.Next(); got != "ba" && got != "ab" {
+		t.Errorf("got %q cycling two frames", got)
+	}
+	s.Set("")
+	if got := s.Next(); got != "" {
+		t.Errorf("got %q without frames", got)
+	}
+}
+
+// TestImports keeps the package's dependencies to the standard library, and
+// the terminal package's to golang.org/x/term
+func TestImports(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := filepath.Glob("terminal/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, name := range append(files, sub...) {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			first, _, _ := strings.Cut(path, "/")
+			allowed := filepath.Dir(name) == "terminal" && path == "golang.org/x/term"
+			if strings.Contains(first, ".") && !allowed {
+				t.Errorf("%s imports %s", name, path)
+			}
+		}
+	}
+}
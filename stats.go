@@ -0,0 +1,110 @@
+package multistatus
+
+import (
+	"sync"
+	"time"
+)
+
+// WithRenderStats keeps statistics on drawing the WorkerSet, available from
+// RenderStats and noted at the end of the final frame, to tell a slow
+// terminal from slow rendering. They are off by default, and cost nothing
+// when off.
+func WithRenderStats(b bool) Option {
+	return func(w *WorkerSet) {
+		if b {
+			w.stats = &renderStats{}
+		} else {
+			w.stats = nil
+		}
+	}
+}
+
+// RenderStats counts the frames drawn for a WorkerSet and the time taken to
+// draw them
+type RenderStats struct {
+	// Frames is the number of frames laid out, and Layout the total time
+	// taken to lay them out
+	Frames int64         `json:"frames"`
+	Layout time.Duration `json:"layout"`
+
+	// Writes is the number of writes of frames to the terminal, Bytes the
+	// total written, and Write the total time taken. On a display shared
+	// with other WorkerSets each write includes their frames too.
+	Writes int64         `json:"writes"`
+	Bytes  int64         `json:"bytes"`
+	Write  time.Duration `json:"write"`
+
+	// Skipped is the number of changes that didn't get a frame of their
+	// own, as they came while one was already due
+	Skipped int64 `json:"skipped"`
+}
+
+type renderStats struct {
+	mu sync.Mutex
+	RenderStats
+}
+
+// RenderStats returns the statistics kept with WithRenderStats, which are
+// all zero without it
+func (w *WorkerSet) RenderStats() RenderStats {
+	s := w.stats
+	if s == nil {
+		return RenderStats{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.RenderStats
+}
+
+// laidOut records a frame laid out in d
+func (s *renderStats) laidOut(d time.Duration) {
+	s.mu.Lock()
+	s.Frames++
+	s.Layout += d
+	s.mu.Unlock()
+}
+
+// skipped records a change coalesced into a frame already due
+func (s *renderStats) skipped() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Skipped++
+	s.mu.Unlock()
+}
+
+// wrote records a write of p that took d, in the recording and stats if
+// they are being kept, and checks whether the frame was slow
+func (w *WorkerSet) wrote(p []byte, d time.Duration) {
+	if w.recording != nil {
+		w.recording.frame(p, w.now(), w.Size)
+	}
+	w.checkFrame(d)
+	s := w.stats
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Writes++
+	s.Bytes += int64(len(p))
+	s.Write += d
+	s.mu.Unlock()
+}
+
+// statsLine summarizes the statistics for the final frame, or returns an
+// empty string if they aren't kept
+func (w *WorkerSet) statsLine() string {
+	if w.stats == nil {
+		return ""
+	}
+	s := w.RenderStats()
+	avg := func(total time.Duration, n int64) string {
+		if n == 0 {
+			return "0s"
+		}
+		return (total / time.Duration(n)).Round(10 * time.Microsecond).String()
+	}
+	m := &w.messages
+	return "  " + m.pluralf(int(s.Frames), m.RenderStats, s.Frames, avg(s.Layout, s.Frames), avg(s.Write, s.Writes), s.Skipped)
+}
@@ -0,0 +1,77 @@
+package multistatus
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingClock is the real clock, counting calls to Now
+type countingClock struct {
+	calls atomic.Int64
+}
+
+func (c *countingClock) Now() time.Time {
+	c.calls.Add(1)
+	return time.Now()
+}
+
+func (c *countingClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func TestRenderStats(t *testing.T) {
+	var out syncBuf
+	ws := New(WithOutput(&out), WithTTY(true), WithColor(false), WithRenderStats(true),
+		WithRefreshInterval(time.Millisecond))
+	w := ws.Add("a")
+	time.AfterFunc(20*time.Millisecond, w.Done)
+	ws.Print(context.Background())
+
+	s := ws.RenderStats()
+	if s.Frames < 2 || s.Writes < 2 || s.Layout <= 0 {
+		t.Errorf("stats didn't advance: %+v", s)
+	}
+	// the final frame, with the stats line, is counted once written
+	if n := int64(len(out.String())); s.Bytes != n {
+		t.Errorf("%d bytes counted of %d written", s.Bytes, n)
+	}
+	if i := strings.LastIndex(out.String(), "  rendered "); i < 0 || !strings.Contains(out.String()[i:], " frames, avg ") {
+		t.Errorf("no stats line at the end of %q", out.String())
+	}
+}
+
+func TestRenderStatsLine(t *testing.T) {
+	ws := New(WithClock(newFakeClock()), WithColor(false), WithRenderStats(true))
+	ws.Add("a").Done()
+	ws.frame(false, false)
+	ws.stats.skipped()
+	lines := ws.frameLines(false, true)
+	// the fake clock stands still, so layout takes no time, and the final
+	// frame is counted once it is laid out, after its stats line
+	if got, want := lines[len(lines)-1], "  rendered 1 frame, avg 0s layout, 0s write, 1 skipped"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderStatsOff(t *testing.T) {
+	frameCalls := func(opts ...Option) int64 {
+		clock := &countingClock{}
+		ws := New(append([]Option{WithClock(clock), WithColor(false)}, opts...)...)
+		ws.Add("a")
+		before := clock.calls.Load()
+		ws.frame(true, false)
+		return clock.calls.Load() - before
+	}
+	off, on := frameCalls(), frameCalls(WithRenderStats(true))
+	// timing the layout reads the clock before and after
+	if on-off != 2 {
+		t.Errorf("%d clock reads per frame without stats and %d with them, want 2 fewer without", off, on)
+	}
+	ws := New(WithRenderStats(true), WithRenderStats(false))
+	if ws.stats != nil || ws.RenderStats() != (RenderStats{}) {
+		t.Error("stats kept after turning them off")
+	}
+}
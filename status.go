@@ -0,0 +1,70 @@
+package multistatus
+
+import "time"
+
+// WithStatusThrottle limits how often each Worker's status text is updated
+// to once per interval. Values set in between are dropped except the latest,
+// which is applied once the interval has passed, so Workers may call
+// SetStatus for every item they process without flooding the display or the
+// Event stream.
+func WithStatusThrottle(interval time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.statusThrottle = interval
+	}
+}
+
+// SetStatus sets a short line of text describing what the Worker is doing,
+// shown alongside its name. Setting the status it already has does nothing.
+func (w *Worker) SetStatus(s string) {
+	ws := w.parent
+	ws.mu.Lock()
+	if ws.statusThrottle > 0 {
+		w.nextStatus = s
+		if w.statusTimer != nil {
+			ws.mu.Unlock()
+			return
+		}
+		if wait := time.Until(w.statusSet.Add(ws.statusThrottle)); wait > 0 {
+			w.statusTimer = time.AfterFunc(wait, w.flushStatus)
+			ws.mu.Unlock()
+			return
+		}
+	}
+	changed := w.applyStatus(s)
+	ws.mu.Unlock()
+	if changed {
+		w.statusChanged(s)
+	}
+}
+
+// flushStatus applies the latest status held back by the throttle
+func (w *Worker) flushStatus() {
+	ws := w.parent
+	ws.mu.Lock()
+	w.statusTimer = nil
+	s := w.nextStatus
+	changed := w.applyStatus(s)
+	ws.mu.Unlock()
+	if changed {
+		w.statusChanged(s)
+	}
+}
+
+// applyStatus sets the status, publishing its Event, and reports whether it
+// changed. The caller must hold the parent's lock.
+func (w *Worker) applyStatus(s string) bool {
+	w.statusSet = time.Now()
+	if s == w.status {
+		return false
+	}
+	w.status = s
+	w.parent.publish(Event{Type: EventStatus, Worker: w, ID: w.id, From: w.State, To: w.State, Status: s, Time: w.statusSet})
+	return true
+}
+
+// statusChanged redraws and logs a new status
+func (w *Worker) statusChanged(s string) {
+	ws := w.parent
+	ws.touch()
+	ws.log.printf("#%d %s: status %q", w.id, stripControl(w.Name), s)
+}
@@ -0,0 +1,107 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// statusEvents subscribes to ws and returns a function listing the statuses
+// of the Events delivered so far
+func statusEvents(ws *WorkerSet) func() []string {
+	var mu sync.Mutex
+	var statuses []string
+	ws.Subscribe(func(e Event) {
+		if e.Type == EventStatus {
+			mu.Lock()
+			statuses = append(statuses, e.Status)
+			mu.Unlock()
+		}
+	})
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), statuses...)
+	}
+}
+
+func TestSetStatusDuplicate(t *testing.T) {
+	ws := New(WithSilent(true))
+	events := statusEvents(ws)
+	w := ws.Add("task")
+	for _, s := range []string{"a", "a", "b", "b", "b", "a"} {
+		w.SetStatus(s)
+	}
+	w.Done()
+	ws.Close()
+	if got, want := events(), []string{"a", "b", "a"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStatusThrottle(t *testing.T) {
+	ws := New(WithSilent(true), WithStatusThrottle(50*time.Millisecond))
+	events := statusEvents(ws)
+	w := ws.Add("task")
+	for i := 0; i < 1000; i++ {
+		w.SetStatus(fmt.Sprint("item ", i))
+	}
+	if got := ws.Snapshot()[0].Status; got != "item 0" {
+		t.Errorf("status is %q before the interval passed, want the first", got)
+	}
+	// the latest is applied once the interval has passed
+	if !waitFor(func() bool { return ws.Snapshot()[0].Status == "item 999" }) {
+		t.Fatalf("status is %q, want the latest", ws.Snapshot()[0].Status)
+	}
+	w.Done()
+	ws.Close()
+	if got, want := events(), []string{"item 0", "item 999"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStatusThrottleFinish(t *testing.T) {
+	ws := New(WithSilent(true), WithStatusThrottle(time.Hour))
+	w := ws.Add("task")
+	w.SetStatus("first")
+	w.SetStatus("last")
+	w.Done()
+	// a finished Worker shows its latest status without waiting
+	if got := ws.Snapshot()[0].Status; got != "last" {
+		t.Errorf("status is %q, want last", got)
+	}
+}
+
+// benchmarkSetStatus times a Worker setting a new status in a hot loop while
+// the WorkerSet is printed, reporting the frames drawn per status
+func benchmarkSetStatus(b *testing.B, opts ...Option) {
+	opts = append([]Option{WithOutput(&syncBuf{}), WithTTY(true), WithRenderStats(true),
+		WithRefreshInterval(time.Millisecond)}, opts...)
+	ws := New(opts...)
+	w := ws.Add("hot")
+	ws.Subscribe(func(Event) {})
+	done := make(chan struct{})
+	go func() {
+		ws.Print(context.Background())
+		close(done)
+	}()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.SetStatus(fmt.Sprint(i))
+	}
+	b.StopTimer()
+	w.Done()
+	<-done
+	b.ReportMetric(float64(ws.RenderStats().Frames)/float64(b.N), "frames/op")
+}
+
+func BenchmarkSetStatus(b *testing.B) {
+	benchmarkSetStatus(b)
+}
+
+func BenchmarkSetStatusThrottled(b *testing.B) {
+	benchmarkSetStatus(b, WithStatusThrottle(100*time.Millisecond))
+}
@@ -0,0 +1,101 @@
+package multistatus
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// statusFile periodically rewrites a JSON snapshot of a WorkerSet
+type statusFile struct {
+	path    string
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// WithStatusFile keeps a JSON snapshot of the WorkerSet, as produced by
+// MarshalJSON, in the named file while Print runs. The file is rewritten
+// whenever a Worker changes and at least every few seconds, always by
+// renaming a complete temporary file into place so readers never see partial
+// JSON. When Print returns the file is left holding the final snapshot.
+func WithStatusFile(path string) Option {
+	return func(w *WorkerSet) {
+		w.statusFile = &statusFile{path: path}
+	}
+}
+
+// WithStatusFileInterval sets how often the status file is rewritten when
+// nothing has changed, 2 seconds by default. It has no effect without
+// WithStatusFile, which may be given before or after it.
+func WithStatusFileInterval(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.statusInterval = d
+	}
+}
+
+// start begins rewriting the status file in the background
+func (f *statusFile) start(w *WorkerSet) {
+	if f == nil {
+		return
+	}
+	f.stop = make(chan struct{})
+	f.stopped = make(chan struct{})
+	go func() {
+		defer close(f.stopped)
+		var written int64 = -1
+		last := time.Time{}
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-time.After(w.refresh):
+			}
+			v := atomic.LoadInt64(&w.version)
+			if v == written && time.Since(last) < w.statusInterval {
+				continue
+			}
+			if f.write(w) == nil {
+				written, last = v, time.Now()
+			}
+		}
+	}()
+}
+
+// finish stops the background writer and writes the final snapshot
+func (f *statusFile) finish(w *WorkerSet) error {
+	if f == nil || f.stop == nil {
+		return nil
+	}
+	close(f.stop)
+	<-f.stopped
+	return f.write(w)
+}
+
+// write atomically replaces the status file with a fresh snapshot, readable
+// by everyone, with mode 0644
+func (f *statusFile) write(w *WorkerSet) error {
+	b, err := w.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
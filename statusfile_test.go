@@ -0,0 +1,50 @@
+package multistatus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStatusFileIntervalOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+	for _, opts := range [][]Option{
+		{WithStatusFileInterval(time.Minute), WithStatusFile(path)},
+		{WithStatusFile(path), WithStatusFileInterval(time.Minute)},
+	} {
+		ws := New(opts...)
+		if ws.statusInterval != time.Minute {
+			t.Errorf("interval is %v, want %v", ws.statusInterval, time.Minute)
+		}
+	}
+}
+
+func TestStatusFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no Unix permissions")
+	}
+	path := filepath.Join(t.TempDir(), "status.json")
+	ws := New(WithSilent(true), WithStatusFile(path))
+	ws.Add("done").Done()
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := fi.Mode().Perm(); mode != 0644 {
+		t.Errorf("status file has mode %v, want %v", mode, os.FileMode(0644))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if sum, err := DecodeSummary(f); err != nil || sum.Completed != 1 {
+		t.Errorf("status file holds %+v, %v", sum, err)
+	}
+}
@@ -0,0 +1,170 @@
+package multistatus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Step is one item of a Worker's checklist, set with Steps. Its State is
+// Pending until it is checked off as Completed or Failed.
+type Step struct {
+	Name  string      `json:"name"`
+	State WorkerState `json:"state"`
+	Err   string      `json:"error,omitempty"`
+}
+
+// Steps sets the Worker's checklist for multi-phase work, such as "download",
+// "verify" and "install", replacing any earlier one. While the Worker is
+// running on a terminal its steps are listed under its line, or summarized
+// as "2/3 steps" on the line when there isn't room for them.
+func (w *Worker) Steps(names ...string) {
+	ws := w.parent
+	steps := make([]Step, len(names))
+	for i, name := range names {
+		steps[i] = Step{Name: name, State: Pending}
+	}
+	ws.mu.Lock()
+	w.steps = steps
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// StepDone checks off the named step as Completed, adding it to the end of
+// the checklist if it isn't on it
+func (w *Worker) StepDone(name string) {
+	w.setStep(name, Completed, nil)
+}
+
+// StepFail checks off the named step as Failed with err, adding it to the end
+// of the checklist if it isn't on it. If the Worker later fails without an
+// error of its own, its error is made from the errors of its failed steps.
+func (w *Worker) StepFail(name string, err error) {
+	if err == nil {
+		err = errors.New(w.parent.messages.Failed)
+	}
+	w.setStep(name, Failed, err)
+}
+
+func (w *Worker) setStep(name string, state WorkerState, err error) {
+	ws := w.parent
+	ws.mu.Lock()
+	i := 0
+	for i < len(w.steps) && w.steps[i].Name != name {
+		i++
+	}
+	if i == len(w.steps) {
+		w.steps = append(w.steps, Step{Name: name})
+	} else {
+		// Copy on write, as snapshots share the slice
+		w.steps = append([]Step(nil), w.steps...)
+	}
+	w.steps[i].State = state
+	if err != nil {
+		w.steps[i].Err = err.Error()
+	}
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// SetProgress sets the fraction of the Worker's work done, from 0 to 1, in
+// place of the share of its steps checked off. A negative fraction goes back
+// to the steps.
+func (w *Worker) SetProgress(fraction float64) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.fraction = min(fraction, 1)
+	w.hasFraction = fraction >= 0
+	ws.mu.Unlock()
+	ws.touch()
+}
+
+// stepsProgress returns the fraction of v's steps checked off, or -1 if it
+// has none
+func stepsProgress(v WorkerStatus) float64 {
+	if len(v.Steps) == 0 {
+		return -1
+	}
+	done := 0
+	for _, s := range v.Steps {
+		if s.State.finished() {
+			done++
+		}
+	}
+	return float64(done) / float64(len(v.Steps))
+}
+
+// stepErr joins the errors of the Worker's failed steps, or returns nil if
+// none failed. The caller must hold the parent's lock.
+func (w *Worker) stepErr() error {
+	var errs []error
+	for _, s := range w.steps {
+		if s.State == Failed {
+			errs = append(errs, fmt.Errorf("%s: %s", s.Name, s.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stepsShown reports whether v's steps are listed under its line
+func stepsShown(v WorkerStatus) bool {
+	return len(v.Steps) > 0 && !v.State.finished()
+}
+
+// stepsFit reports whether the steps of rows can be listed under them within
+// budget lines along with used lines already taken. A budget of 0 or less
+// means there's no limit.
+func stepsFit(rows []WorkerStatus, used, budget int) bool {
+	if budget <= 0 {
+		return true
+	}
+	n := used + len(rows)
+	for _, v := range rows {
+		if stepsShown(v) {
+			n += len(v.Steps)
+		}
+	}
+	return n <= budget
+}
+
+// stepsText summarizes v's steps on its line when they are collapsed
+func (w *WorkerSet) stepsText(v WorkerStatus) string {
+	if !v.stepsCollapsed || !stepsShown(v) {
+		return ""
+	}
+	done := 0
+	for _, s := range v.Steps {
+		if s.State.finished() {
+			done++
+		}
+	}
+	m := &w.messages
+	return "(" + m.pluralf(len(v.Steps), m.Steps, done, len(v.Steps)) + ")"
+}
+
+// stepLines lists v's steps under its line, indented to start under the
+// Worker's name. The first pending step is marked as running and those after
+// it are dimmed.
+func (w *WorkerSet) stepLines(v WorkerStatus, marker string, markers [numStates]string, words bool, width int, level ColorLevel) []string {
+	if v.stepsCollapsed || !stepsShown(v) {
+		return nil
+	}
+	indent := strings.Repeat(" ", 3+stringWidth(marker))
+	lines := make([]string, 0, len(v.Steps))
+	running := false
+	for _, s := range v.Steps {
+		name := stripControl(s.Name)
+		var l string
+		switch {
+		case s.State != Pending:
+			l = indent + markers[s.State] + " " + name
+		case !running:
+			running = true
+			l = indent + markers[Pending] + " " + name
+		default:
+			l = indent + colorize(w.plainMarker(Pending, words)+" "+name, w.theme.DimColor, level)
+		}
+		lines = append(lines, truncate(l, widthOrMax(width)))
+	}
+	return lines
+}
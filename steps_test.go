@@ -0,0 +1,140 @@
+package multistatus
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stepsSet returns a WorkerSet of the given height with an "install" Worker
+// partway through its steps, and another Worker after it
+func stepsSet(height int) (*WorkerSet, *Worker) {
+	ws := New(WithColor(false), WithSize(80, height))
+	w := ws.Add("install")
+	ws.Add("other")
+	w.Steps("download", "verify", "install")
+	w.StepDone("download")
+	return ws, w
+}
+
+func TestStepsRender(t *testing.T) {
+	ws, w := stepsSet(24)
+	_, lines := ws.frame(true, false)
+	want := []string{
+		"  ⠋ install",
+		"    ✔ download",
+		"    ⠋ verify",
+		"    - install",
+		"  ⠋ other",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+
+	w.StepFail("verify", errors.New("bad checksum"))
+	_, lines = ws.frame(true, false)
+	// the next step is now the running one
+	if lines[2] != "    ✗ verify" || strings.HasPrefix(lines[3], "    -") {
+		t.Errorf("after failing verify got %q", lines[1:4])
+	}
+
+	// finished Workers drop their checklist
+	w.Done()
+	if _, lines = ws.frame(true, false); len(lines) != 2 {
+		t.Errorf("steps listed under a finished worker: %q", lines)
+	}
+}
+
+func TestStepsCollapsed(t *testing.T) {
+	for _, tc := range []struct {
+		height int
+		lines  []string
+	}{
+		// two rows and three steps fit within the budget of height-1 lines
+		{6, []string{"  ⠋ install", "    ✔ download", "    ⠋ verify", "    - install", "  ⠋ other"}},
+		{5, []string{"  ⠋ install (1/3 steps)", "  ⠋ other"}},
+	} {
+		ws, _ := stepsSet(tc.height)
+		if _, lines := ws.frame(true, false); !reflect.DeepEqual(lines, tc.lines) {
+			t.Errorf("height %d: got %q, want %q", tc.height, lines, tc.lines)
+		}
+	}
+
+	// without a terminal the steps are always summarized
+	ws, _ := stepsSet(24)
+	if _, lines := ws.frame(false, false); lines[0] != "  [ .. ] install (1/3 steps)" {
+		t.Errorf("got %q", lines[0])
+	}
+}
+
+func TestStepsError(t *testing.T) {
+	ws := New(WithSilent(true))
+	w := ws.Add("install")
+	w.Steps("download", "verify", "install")
+	w.StepFail("verify", errors.New("bad checksum"))
+	w.StepFail("extra", nil)
+	w.Fail()
+	if want := "verify: bad checksum\nextra: failed"; w.Err() == nil || w.Err().Error() != want {
+		t.Errorf("got %v, want %q", w.Err(), want)
+	}
+
+	// an error of the Worker's own wins
+	own := ws.Add("own")
+	own.StepFail("verify", errors.New("bad checksum"))
+	own.FailWith(errors.New("disk full"))
+	if got := own.Err().Error(); got != "disk full" {
+		t.Errorf("got %q, want the worker's own error", got)
+	}
+}
+
+func TestStepsJSON(t *testing.T) {
+	_, w := stepsSet(24)
+	w.StepFail("verify", errors.New("bad checksum"))
+	b, err := json.Marshal(w.parent.Snapshot()[0].Steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"name":"download","state":"completed"},{"name":"verify","state":"failed","error":"bad checksum"},{"name":"install","state":"pending"}]`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
+
+func TestStepsProgress(t *testing.T) {
+	ws, w := stepsSet(24)
+	var got []float64
+	ws.lineFunc = func(l Line) string {
+		if l.Name == "install" {
+			got = append(got, l.Progress)
+		}
+		return l.Name
+	}
+	progress := func() float64 {
+		got = nil
+		ws.frame(true, false)
+		return got[0]
+	}
+	for _, tc := range []struct {
+		name   string
+		change func()
+		want   float64
+	}{
+		{"one step done", func() {}, 1.0 / 3},
+		{"a step failed", func() { w.StepFail("verify", errors.New("bad checksum")) }, 2.0 / 3},
+		{"set", func() { w.SetProgress(0.9) }, 0.9},
+		{"set over the steps", func() { w.StepDone("install") }, 0.9},
+		{"set past the end", func() { w.SetProgress(2) }, 1},
+		{"cleared", func() { w.SetProgress(0.5); w.SetProgress(-1) }, 1},
+		{"new steps", func() { w.Steps("one", "two") }, 0},
+	} {
+		tc.change()
+		if p := progress(); p != tc.want {
+			t.Errorf("%s: progress is %v, want %v", tc.name, p, tc.want)
+		}
+	}
+	if p := ws.Snapshot()[1].progress(); p != -1 {
+		t.Errorf("a Worker without steps has progress %v, want -1", p)
+	}
+}
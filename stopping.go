@@ -0,0 +1,112 @@
+package multistatus
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// WithStopTimeout sets how long Print waits, once canceled, for Stopping
+// Workers to finish winding down, 5s by default. Print keeps drawing them
+// meanwhile, and returns without them once the timeout passes.
+func WithStopTimeout(d time.Duration) Option {
+	return func(w *WorkerSet) {
+		w.stopTimeout = d
+	}
+}
+
+// OnCancel registers fn to be called in its own goroutine if Print is
+// canceled while the Worker is pending. The Worker is then shown as Stopping
+// until it finishes, so it can clean up before calling Done or Fail.
+func (w *Worker) OnCancel(fn func()) {
+	ws := w.parent
+	ws.mu.Lock()
+	w.onCancel = append(w.onCancel, fn)
+	ws.mu.Unlock()
+}
+
+// abort cancels every Worker straight away with err, for a Print canceled
+// before it began. Queued Workers are canceled without being started, and
+// pending Workers are canceled rather than left to wind down, though their
+// OnCancel functions are still called.
+func (w *WorkerSet) abort(err error) {
+	w.cancel()
+	w.dispatch()
+
+	var pending []*Worker
+	var fns []func()
+	w.mu.Lock()
+	for _, worker := range w.members() {
+		if !worker.State.finished() {
+			pending = append(pending, worker)
+			fns = append(fns, worker.onCancel...)
+		}
+	}
+	w.mu.Unlock()
+	w.log.printf("print canceled before starting: %d canceled", len(pending))
+	for _, worker := range pending {
+		worker.transition(Canceled, worker.finalizeIdle(err))
+	}
+	for _, fn := range fns {
+		go fn()
+	}
+}
+
+// stop cancels the Workers' Context once Print is canceled. Queued Workers
+// are canceled without being started, and pending Workers watching for
+// cancelation become Stopping. stop then waits until they have all finished,
+// done is closed, or the stop timeout passes.
+func (w *WorkerSet) stop(done <-chan bool) {
+	w.cancel()
+	w.dispatch()
+
+	var stopping []*Worker
+	var fns []func()
+	w.mu.Lock()
+	now := w.now()
+	for _, worker := range w.members() {
+		if worker.State == Pending && (worker.watchesCtx || len(worker.onCancel) > 0) {
+			worker.State = Stopping
+			worker.record(Pending, now)
+			atomic.AddInt64(&w.counts[Pending], -1)
+			atomic.AddInt64(&w.counts[Stopping], 1)
+			stopping = append(stopping, worker)
+			fns = append(fns, worker.onCancel...)
+		}
+	}
+	// The Events share a Time, so are published in order of ID
+	sort.Slice(stopping, func(i, j int) bool { return stopping[i].id < stopping[j].id })
+	for _, worker := range stopping {
+		w.publish(Event{Worker: worker, ID: worker.id, From: Pending, To: Stopping, Time: now})
+	}
+	w.mu.Unlock()
+	if len(stopping) > 0 {
+		for _, worker := range stopping {
+			w.log.printf("#%d %s: %s -> %s", worker.id, stripControl(worker.Name), Pending, Stopping)
+		}
+		w.touch()
+		for _, fn := range fns {
+			go fn()
+		}
+	}
+
+	deadline := time.After(w.stopTimeout)
+	for w.Count(Stopping) > 0 || w.isSettling() {
+		select {
+		case <-done:
+			return
+		case <-deadline:
+			w.log.printf("stop timed out: %d stopping", w.Count(Stopping))
+			return
+		case <-time.After(w.refresh):
+		}
+	}
+}
+
+// isSettling reports whether a Worker has finished but not yet written its
+// log record and teed output, or is being canceled from the queue
+func (w *WorkerSet) isSettling() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.settling > 0
+}
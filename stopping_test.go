@@ -0,0 +1,133 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cancelAfter returns a context canceled after d
+func cancelAfter(t *testing.T, d time.Duration) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestStoppingOnCancel(t *testing.T) {
+	ws := New(WithSilent(true))
+	var mu sync.Mutex
+	var events []string
+	ws.Subscribe(func(e Event) {
+		mu.Lock()
+		events = append(events, fmt.Sprintf("%s %s->%s", e.Worker.Name, e.From, e.To))
+		mu.Unlock()
+	})
+	cleanup := ws.Add("cleanup")
+	cleanup.OnCancel(func() {
+		time.Sleep(50 * time.Millisecond)
+		cleanup.Done()
+	})
+	watcher := ws.Add("watcher")
+	go func() {
+		<-watcher.Context().Done()
+		time.Sleep(30 * time.Millisecond)
+		watcher.FailWith(watcher.Context().Err())
+	}()
+	idle := ws.Add("idle")
+
+	go func() {
+		time.Sleep(35 * time.Millisecond)
+		if s := ws.Snapshot()[0].State; s != Stopping {
+			t.Errorf("cleanup is %v while winding down", s)
+		}
+	}()
+	start := time.Now()
+	ws.Print(cancelAfter(t, 20*time.Millisecond))
+	if d := time.Since(start); d < 60*time.Millisecond {
+		t.Errorf("Print returned after %v, before the cleanup finished", d)
+	}
+	if cleanup.State != Completed || watcher.State != Canceled {
+		t.Errorf("cleanup is %v, watcher %v", cleanup.State, watcher.State)
+	}
+	if idle.State == Stopping {
+		t.Errorf("Worker not watching for cancelation shown as Stopping")
+	}
+	ws.Close()
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"cleanup pending->stopping", "watcher pending->stopping", "watcher stopping->canceled", "cleanup stopping->completed"}
+	if fmt.Sprint(events[:4]) != fmt.Sprint(want) {
+		t.Errorf("events %q, want %q", events, want)
+	}
+}
+
+func TestStopTimeout(t *testing.T) {
+	ws := New(WithSilent(true), WithStopTimeout(30*time.Millisecond))
+	stuck := ws.Add("stuck")
+	release := make(chan struct{})
+	stuck.OnCancel(func() { <-release })
+	start := time.Now()
+	ws.Print(cancelAfter(t, 10*time.Millisecond))
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Print waited %v past the stop timeout", d)
+	}
+	if s := ws.Snapshot()[0].State; s != Stopping {
+		t.Errorf("stuck Worker is %v, want still Stopping", s)
+	}
+	close(release)
+	stuck.Done()
+}
+
+func TestPrintPrecanceled(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"tty", []Option{WithTTY(true)}, "  ⊘ running\n  ⊘ queued\n  ⊘ added\n"},
+		{"plain", []Option{WithTTY(false)}, "  [SKIP] running\n  [SKIP] queued\n  [SKIP] added\n"},
+		{"quiet", []Option{WithTTY(true), WithQuiet(true)}, "  [SKIP] running\n  [SKIP] queued\n  [SKIP] added\nCanceled.\n"},
+	} {
+		var out syncBuf
+		ws := New(append([]Option{WithOutput(&out), WithColor(false), WithConcurrency(1)}, tc.opts...)...)
+		ws.Go("running", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		var started atomic.Bool
+		ws.Go("queued", func(ctx context.Context) error {
+			started.Store(true)
+			return nil
+		})
+		ws.Add("added")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := ws.Print(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: got %v, want context.Canceled", tc.name, err)
+		}
+		if started.Load() {
+			t.Errorf("%s: queued worker started", tc.name)
+		}
+		if sum := ws.Summary(); sum.Canceled != 3 {
+			t.Errorf("%s: %d canceled, want 3", tc.name, sum.Canceled)
+		}
+		// a single frame, with the cursor shown again
+		vt := newVT(24)
+		vt.feed(out.String())
+		if got := strings.Join(vt.lines(), "\n") + "\n"; got != tc.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.name, got, tc.want)
+		}
+		if s := out.String(); strings.LastIndex(s, csi+"?25l") > strings.LastIndex(s, csi+"?25h") {
+			t.Errorf("%s: cursor left hidden", tc.name)
+		}
+		if n := strings.Count(out.String(), "running"); n != 1 {
+			t.Errorf("%s: %d frames drawn, want 1", tc.name, n)
+		}
+	}
+}
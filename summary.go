@@ -0,0 +1,171 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Summary reports the status of every Worker in a WorkerSet along with the
+// number in each state. Stopping Workers are counted as Pending.
+type Summary struct {
+	// Schema is SchemaVersion for a Summary returned by Summary
+	Schema string `json:"schema"`
+
+	Workers   []WorkerStatus `json:"workers"`
+	Completed int            `json:"completed"`
+	Failed    int            `json:"failed"`
+	Canceled  int            `json:"canceled,omitempty"`
+	Pending   int            `json:"pending"`
+
+	// TotalDuration and ActiveDuration add up the Elapsed and Active
+	// times of every Worker
+	TotalDuration  time.Duration `json:"total_duration"`
+	ActiveDuration time.Duration `json:"active_duration"`
+
+	// Duration is the wall time of the run, as returned by Duration, and
+	// PausedDuration the time during which every unfinished Worker was
+	// paused
+	Duration       time.Duration `json:"duration"`
+	PausedDuration time.Duration `json:"paused_duration,omitempty"`
+
+	// OK is set once every Worker has finished without being canceled,
+	// and none failed or, with WithFailureThreshold or WithFailureRatio,
+	// no more than tolerated
+	OK bool `json:"ok"`
+
+	// FirstError is the error of the Worker that failed first, as
+	// chosen by FirstError, and FirstFailed is that Worker's ID, or -1
+	// if none failed
+	FirstError  string `json:"first_error,omitempty"`
+	FirstFailed int64  `json:"first_failed"`
+
+	// DroppedEvents is the number of Events dropped because subscribers
+	// fell behind, as returned by DroppedEvents
+	DroppedEvents int64 `json:"dropped_events,omitempty"`
+
+	// Misuse is set to ErrForeignWorker's message once Workers has been
+	// found to hold a Worker that doesn't belong in it
+	Misuse string `json:"misuse,omitempty"`
+
+	// FailureTimeline counts when Workers failed over the run, and is nil
+	// when none failed
+	FailureTimeline *FailureTimeline `json:"failure_timeline,omitempty"`
+
+	// Groups counts the Workers of each group set with SetGroup, and is
+	// nil when no Worker has a group
+	Groups map[string]GroupSummary `json:"groups,omitempty"`
+}
+
+// Summary returns a Summary of the WorkerSet as it is now, including removed
+// Workers in the counts with WithKeepRemovedCounts
+func (w *WorkerSet) Summary() Summary {
+	snap := countable(w.Snapshot())
+	counts, total := w.tally(snap)
+	_, groups := groupSummaries(snap)
+	first, failed := firstFailure(snap)
+	if !failed {
+		first.ID = -1
+	}
+	var misuse string
+	w.mu.Lock()
+	if w.misuse != nil {
+		misuse = w.misuse.Error()
+	}
+	w.mu.Unlock()
+	ok := w.withinLimit(counts[Failed], total) &&
+		counts[Canceled]+counts[Pending]+counts[Stopping] == 0
+	duration, paused := w.duration(snap, w.now())
+	var wall, active time.Duration
+	for _, v := range snap {
+		wall += v.Elapsed
+		active += v.Active
+	}
+	return Summary{
+		Schema:         SchemaVersion,
+		Workers:        snap,
+		Completed:      counts[Completed],
+		Failed:         counts[Failed],
+		Canceled:       counts[Canceled],
+		Pending:        counts[Pending] + counts[Stopping],
+		TotalDuration:  wall,
+		ActiveDuration: active,
+		Duration:       duration,
+		PausedDuration: paused,
+		OK:             ok,
+		FirstError:     first.Err,
+		FirstFailed:    first.ID,
+		Groups:         groups,
+		Misuse:         misuse,
+		DroppedEvents:  w.DroppedEvents(),
+
+		FailureTimeline: failureTimeline(snap),
+	}
+}
+
+// ErrIncomplete is matched by the error of Err when Workers were canceled or
+// haven't finished
+var ErrIncomplete = errors.New("multistatus: workers canceled or unfinished")
+
+// Err returns nil if the run is OK, as reported by Summary. Otherwise it
+// returns the errors of the failed Workers joined in the order they were
+// added, each prefixed by its Worker's name, followed by one matching
+// ErrIncomplete if Workers were canceled or haven't finished. Failures within
+// the threshold set by WithFailureThreshold or WithFailureRatio are left out.
+func (w *WorkerSet) Err() error {
+	counts, total := w.tally(w.Snapshot())
+	var errs []error
+	if !w.withinLimit(counts[Failed], total) {
+		w.mu.Lock()
+		for _, worker := range w.members() {
+			switch {
+			case worker.State != Failed || worker.info:
+			case worker.err == nil:
+				errs = append(errs, fmt.Errorf("%s: %s", worker.Name, w.messages.Failed))
+			default:
+				errs = append(errs, fmt.Errorf("%s: %w", worker.Name, worker.err))
+			}
+		}
+		w.mu.Unlock()
+	}
+	if unfinished := counts[Pending] + counts[Stopping]; counts[Canceled]+unfinished > 0 {
+		errs = append(errs, fmt.Errorf("%w: %d canceled, %d unfinished", ErrIncomplete, counts[Canceled], unfinished))
+	}
+	return errors.Join(errs...)
+}
+
+// ExitCode returns a status for a program to exit with once Print has
+// returned: 0 if the run is OK, as reported by Summary, and 1 otherwise
+func (w *WorkerSet) ExitCode() int {
+	if w.Summary().OK {
+		return 0
+	}
+	return 1
+}
+
+// A Task is a named function for Run
+type Task struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// Run runs every task at once in a new WorkerSet configured by opts, as with
+// Go, and prints their status until they have all finished or ctx is
+// canceled. It returns a Summary of the run, along with the error returned by
+// Err: that of the failed tasks joined in the order the tasks were given,
+// each prefixed by its task's name, with failures within the threshold set
+// by WithFailureThreshold or WithFailureRatio left out. If Print fails or is
+// canceled its error comes first, joined with Err's. If the Options are
+// invalid, that error is returned instead.
+func Run(ctx context.Context, tasks []Task, opts ...Option) (Summary, error) {
+	ws, err := NewWithOptions(opts...)
+	if err != nil {
+		return Summary{}, err
+	}
+	for _, t := range tasks {
+		ws.Go(t.Name, t.Fn)
+	}
+	perr := ws.Print(ctx)
+	return ws.Summary(), errors.Join(perr, ws.Err())
+}
@@ -0,0 +1,108 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	errB, errD := errors.New("b broke"), errors.New("d broke")
+	sum, err := Run(context.Background(), []Task{
+		{"a", func(context.Context) error { return nil }},
+		{"b", func(context.Context) error { return errB }},
+		{"c", func(context.Context) error { panic("c panicked") }},
+		{"d", func(context.Context) error { return errD }},
+	}, WithSilent(true))
+	if sum.Completed != 1 || sum.Failed != 3 || sum.OK {
+		t.Errorf("Summary has %d completed and %d failed, OK %v", sum.Completed, sum.Failed, sum.OK)
+	}
+	if !errors.Is(err, errB) || !errors.Is(err, errD) {
+		t.Fatalf("Run returned %v, want the tasks' errors", err)
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) < 3 || lines[0] != "b: b broke" || !strings.HasPrefix(lines[1], "c: ") || lines[len(lines)-1] != "d: d broke" {
+		t.Errorf("errors aren't in task order:\n%v", err)
+	}
+}
+
+func TestRunWithinThreshold(t *testing.T) {
+	sum, err := Run(context.Background(), []Task{
+		{"a", func(context.Context) error { return nil }},
+		{"b", func(context.Context) error { return errors.New("b broke") }},
+	}, WithSilent(true), WithFailureThreshold(1))
+	if err != nil || !sum.OK {
+		t.Errorf("Run returned %v with OK %v, want the failure tolerated", err, sum.OK)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	errA := errors.New("a broke")
+	ctx, cancel := context.WithCancel(context.Background())
+	sum, err := Run(ctx, []Task{
+		{"a", func(context.Context) error { return errA }},
+		{"b", func(ctx context.Context) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}, WithSilent(true), WithConcurrency(1))
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, errA) || !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("Run returned %v, want the cancelation joined with the task errors", err)
+	}
+	var pe *PrintError
+	if !errors.As(err, &pe) || !strings.HasPrefix(err.Error(), pe.Error()) {
+		t.Errorf("Print's error doesn't come first: %v", err)
+	}
+	if sum.Failed != 1 || sum.Canceled != 1 {
+		t.Errorf("Summary has %d failed and %d canceled, want 1 and 1", sum.Failed, sum.Canceled)
+	}
+}
+
+func TestSummaryRetryDurations(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithOutput(&syncBuf{}), WithClock(clock), WithColor(false), WithElapsed(true), WithRetries(1),
+		WithBackoff(func(int) time.Duration { return 10 * time.Second }))
+	plain := ws.Add("plain")
+	var calls atomic.Int32
+	ws.Go("retried", func(ctx context.Context) error {
+		if calls.Add(1) == 1 {
+			clock.Advance(2 * time.Second)
+			return errors.New("broke")
+		}
+		clock.Advance(3 * time.Second)
+		return nil
+	})
+	if !waitFor(func() bool { return clock.Waiters() == 1 }) {
+		t.Fatal("worker didn't back off")
+	}
+	plain.Done()
+	clock.Advance(10 * time.Second)
+	ws.Print(context.Background())
+
+	// retried ran for 2s and 3s around 10s of backing off
+	sum := ws.Summary()
+	if sum.TotalDuration != 17*time.Second || sum.ActiveDuration != 7*time.Second {
+		t.Errorf("summary has total %v and active %v, want 17s and 7s", sum.TotalDuration, sum.ActiveDuration)
+	}
+	if v := sum.Workers[1]; v.Elapsed != 15*time.Second || v.Active != 5*time.Second {
+		t.Errorf("retried has elapsed %v and active %v, want 15s and 5s", v.Elapsed, v.Active)
+	}
+	// the final report shows the active time only where it differs
+	lines := ws.frameLines(false, true)
+	if want := []string{"  [ OK ] plain (2s)", "  [ OK ] retried (15s, 5s active)"}; lines[0] != want[0] || lines[1] != want[1] {
+		t.Errorf("got %q, want %q", lines[:2], want)
+	}
+}
+
+func TestErrWithoutError(t *testing.T) {
+	ws := New(WithSilent(true))
+	ws.Add("a").Fail()
+	ws.Add("b").FailWith(errors.New("b broke"))
+	if got, want := ws.Err().Error(), "a: failed\nb: b broke"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
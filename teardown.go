@@ -0,0 +1,60 @@
+package multistatus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// teardown completes the outputs fed while the WorkerSet runs, once the final
+// frame has been drawn: the status file is given its final snapshot, then the
+// recording, the run log and the debug log are flushed and closed, and only
+// then are the functions registered with onFinish called, such as to stop the
+// status server, and is the run reported finished by Handler. Whatever
+// watches the run thus never sees it finished before every file is complete.
+// Each stage runs even if an earlier one failed or panicked, and their errors
+// are joined. Later calls do nothing.
+func (w *WorkerSet) teardown() error {
+	w.mu.Lock()
+	if w.tearing {
+		w.mu.Unlock()
+		return nil
+	}
+	w.tearing = true
+	w.mu.Unlock()
+
+	var errs []error
+	for _, stage := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"status file", func() error { return w.statusFile.finish(w) }},
+		{"recording", w.recording.finish},
+		{"run log", w.log.close},
+		{"debug log", w.debug.close},
+	} {
+		errs = append(errs, runStage(stage.name, stage.fn))
+	}
+
+	w.mu.Lock()
+	w.tornDown = true
+	finish := w.finishers
+	w.finishers = nil
+	w.mu.Unlock()
+	for _, fn := range finish {
+		errs = append(errs, runStage("finisher", func() error {
+			fn()
+			return nil
+		}))
+	}
+	return errors.Join(errs...)
+}
+
+// runStage calls fn, turning a panic into an error naming the stage
+func runStage(name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("multistatus: %s: panic: %v", name, r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,105 @@
+package multistatus
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stageWriter notes name in order and calls check the first time it is
+// written to, panicking afterwards if panics is set
+type stageWriter struct {
+	name   string
+	order  *[]string
+	check  func()
+	panics bool
+	once   sync.Once
+}
+
+func (s *stageWriter) Write(p []byte) (int, error) {
+	s.once.Do(func() {
+		*s.order = append(*s.order, s.name)
+		s.check()
+	})
+	if s.panics {
+		panic(s.name + " broke")
+	}
+	return len(p), nil
+}
+
+// teardownSet returns a WorkerSet with every output torn down by Print, the
+// recording held back by a limit so it's written then. Each output checks as
+// it's finished that the stages before it are complete and that Handler
+// doesn't yet report the run finished. The recording panics if
+// breakRecording is set.
+func teardownSet(t *testing.T, order *[]string, breakRecording bool) *WorkerSet {
+	t.Helper()
+	dir := t.TempDir()
+	statusPath, logPath := filepath.Join(dir, "status.json"), filepath.Join(dir, "run.log")
+	var ws *WorkerSet
+	reported := func() bool {
+		rec := httptest.NewRecorder()
+		ws.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+		return strings.Contains(rec.Body.String(), `"finished":true`)
+	}
+	recording := &stageWriter{name: "recording", order: order, panics: breakRecording, check: func() {
+		if b, err := os.ReadFile(statusPath); err != nil || !strings.Contains(string(b), `"finished":true`) {
+			t.Errorf("the status file wasn't final before the recording: %s %v", b, err)
+		}
+		if reported() {
+			t.Error("Handler reported the run finished before the recording")
+		}
+	}}
+	debug := &stageWriter{name: "debug log", order: order, check: func() {
+		if b, err := os.ReadFile(logPath); err != nil || !strings.Contains(string(b), "print finished") {
+			t.Errorf("the run log wasn't closed before the debug log: %q %v", b, err)
+		}
+		if reported() {
+			t.Error("Handler reported the run finished before the debug log")
+		}
+	}}
+	ws = New(WithOutput(&syncBuf{}), WithTTY(true), WithStatusFile(statusPath), WithLogFile(logPath),
+		WithRecording(recording), WithRecordingLimit(1<<20), WithDebugLog(debug))
+	ws.debug.printf("seeded")
+	ws.onFinish(func() {
+		*order = append(*order, "finisher")
+		if !reported() {
+			t.Error("Handler didn't report the run finished to the finisher")
+		}
+	})
+	ws.Add("task").Done()
+	return ws
+}
+
+func TestTeardownOrder(t *testing.T) {
+	var order []string
+	ws := teardownSet(t, &order, false)
+	if err := ws.Print(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"recording", "debug log", "finisher"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("got %v, want %v", order, want)
+	}
+
+	// later calls do nothing
+	if err := ws.teardown(); err != nil || len(order) != 3 {
+		t.Errorf("a second teardown returned %v, leaving %v", err, order)
+	}
+}
+
+func TestTeardownPanic(t *testing.T) {
+	var order []string
+	ws := teardownSet(t, &order, true)
+	err := ws.Print(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "multistatus: recording: panic: recording broke") {
+		t.Errorf("got %v", err)
+	}
+	if want := []string{"recording", "debug log", "finisher"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("got %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,106 @@
+package multistatus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// probeTimeout is how long WithProbeTerminal waits for the terminal to reply
+const probeTimeout = 200 * time.Millisecond
+
+// WithProbeTerminal checks that the output really is a terminal that
+// understands escapes, as some container and CI devices only claim to be,
+// before animating. The check asks the terminal for the cursor position and
+// waits briefly for its reply on the controlling terminal, /dev/tty; with no
+// reply the WorkerSet prints as it would without a terminal. The check is
+// made once, when Print is first called, and its outcome is recorded in the
+// debug log given by WithDebugLog. It has no effect when WithTTY is given.
+func WithProbeTerminal(b bool) Option {
+	return func(w *WorkerSet) {
+		w.probe = b
+	}
+}
+
+// probed reports whether the terminal answered the probe, probing it the
+// first time
+func (w *WorkerSet) probed() bool {
+	w.probeOnce.Do(func() {
+		_, err := cursorColumn(w.terminal, w.out, probeTimeout)
+		if err != nil {
+			w.debug.printf("terminal: probe failed, printing without animation: %v", err)
+			return
+		}
+		w.debug.printf("terminal: probe answered")
+		w.probeOK = true
+	})
+	return w.probeOK
+}
+
+// cursorColumn writes a cursor position query to out and waits up to timeout
+// for a reply on the controlling terminal, put into raw mode with t,
+// returning the cursor's column, counting from 1
+func cursorColumn(t Terminal, out io.Writer, timeout time.Duration) (int, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer tty.Close()
+	restore, err := t.MakeRaw(tty)
+	if err != nil {
+		return 0, err
+	}
+	defer restore()
+
+	if _, err := io.WriteString(out, cursorQuery); err != nil {
+		return 0, err
+	}
+	if err := tty.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+	var reply []byte
+	buf := make([]byte, 32)
+	for {
+		n, err := tty.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if col, ok := parseCursorReply(reply); ok {
+			return col, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if len(reply) > 256 {
+			return 0, errors.New("no cursor position in reply")
+		}
+	}
+}
+
+// parseCursorReply finds the column in a cursor position report,
+// "ESC [ row ; column R", reporting whether reply holds a complete one
+func parseCursorReply(reply []byte) (int, bool) {
+	i := bytes.Index(reply, []byte(csi))
+	if i < 0 {
+		return 0, false
+	}
+	reply = reply[i+len(csi):]
+	end := bytes.IndexByte(reply, 'R')
+	if end < 0 {
+		return 0, false
+	}
+	var row, col int
+	if _, err := fmt.Sscanf(string(reply[:end]), "%d;%d", &row, &col); err != nil {
+		return 0, true
+	}
+	return col, true
+}
+
+// logSizeFallback records, once, that the terminal size couldn't be read
+func (w *WorkerSet) logSizeFallback(err error) {
+	if atomic.CompareAndSwapInt32(&w.sizeLogged, 0, 1) {
+		w.debug.printf("terminal: size unavailable, assuming 80x24: %v", err)
+	}
+}
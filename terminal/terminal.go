@@ -0,0 +1,64 @@
+// Package terminal implements multistatus.Terminal with golang.org/x/term,
+// reading the terminal's real size and supporting raw mode on every platform
+// x/term does. It is kept apart so that multistatus itself needs only the
+// standard library:
+//
+//	ws := multistatus.New(multistatus.WithTerminal(terminal.New()))
+package terminal
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Terminal implements multistatus.Terminal
+type Terminal struct{}
+
+// New returns a Terminal
+func New() Terminal {
+	return Terminal{}
+}
+
+// IsTerminal reports whether f is a terminal
+func (Terminal) IsTerminal(f *os.File) bool {
+	fd, err := fdOf(f)
+	return err == nil && term.IsTerminal(fd)
+}
+
+// Size returns the width and height of the terminal f
+func (Terminal) Size(f *os.File) (width, height int, err error) {
+	fd, err := fdOf(f)
+	if err != nil {
+		return 0, 0, err
+	}
+	return term.GetSize(fd)
+}
+
+// MakeRaw puts the terminal f into raw mode, returning a function restoring
+// its previous state
+func (Terminal) MakeRaw(f *os.File) (restore func(), err error) {
+	fd, err := fdOf(f)
+	if err != nil {
+		return nil, err
+	}
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() { term.Restore(fd, old) }, nil
+}
+
+// fdOf returns f's file descriptor without calling Fd, which would put f
+// into blocking mode and stop read deadlines working
+func fdOf(f *os.File) (int, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var fd int
+	if err := rc.Control(func(p uintptr) { fd = int(p) }); err != nil {
+		return 0, err
+	}
+	return fd, nil
+}
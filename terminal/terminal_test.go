@@ -0,0 +1,30 @@
+package terminal_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/zikes/multistatus"
+	"github.com/zikes/multistatus/terminal"
+)
+
+var _ multistatus.Terminal = terminal.New()
+
+func TestPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	term := terminal.New()
+	if term.IsTerminal(w) {
+		t.Error("a pipe is a terminal")
+	}
+	if _, _, err := term.Size(w); err == nil {
+		t.Error("a pipe has a size")
+	}
+	if _, err := term.MakeRaw(w); err == nil {
+		t.Error("a pipe entered raw mode")
+	}
+}
@@ -0,0 +1,63 @@
+package multistatus
+
+import (
+	"errors"
+	"os"
+	"strconv"
+)
+
+// A Terminal detects and controls the terminal a WorkerSet draws on. The
+// default uses only the standard library: it takes any character device for
+// a terminal, reads the size from the COLUMNS and LINES environment
+// variables, and can't enter raw mode, so WithProbeTerminal and GuardNewline
+// get no reply. The terminal package implements it in full; give it with
+// WithTerminal, or with SetDefaults for every WorkerSet.
+type Terminal interface {
+	// IsTerminal reports whether f is a terminal
+	IsTerminal(f *os.File) bool
+
+	// Size returns the width and height of the terminal f
+	Size(f *os.File) (width, height int, err error)
+
+	// MakeRaw puts the terminal f into raw mode, returning a function
+	// restoring its previous state
+	MakeRaw(f *os.File) (restore func(), err error)
+}
+
+// WithTerminal detects and controls the output's terminal with t in place of
+// the default, which uses only the standard library. A nil t restores the
+// default.
+func WithTerminal(t Terminal) Option {
+	return func(w *WorkerSet) {
+		if t == nil {
+			t = stdTerminal{}
+		}
+		w.terminal = t
+	}
+}
+
+var (
+	errNoSize    = errors.New("COLUMNS and LINES are unset")
+	errNoRawMode = errors.New("raw mode needs a Terminal given with WithTerminal")
+)
+
+// stdTerminal is the Terminal used unless WithTerminal is given
+type stdTerminal struct{}
+
+func (stdTerminal) IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func (stdTerminal) Size(f *os.File) (width, height int, err error) {
+	width, werr := strconv.Atoi(os.Getenv("COLUMNS"))
+	height, herr := strconv.Atoi(os.Getenv("LINES"))
+	if werr != nil || herr != nil || width <= 0 || height <= 0 {
+		return 0, 0, errNoSize
+	}
+	return width, height, nil
+}
+
+func (stdTerminal) MakeRaw(f *os.File) (restore func(), err error) {
+	return nil, errNoRawMode
+}
@@ -0,0 +1,68 @@
+package multistatus
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// sizedTerminal is a Terminal reporting every file as a terminal of the
+// given size
+type sizedTerminal struct {
+	width, height int
+}
+
+func (t sizedTerminal) IsTerminal(*os.File) bool { return true }
+
+func (t sizedTerminal) Size(*os.File) (int, int, error) { return t.width, t.height, nil }
+
+func (t sizedTerminal) MakeRaw(*os.File) (func(), error) { return func() {}, nil }
+
+func TestWithTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	ws := New(WithOutput(w), WithTerminal(sizedTerminal{120, 40}))
+	if !ws.isTerminal() {
+		t.Error("the Terminal given wasn't asked")
+	}
+	if width, height := ws.Size(); width != 120 || height != 40 {
+		t.Errorf("got size %dx%d, want 120x40", width, height)
+	}
+
+	ws = New(WithOutput(w), WithTerminal(sizedTerminal{120, 40}), WithTerminal(nil))
+	if ws.isTerminal() {
+		t.Error("a pipe is a terminal by default")
+	}
+}
+
+func TestStdTerminal(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "")
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	var term stdTerminal
+	if term.IsTerminal(w) {
+		t.Error("a pipe is a terminal")
+	}
+	if _, _, err := term.Size(w); !errors.Is(err, errNoSize) {
+		t.Errorf("got %v without COLUMNS and LINES, want %v", err, errNoSize)
+	}
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", "50")
+	if width, height, err := term.Size(w); width != 132 || height != 50 || err != nil {
+		t.Errorf("got %dx%d, %v, want 132x50", width, height, err)
+	}
+	if _, err := term.MakeRaw(w); !errors.Is(err, errNoRawMode) {
+		t.Errorf("got %v, want %v", err, errNoRawMode)
+	}
+}
@@ -0,0 +1,113 @@
+package multistatus
+
+// Theme controls the markers and colors used to draw each Worker
+type Theme struct {
+	// Completed and Failed mark finished Workers
+	Completed string
+	Failed    string
+
+	// Pending marks pending Workers when the output isn't a terminal
+	Pending string
+
+	// Stopping marks Workers winding down after cancelation, and Canceled
+	// those that were canceled
+	Stopping string
+	Canceled string
+
+	// CompletedLabel, FailedLabel, PendingLabel, StoppingLabel and
+	// CanceledLabel replace the markers in the word style, used by default
+	// when the output isn't a terminal. They should all be the same width
+	// so names line up.
+	CompletedLabel string
+	FailedLabel    string
+	PendingLabel   string
+	StoppingLabel  string
+	CanceledLabel  string
+
+	// Info marks rows added with AddInfo, and InfoLabel replaces it in the
+	// word style
+	Info      string
+	InfoLabel string
+
+	// Backoff marks Workers waiting to retry with WithRetries, Blocked
+	// those marked with SetBlocked, Queued those waiting for their turn to
+	// start, and Paused those paused with Pause, in place of the spinner.
+	// BackoffLabel, BlockedLabel, QueuedLabel and PausedLabel replace them
+	// in the word style.
+	Backoff      string
+	BackoffLabel string
+	Blocked      string
+	BlockedLabel string
+	Queued       string
+	QueuedLabel  string
+	Paused       string
+	PausedLabel  string
+
+	// Spinner holds the animation frames for pending Workers on a terminal
+	Spinner string
+
+	// Sparkline holds the levels of the throughput sparkline shown with
+	// WithRateSparkline, from lowest to highest. When it's empty the
+	// counts are listed as numbers instead.
+	Sparkline string
+
+	// CompletedColor and FailedColor are applied to the finished markers
+	// on a terminal
+	CompletedColor Color
+	FailedColor    Color
+
+	// CanceledColor is applied to the Stopping and Canceled markers on a
+	// terminal
+	CanceledColor Color
+
+	// WarningColor is applied to the elapsed time of Workers running over
+	// their expected duration
+	WarningColor Color
+
+	// DimColor is used for de-emphasized parts, such as the unfilled part
+	// of a progress bar
+	DimColor Color
+}
+
+// DefaultTheme is the Theme used unless WithTheme is given
+var DefaultTheme = Theme{
+	Completed:      "✔",
+	Failed:         "✗",
+	Pending:        "-",
+	Stopping:       "◌",
+	Canceled:       "⊘",
+	Info:           "ℹ",
+	Backoff:        "◷",
+	Blocked:        "⧗",
+	Queued:         "·",
+	Paused:         "‖",
+	CompletedLabel: "[ OK ]",
+	FailedLabel:    "[FAIL]",
+	PendingLabel:   "[ .. ]",
+	StoppingLabel:  "[STOP]",
+	CanceledLabel:  "[SKIP]",
+	InfoLabel:      "[INFO]",
+	BackoffLabel:   "[WAIT]",
+	BlockedLabel:   "[HOLD]",
+	QueuedLabel:    "[ .. ]",
+	PausedLabel:    "[ || ]",
+	Spinner:        defaultSpinner,
+	Sparkline:      defaultSparkline,
+	CompletedColor: Green,
+	FailedColor:    Red,
+	CanceledColor:  Yellow,
+	WarningColor:   Yellow,
+	DimColor:       DefaultColor.Dim(),
+}
+
+// colorize wraps s in the escape sequences drawing it in c at the given
+// level, or returns it unchanged if there's nothing to draw
+func colorize(s string, c Color, level ColorLevel) string {
+	e := escWriter{level: level}
+	if !e.SetColor(c) {
+		return s
+	}
+	e.WriteString(s)
+	e.ResetColor()
+	return e.String()
+}
@@ -0,0 +1,86 @@
+package multistatus
+
+// WithFailureThreshold tolerates up to n failed Workers: a run with no more
+// failures than that is still reported as OK by Summary and Run, though the
+// failures are listed in the final report as usual.
+func WithFailureThreshold(n int) Option {
+	return func(w *WorkerSet) {
+		w.failThreshold = n
+		w.tolerant = true
+	}
+}
+
+// WithFailureRatio tolerates failed Workers up to the fraction f of all
+// Workers, such as 0.05 for 5%, as WithFailureThreshold does for a fixed
+// number. When both are given, the run must be within both to be OK.
+func WithFailureRatio(f float64) Option {
+	return func(w *WorkerSet) {
+		w.failRatio = f
+		w.tolerantRatio = true
+	}
+}
+
+// WithFailFast cancels the run once more Workers have failed than tolerated
+// by WithFailureThreshold or WithFailureRatio, or once any has failed
+// without them: Print cancels the Workers still running as it would if its
+// context were canceled, and returns a *PrintError matching ErrFailFast.
+func WithFailFast(b bool) Option {
+	return func(w *WorkerSet) {
+		w.failFast = b
+	}
+}
+
+// checkFailFast cancels the Workers and Print once the failures exceed the
+// limit, with WithFailFast
+func (w *WorkerSet) checkFailFast() {
+	if !w.failFast {
+		return
+	}
+	counts, total := w.tally(w.Snapshot())
+	if w.withinLimit(counts[Failed], total) {
+		return
+	}
+	w.mu.Lock()
+	stop := w.stopPrint
+	if stop == nil || w.failedFast {
+		w.mu.Unlock()
+		return
+	}
+	w.failedFast = true
+	w.mu.Unlock()
+	w.log.printf("fail fast: %d failed, canceling", counts[Failed])
+	w.cancel()
+	stop()
+}
+
+// failureLimit returns the number of failures tolerated out of total
+// Workers, or -1 if there's no limit set
+func (w *WorkerSet) failureLimit(total int) int {
+	limit := -1
+	if w.tolerant {
+		limit = w.failThreshold
+	}
+	if w.tolerantRatio {
+		if n := int(w.failRatio * float64(total)); limit < 0 || n < limit {
+			limit = n
+		}
+	}
+	return limit
+}
+
+// withinLimit reports whether failed failures out of total are tolerated
+func (w *WorkerSet) withinLimit(failed, total int) bool {
+	return failed == 0 || failed <= w.failureLimit(total)
+}
+
+// thresholdLine notes that the failures in snap are tolerated, for the end
+// of the final report, or returns an empty string when that doesn't apply
+func (w *WorkerSet) thresholdLine(snap []WorkerStatus) string {
+	counts, total := w.tally(snap)
+	failed := counts[Failed]
+	if failed == 0 || !w.withinLimit(failed, total) {
+		return ""
+	}
+	m := &w.messages
+	return m.pluralf(failed, m.WithinThreshold, failed, w.failureLimit(total))
+}
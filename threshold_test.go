@@ -0,0 +1,134 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// runFailing runs total Workers of which the first failed fail, one at a
+// time once Print begins, returning the WorkerSet, Print's error and the
+// number of Workers whose function ran
+func runFailing(total, failed int, opts ...Option) (*WorkerSet, error, int32) {
+	ws := New(append([]Option{WithSilent(true), WithConcurrency(1), WithStartOnPrint(true)}, opts...)...)
+	var ran int32
+	for i := 0; i < total; i++ {
+		i := i
+		ws.Go(fmt.Sprintf("task %d", i), func(ctx context.Context) error {
+			atomic.AddInt32(&ran, 1)
+			if i < failed {
+				return fmt.Errorf("error %d", i)
+			}
+			return nil
+		})
+	}
+	err := ws.Print(context.Background())
+	return ws, err, atomic.LoadInt32(&ran)
+}
+
+func TestFailureThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		failed int
+		opts   []Option
+		ok     bool
+	}{
+		{"no failures", 0, nil, true},
+		{"one failure, no threshold", 1, nil, false},
+		{"below threshold", 2, []Option{WithFailureThreshold(3)}, true},
+		{"at threshold", 3, []Option{WithFailureThreshold(3)}, true},
+		{"above threshold", 4, []Option{WithFailureThreshold(3)}, false},
+		{"zero threshold", 1, []Option{WithFailureThreshold(0)}, false},
+		{"at ratio", 2, []Option{WithFailureRatio(0.2)}, true},
+		{"above ratio", 3, []Option{WithFailureRatio(0.2)}, false},
+		{"within threshold, above ratio", 3, []Option{WithFailureThreshold(5), WithFailureRatio(0.2)}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err, _ := runFailing(10, tc.failed, tc.opts...)
+			if err != nil {
+				t.Fatalf("Print returned %v", err)
+			}
+			sum := ws.Summary()
+			if sum.OK != tc.ok || sum.Failed != tc.failed {
+				t.Errorf("Summary has OK %v with %d failed, want %v with %d", sum.OK, sum.Failed, tc.ok, tc.failed)
+			}
+			want := 0
+			if !tc.ok {
+				want = 1
+			}
+			if got := ws.ExitCode(); got != want {
+				t.Errorf("ExitCode() = %d, want %d", got, want)
+			}
+			err = ws.Err()
+			if tc.ok {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), "task 0: error 0") || strings.Count(err.Error(), "\n") != tc.failed-1 {
+				t.Errorf("Err() = %v, want each failure", err)
+			}
+		})
+	}
+}
+
+func TestFailureThresholdIncomplete(t *testing.T) {
+	ws := New(WithSilent(true), WithFailureThreshold(5), WithStopTimeout(10*time.Millisecond))
+	ws.Add("left pending")
+	if ws.Summary().OK || ws.ExitCode() != 1 || !errors.Is(ws.Err(), ErrIncomplete) {
+		t.Error("a pending Worker is reported OK")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ws.Go("canceled", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ws.Print(ctx)
+	sum := ws.Summary()
+	if sum.OK || sum.Canceled != 1 || sum.Pending != 1 {
+		t.Errorf("Summary has OK %v with %d canceled and %d pending, want false, 1 and 1", sum.OK, sum.Canceled, sum.Pending)
+	}
+	if err := ws.Err(); !errors.Is(err, ErrIncomplete) || !strings.Contains(err.Error(), "1 canceled, 1 unfinished") {
+		t.Errorf("Err() = %v, want one matching ErrIncomplete", err)
+	}
+}
+
+func TestFailFastThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		failed   int
+		opts     []Option
+		failFast bool
+	}{
+		{"no threshold", 1, nil, true},
+		{"at threshold", 2, []Option{WithFailureThreshold(2)}, false},
+		{"above threshold", 3, []Option{WithFailureThreshold(2)}, true},
+		{"at ratio", 2, []Option{WithFailureRatio(0.2)}, false},
+		{"above ratio", 3, []Option{WithFailureRatio(0.2)}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err, ran := runFailing(10, tc.failed, append(tc.opts, WithFailFast(true))...)
+			if !tc.failFast {
+				if err != nil || ran != 10 || !ws.Summary().OK {
+					t.Errorf("Print returned %v after %d ran, want every Worker to run", err, ran)
+				}
+				return
+			}
+			if !errors.Is(err, ErrFailFast) {
+				t.Fatalf("Print returned %v, want ErrFailFast", err)
+			}
+			if ran != int32(tc.failed) {
+				t.Errorf("%d Workers ran, want %d", ran, tc.failed)
+			}
+			if sum := ws.Summary(); sum.OK || sum.Canceled != 10-tc.failed {
+				t.Errorf("Summary has OK %v with %d canceled, want false with %d", sum.OK, sum.Canceled, 10-tc.failed)
+			}
+		})
+	}
+}
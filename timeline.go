@@ -0,0 +1,128 @@
+package multistatus
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxTimelineBuckets is the most buckets a FailureTimeline is divided into;
+// shorter runs get one bucket per second
+const maxTimelineBuckets = 30
+
+// sparkBlocks are the characters of a sparkline, from empty to full
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// WithFailureTimeline ends the final report with when Workers failed, to show
+// whether failures clustered: a sparkline of failures over the run, followed
+// by each failed Worker with the time it failed.
+func WithFailureTimeline(b bool) Option {
+	return func(w *WorkerSet) {
+		w.failureTimeline = b
+	}
+}
+
+// A FailureTimeline counts failures over a run, from when the first Worker
+// started until the last one finished, in buckets of equal width
+type FailureTimeline struct {
+	Start       time.Time     `json:"start"`
+	BucketWidth time.Duration `json:"bucket_width"`
+	Buckets     []int         `json:"buckets"`
+}
+
+// failureTimeline buckets the failures in snap, or returns nil if none failed
+func failureTimeline(snap []WorkerStatus) *FailureTimeline {
+	var start, end time.Time
+	failed := false
+	for _, v := range snap {
+		if start.IsZero() || v.Started.Before(start) {
+			start = v.Started
+		}
+		if v.Finished.After(end) {
+			end = v.Finished
+		}
+		failed = failed || v.State == Failed
+	}
+	if !failed {
+		return nil
+	}
+	span := end.Sub(start)
+	n := int(span / time.Second)
+	if n < 1 {
+		n = 1
+	}
+	if n > maxTimelineBuckets {
+		n = maxTimelineBuckets
+	}
+	t := &FailureTimeline{Start: start, BucketWidth: span / time.Duration(n), Buckets: make([]int, n)}
+	for _, v := range snap {
+		if v.State == Failed {
+			t.Buckets[t.bucket(v.Finished)]++
+		}
+	}
+	return t
+}
+
+// bucket returns the index of the bucket holding tm
+func (t *FailureTimeline) bucket(tm time.Time) int {
+	if t.BucketWidth <= 0 {
+		return 0
+	}
+	i := int(tm.Sub(t.Start) / t.BucketWidth)
+	if i < 0 {
+		return 0
+	}
+	if i >= len(t.Buckets) {
+		return len(t.Buckets) - 1
+	}
+	return i
+}
+
+// Sparkline draws the failures in each bucket as a block character, taller
+// for more failures
+func (t *FailureTimeline) Sparkline() string {
+	most := 0
+	for _, n := range t.Buckets {
+		if n > most {
+			most = n
+		}
+	}
+	var b strings.Builder
+	for _, n := range t.Buckets {
+		i := 0
+		if n > 0 {
+			i = (n*(len(sparkBlocks)-1) + most - 1) / most
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// timelineLines formats the failures in snap for the final report with
+// WithFailureTimeline
+func (w *WorkerSet) timelineLines(snap []WorkerStatus) []string {
+	t := failureTimeline(snap)
+	if !w.failureTimeline || t == nil {
+		return nil
+	}
+	var failed []WorkerStatus
+	for _, v := range snap {
+		if v.State == Failed {
+			failed = append(failed, v)
+		}
+	}
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failed[i].Finished.Before(failed[j].Finished)
+	})
+	const layout = "15:04:05"
+	m := &w.messages
+	first, last := failed[0].Finished.Format(layout), failed[len(failed)-1].Finished.Format(layout)
+	lines := []string{
+		"  " + m.pluralf(len(failed), m.FailureTimeline, len(failed), first, last),
+		"  " + t.Sparkline(),
+	}
+	for _, v := range failed {
+		lines = append(lines, "    "+v.Finished.Format(layout)+" "+stripControl(v.Name))
+	}
+	return lines
+}
@@ -0,0 +1,92 @@
+package multistatus
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// at returns the time d into the fake clock's day
+func at(d time.Duration) time.Time {
+	return newFakeClock().Now().Add(d)
+}
+
+func TestFailureTimelineBuckets(t *testing.T) {
+	status := func(state WorkerState, finished time.Duration) WorkerStatus {
+		return WorkerStatus{State: state, Started: at(0), Finished: at(finished)}
+	}
+	for _, tc := range []struct {
+		name  string
+		snap  []WorkerStatus
+		width time.Duration
+		want  []int
+	}{
+		{"seconds", []WorkerStatus{status(Failed, 2*time.Second), status(Failed, 2500*time.Millisecond), status(Completed, 10*time.Second)},
+			time.Second, []int{0, 0, 2, 0, 0, 0, 0, 0, 0, 0}},
+		{"instant", []WorkerStatus{status(Failed, 300*time.Millisecond)},
+			300 * time.Millisecond, []int{1}},
+		{"hour", []WorkerStatus{status(Failed, time.Minute), status(Failed, 59*time.Minute), status(Completed, time.Hour)},
+			2 * time.Minute, append(append([]int{1}, make([]int, 28)...), 1)},
+	} {
+		tl := failureTimeline(tc.snap)
+		if tl == nil {
+			t.Errorf("%s: no timeline", tc.name)
+			continue
+		}
+		if !tl.Start.Equal(at(0)) || tl.BucketWidth != tc.width || !reflect.DeepEqual(tl.Buckets, tc.want) {
+			t.Errorf("%s: got %v wide %v from %v, want %v wide %v", tc.name, tl.Buckets, tl.BucketWidth, tl.Start, tc.want, tc.width)
+		}
+	}
+	if tl := failureTimeline([]WorkerStatus{status(Completed, time.Second)}); tl != nil {
+		t.Errorf("timeline %+v without failures", tl)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	for _, tc := range []struct {
+		buckets []int
+		want    string
+	}{
+		{[]int{1, 2, 4, 0}, "▃▅█▁"},
+		{[]int{0, 0, 2, 0}, "▁▁█▁"},
+		{[]int{3}, "█"},
+		{[]int{1, 7}, "▂█"},
+	} {
+		if got := (&FailureTimeline{Buckets: tc.buckets}).Sparkline(); got != tc.want {
+			t.Errorf("%v: got %q, want %q", tc.buckets, got, tc.want)
+		}
+	}
+}
+
+func TestFailureTimelineReport(t *testing.T) {
+	clock := newFakeClock()
+	ws := New(WithClock(clock), WithColor(false), WithFailureTimeline(true))
+	a, b, c := ws.Add("a"), ws.Add("b"), ws.Add("c")
+	clock.Advance(2 * time.Second)
+	b.Fail()
+	clock.Advance(time.Second)
+	a.Fail()
+	clock.Advance(7 * time.Second)
+	c.Done()
+
+	_, lines := ws.frame(false, true)
+	want := []string{
+		"  2 failures between 12:00:02 and 12:00:03",
+		"  ▁▁██▁▁▁▁▁▁",
+		"    12:00:02 b",
+		"    12:00:03 a",
+	}
+	if len(lines) < len(want) || !reflect.DeepEqual(lines[len(lines)-len(want):], want) {
+		t.Errorf("got %q, want it to end with %q", lines, want)
+	}
+	if sum := ws.Summary(); sum.FailureTimeline == nil || !reflect.DeepEqual(sum.FailureTimeline.Buckets, []int{0, 0, 1, 1, 0, 0, 0, 0, 0, 0}) {
+		t.Errorf("summary timeline %+v", sum.FailureTimeline)
+	}
+
+	// off by default
+	ws = New(WithClock(clock), WithColor(false))
+	ws.Add("a").Fail()
+	if _, lines := ws.frame(false, true); len(lines) != 1 {
+		t.Errorf("timeline reported without WithFailureTimeline: %q", lines)
+	}
+}
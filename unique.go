@@ -0,0 +1,60 @@
+package multistatus
+
+import "fmt"
+
+// WithUniqueNames has Add give each Worker a name no other Worker in the
+// WorkerSet has, suffixing a repeated name with a number, as in
+// "deploy web (2)". The name asked for remains available from
+// Worker.RequestedName.
+func WithUniqueNames(b bool) Option {
+	return func(w *WorkerSet) {
+		w.uniqueNames = b
+	}
+}
+
+// AddUnique adds a Worker as Add does, but always gives it a unique name as
+// WithUniqueNames would
+func (w *WorkerSet) AddUnique(name string) *Worker {
+	return w.add(name, true)
+}
+
+// RequestedName returns the name the Worker was added with, before any
+// suffix added to make it unique
+func (w *Worker) RequestedName() string {
+	return w.requested
+}
+
+// uniqueName returns name, suffixed if needed to make it unique when unique
+// is set, and records it as taken. The caller must hold the lock.
+func (w *WorkerSet) uniqueName(name string, unique bool) string {
+	if w.names == nil {
+		w.names = make(nameTable)
+	}
+	return w.names.take(name, unique)
+}
+
+// A nameTable records the names taken, along with the last suffix given to
+// each repeated name
+type nameTable map[string]int
+
+// take returns name, suffixed if needed to make it unique when unique is
+// set, and records it as taken
+func (t nameTable) take(name string, unique bool) string {
+	if n, taken := t[name]; unique && taken {
+		// Resume counting from the last suffix given, skipping any taken
+		// by a Worker that was added with it
+		for {
+			n++
+			s := fmt.Sprintf("%s (%d)", name, n)
+			if _, taken := t[s]; !taken {
+				t[name] = n
+				name = s
+				break
+			}
+		}
+	}
+	if _, taken := t[name]; !taken {
+		t[name] = 1
+	}
+	return name
+}
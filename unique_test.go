@@ -0,0 +1,83 @@
+package multistatus
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// workerNames returns the names of the Workers in a snapshot of ws
+func workerNames(ws *WorkerSet) []string {
+	var names []string
+	for _, v := range ws.Snapshot() {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+func TestUniqueNames(t *testing.T) {
+	ws := New(WithSilent(true), WithUniqueNames(true))
+	ws.Add("deploy web")
+	ws.Add("deploy web")
+	ws.Add("deploy db")
+	third := ws.Add("deploy web")
+	ws.AddBatch([]string{"deploy db", "deploy db"})
+	want := []string{"deploy web", "deploy web (2)", "deploy db", "deploy web (3)", "deploy db (2)", "deploy db (3)"}
+	if got := workerNames(ws); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if third.RequestedName() != "deploy web" {
+		t.Errorf("requested name %q", third.RequestedName())
+	}
+	if v := ws.Snapshot()[3]; v.RequestedName != "deploy web" {
+		t.Errorf("snapshot requested name %q", v.RequestedName)
+	}
+	if v := ws.Snapshot()[0]; v.RequestedName != "" {
+		t.Errorf("requested name %q on an unchanged name", v.RequestedName)
+	}
+}
+
+func TestAddUnique(t *testing.T) {
+	ws := New(WithSilent(true))
+	ws.Add("a")
+	ws.Add("a")
+	ws.Add("a (2)")
+	ws.AddUnique("a")
+	ws.AddUnique("a")
+	// suffixes already taken by a name are skipped
+	want := []string{"a", "a", "a (2)", "a (3)", "a (4)"}
+	if got := workerNames(ws); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestAddUniqueConcurrent(t *testing.T) {
+	const n = 50
+	ws := New(WithSilent(true), WithUniqueNames(true))
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ws.Add("job")
+		}()
+	}
+	wg.Wait()
+	seen := map[string]bool{}
+	for _, name := range workerNames(ws) {
+		seen[name] = true
+	}
+	for i := 1; i <= n; i++ {
+		name := "job"
+		if i > 1 {
+			name = fmt.Sprintf("job (%d)", i)
+		}
+		if !seen[name] {
+			t.Errorf("no worker named %q", name)
+		}
+	}
+	if len(seen) != n {
+		t.Errorf("%d distinct names for %d workers", len(seen), n)
+	}
+}
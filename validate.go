@@ -0,0 +1,127 @@
+package multistatus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNilOutput is returned when the WorkerSet's output is nil without
+	// WithSilent
+	ErrNilOutput = errors.New("multistatus: output is nil")
+
+	// ErrInvalidOption is returned, along with details, when an Option is
+	// given a value out of its range
+	ErrInvalidOption = errors.New("multistatus: invalid option")
+
+	// ErrConflictingOptions is returned, along with details, when Options
+	// that can't be used together are given
+	ErrConflictingOptions = errors.New("multistatus: conflicting options")
+
+	// ErrEmptyName is returned by Print when a Worker was added without a
+	// name under NameReject
+	ErrEmptyName = errors.New("multistatus: worker name is empty")
+)
+
+// NewWithOptions is like New, but returns an error if the Options are
+// invalid rather than leaving it to Print. Errors can be compared against
+// ErrNilOutput, ErrInvalidOption and ErrConflictingOptions using errors.Is.
+func NewWithOptions(opts ...Option) (*WorkerSet, error) {
+	ws := New(opts...)
+	if ws.err != nil {
+		return nil, ws.err
+	}
+	if err := ws.validate(); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// validate checks the configuration for values Print can't work with
+func (w *WorkerSet) validate() error {
+	if w.out == nil && !w.silent {
+		return ErrNilOutput
+	}
+	if w.refresh <= 0 {
+		return fmt.Errorf("%w: refresh interval %v is not positive", ErrInvalidOption, w.refresh)
+	}
+	for _, c := range []struct {
+		name string
+		n    int64
+	}{
+		{"concurrency", int64(w.concurrency)},
+		{"max rows", int64(w.maxRows)},
+		{"error lines", int64(w.maxErrLines)},
+		{"error grouping", int64(w.groupErrors)},
+		{"retries", int64(w.retries)},
+		{"compact height", int64(w.compactBelow)},
+		{"drain timeout", int64(w.drainTimeout)},
+		{"output limit", int64(w.outputLimit)},
+		{"set output limit", int64(w.setOutputLimit)},
+		{"plain wrap width", int64(w.plainWrap)},
+		{"abandon after", int64(w.abandonAfter)},
+		{"rotate interval", int64(w.rotate)},
+		{"status throttle", int64(w.statusThrottle)},
+		{"status file interval", int64(w.statusInterval)},
+		{"history limit", int64(w.historyLimit)},
+		{"slow refresh interval", int64(w.slowRefresh)},
+		{"slow frame threshold", int64(w.slowFrame)},
+		{"failure threshold", int64(w.failThreshold)},
+		{"rate bucket", int64(w.rateBucket)},
+		{"rate buckets", int64(w.rateBuckets)},
+		{"finish hook timeout", int64(w.finishHookTimeout)},
+	} {
+		if c.n < 0 {
+			return fmt.Errorf("%w: %s is negative", ErrInvalidOption, c.name)
+		}
+	}
+	if w.failRatio < 0 || w.failRatio > 1 {
+		return fmt.Errorf("%w: failure ratio %v is not between 0 and 1", ErrInvalidOption, w.failRatio)
+	}
+	if r := w.recording; r != nil && (r.keepalive < 0 || r.limit < 0) {
+		return fmt.Errorf("%w: recording keepalive or limit is negative", ErrInvalidOption)
+	}
+	if w.quiet && w.scrollHistory {
+		return fmt.Errorf("%w: WithQuiet and WithScrollingHistory", ErrConflictingOptions)
+	}
+	return nil
+}
+
+// NamePolicy decides what Add does with a name that is empty once leading
+// and trailing spaces are trimmed
+type NamePolicy int
+
+// Available NamePolicies
+const (
+	// NameAuto names the Worker "task-N", N counting from 1 in the order
+	// Workers were added
+	NameAuto NamePolicy = iota
+	// NameReject makes Print return ErrEmptyName
+	NameReject
+	// NameAllow keeps the empty name
+	NameAllow
+)
+
+// WithNamePolicy sets what Add does with empty names, NameAuto by default
+func WithNamePolicy(p NamePolicy) Option {
+	return func(w *WorkerSet) {
+		w.namePolicy = p
+	}
+}
+
+// workerName returns the name to give the Worker with the given ID. The
+// caller must hold the lock.
+func (w *WorkerSet) workerName(s string, id int64) string {
+	s = strings.TrimSpace(s)
+	if s != "" {
+		return s
+	}
+	switch w.namePolicy {
+	case NameAuto:
+		return fmt.Sprintf("task-%d", id+1)
+	case NameReject:
+		w.setErr(ErrEmptyName)
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	var buf syncBuf
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want error
+	}{
+		{"defaults", nil, nil},
+		{"nil output", []Option{WithOutput(nil)}, ErrNilOutput},
+		{"nil output silent", []Option{WithOutput(nil), WithSilent(true)}, nil},
+		{"zero refresh", []Option{WithRefreshInterval(0)}, ErrInvalidOption},
+		{"negative refresh", []Option{WithRefreshInterval(-time.Second)}, ErrInvalidOption},
+		{"negative concurrency", []Option{WithConcurrency(-1)}, ErrInvalidOption},
+		{"negative max rows", []Option{WithMaxRows(-1)}, ErrInvalidOption},
+		{"negative error lines", []Option{WithErrorLines(-1)}, ErrInvalidOption},
+		{"negative retries", []Option{WithRetries(-1)}, ErrInvalidOption},
+		{"negative wrap width", []Option{WithPlainWrapWidth(-1)}, ErrInvalidOption},
+		{"negative rotate", []Option{WithRotateVisible(-time.Second)}, ErrInvalidOption},
+		{"negative drain timeout", []Option{WithDrainTimeout(-time.Second)}, ErrInvalidOption},
+		{"negative abandon", []Option{WithAbandonAfter(-time.Second)}, ErrInvalidOption},
+		{"negative history", []Option{WithHistoryLimit(-1)}, ErrInvalidOption},
+		{"negative throttle", []Option{WithStatusThrottle(-time.Second)}, ErrInvalidOption},
+		{"negative threshold", []Option{WithFailureThreshold(-1)}, ErrInvalidOption},
+		{"ratio above 1", []Option{WithFailureRatio(1.5)}, ErrInvalidOption},
+		{"ratio below 0", []Option{WithFailureRatio(-0.1)}, ErrInvalidOption},
+		{"quiet scrolling", []Option{WithQuiet(true), WithScrollingHistory(true)}, ErrConflictingOptions},
+		{"quiet", []Option{WithQuiet(true)}, nil},
+		{"scrolling", []Option{WithScrollingHistory(true)}, nil},
+		{"in range", []Option{WithConcurrency(4), WithMaxRows(10), WithRetries(2), WithFailureRatio(0.5)}, nil},
+	} {
+		opts := append([]Option{WithOutput(&buf)}, tc.opts...)
+		ws, err := NewWithOptions(opts...)
+		if !errors.Is(err, tc.want) || (err == nil) != (tc.want == nil) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+			continue
+		}
+		if (ws == nil) == (err == nil) {
+			t.Errorf("%s: got WorkerSet %v with error %v", tc.name, ws, err)
+		}
+		if err != nil {
+			// New leaves the same error to Print
+			perr := New(opts...).Print(context.Background())
+			if !errors.Is(perr, tc.want) {
+				t.Errorf("%s: Print returned %v", tc.name, perr)
+			}
+		}
+	}
+}
+
+func TestNamePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy NamePolicy
+		names  [3]string
+		err    error
+	}{
+		{NameAuto, [3]string{"task-1", "x", "task-3"}, nil},
+		{NameAllow, [3]string{"", "x", ""}, nil},
+		{NameReject, [3]string{"", "x", ""}, ErrEmptyName},
+	} {
+		ws := New(WithSilent(true), WithNamePolicy(tc.policy))
+		for _, name := range []string{"  ", " x ", ""} {
+			ws.Add(name).Done()
+		}
+		for i, w := range ws.Workers {
+			if w.Name != tc.names[i] {
+				t.Errorf("policy %d: Worker %d named %q, want %q", tc.policy, i, w.Name, tc.names[i])
+			}
+		}
+		if err := ws.Print(context.Background()); !errors.Is(err, tc.err) || (err == nil) != (tc.err == nil) {
+			t.Errorf("policy %d: Print returned %v, want %v", tc.policy, err, tc.err)
+		}
+	}
+}
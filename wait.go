@@ -0,0 +1,56 @@
+package multistatus
+
+import (
+	"context"
+	"fmt"
+)
+
+// Wait blocks until the Worker has finished, returning the error it failed
+// with, or until ctx is canceled, returning ctx.Err(). It returns straight
+// away for a Worker that has already finished.
+func (w *Worker) Wait(ctx context.Context) error {
+	select {
+	case <-w.done:
+		return w.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitFor blocks until every Worker with one of the given names has
+// finished, such as to start work that depends on them while the rest keep
+// running. It returns the first error among them, in the order the names
+// are given, or ctx.Err() if ctx is canceled first. A name no Worker has
+// returns an error wrapping ErrNotMember straight away.
+func (w *WorkerSet) WaitFor(ctx context.Context, names ...string) error {
+	var workers []*Worker
+	w.mu.Lock()
+	for _, name := range names {
+		found := false
+		for _, worker := range w.members() {
+			if worker.Name == name {
+				workers = append(workers, worker)
+				found = true
+			}
+		}
+		if !found {
+			w.mu.Unlock()
+			return fmt.Errorf("%w: %q", ErrNotMember, name)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, worker := range workers {
+		select {
+		case <-worker.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	for _, worker := range workers {
+		if err := worker.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package multistatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitResult runs wait in a goroutine, returning a channel of its error
+func waitResult(wait func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() { ch <- wait() }()
+	return ch
+}
+
+func TestWorkerWait(t *testing.T) {
+	ws := New(WithSilent(true))
+	db := ws.Add("database ready")
+	waited := waitResult(func() error { return db.Wait(context.Background()) })
+	select {
+	case err := <-waited:
+		t.Fatalf("Wait returned %v before the worker finished", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	db.Done()
+	db.Done() // repeated transitions mustn't close done again
+	db.Fail()
+	if err := <-waited; err != nil {
+		t.Errorf("got %v", err)
+	}
+
+	// finished already, so straight away
+	broke := errors.New("broke")
+	failed := ws.Add("failed")
+	failed.FailWith(broke)
+	if err := failed.Wait(context.Background()); err != broke {
+		t.Errorf("got %v, want %v", err, broke)
+	}
+	if err := ws.AddInfo("info").Wait(context.Background()); err != nil {
+		t.Errorf("information row: got %v", err)
+	}
+}
+
+func TestWorkerWaitCanceled(t *testing.T) {
+	ws := New(WithSilent(true))
+	w := ws.Add("never")
+	ctx, cancel := context.WithCancel(context.Background())
+	waited := waitResult(func() error { return w.Wait(ctx) })
+	cancel()
+	if err := <-waited; err != context.Canceled {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if w.State != Pending {
+		t.Errorf("worker is %v after its waiter gave up", w.State)
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	ws := New(WithSilent(true))
+	a, b, _ := ws.Add("a"), ws.Add("b"), ws.Add("c")
+	waited := waitResult(func() error { return ws.WaitFor(context.Background(), "a", "b") })
+	a.Done()
+	select {
+	case err := <-waited:
+		t.Fatalf("WaitFor returned %v with b running", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	b.FailWith(errors.New("b broke"))
+	if err := <-waited; err == nil || err.Error() != "b broke" {
+		t.Errorf("got %v, want b's error", err)
+	}
+
+	if err := ws.WaitFor(context.Background(), "a", "missing"); !errors.Is(err, ErrNotMember) {
+		t.Errorf("got %v, want ErrNotMember", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := ws.WaitFor(ctx, "c"); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
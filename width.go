@@ -0,0 +1,230 @@
+package multistatus
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges lists the code points drawn two columns wide: East Asian wide
+// and fullwidth characters, and emoji presentation characters.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},
+	{0x231a, 0x231b},
+	{0x2329, 0x232a},
+	{0x23e9, 0x23ec},
+	{0x23f0, 0x23f0},
+	{0x23f3, 0x23f3},
+	{0x25fd, 0x25fe},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267f, 0x267f},
+	{0x2693, 0x2693},
+	{0x26a1, 0x26a1},
+	{0x26aa, 0x26ab},
+	{0x26bd, 0x26be},
+	{0x26c4, 0x26c5},
+	{0x26ce, 0x26ce},
+	{0x26d4, 0x26d4},
+	{0x26ea, 0x26ea},
+	{0x26f2, 0x26f3},
+	{0x26f5, 0x26f5},
+	{0x26fa, 0x26fa},
+	{0x26fd, 0x26fd},
+	{0x2705, 0x2705},
+	{0x270a, 0x270b},
+	{0x2728, 0x2728},
+	{0x274c, 0x274c},
+	{0x274e, 0x274e},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27b0, 0x27b0},
+	{0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c},
+	{0x2b50, 0x2b50},
+	{0x2b55, 0x2b55},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xa960, 0xa97f},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x1f004, 0x1f004},
+	{0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e},
+	{0x1f191, 0x1f19a},
+	{0x1f200, 0x1f251},
+	{0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff},
+	{0x1f7e0, 0x1f7eb},
+	{0x1f900, 0x1f9ff},
+	{0x1fa70, 0x1faff},
+	{0x20000, 0x3fffd},
+}
+
+// runeWidth returns the number of terminal columns r occupies
+func runeWidth(r rune) int {
+	if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+		return 0
+	}
+	if r < 0x1100 {
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			return 0
+		}
+		return 1
+	}
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || (r >= 0xfe00 && r <= 0xfe0f) {
+		return 0
+	}
+	for _, rng := range wideRanges {
+		if r < rng[0] {
+			break
+		}
+		if r <= rng[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// escapeLen returns the length of the CSI escape sequence at the start of s,
+// or 0 if s doesn't start with one.
+func escapeLen(s string) int {
+	if !strings.HasPrefix(s, csi) {
+		return 0
+	}
+	j := 2
+	for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+		j++
+	}
+	if j < len(s) {
+		j++
+	}
+	return j
+}
+
+// stringWidth returns the number of terminal columns s occupies, ignoring
+// escape sequences.
+func stringWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		n += runeWidth(r)
+		i += size
+	}
+	return n
+}
+
+// truncate shortens s to at most width columns, skipping over escape
+// sequences, and resets attributes if any were cut short.
+func truncate(s string, width int) string {
+	var buf strings.Builder
+	n, escaped := 0, false
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			buf.WriteString(s[i : i+l])
+			escaped = true
+			i += l
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := runeWidth(r)
+		if n+rw > width {
+			if escaped {
+				buf.WriteString(sgrReset)
+			}
+			return buf.String()
+		}
+		buf.WriteRune(r)
+		n += rw
+		i += size
+	}
+	return buf.String()
+}
+
+// truncateMiddle shortens plain text s to at most width columns by replacing
+// its middle with an ellipsis.
+func truncateMiddle(s string, width int) string {
+	if stringWidth(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return truncate("…", width)
+	}
+	rs := []rune(s)
+	head, tail := "", ""
+	hw, tw := 0, 0
+	budget := width - 1
+	for i, j := 0, len(rs)-1; i <= j; {
+		if hw <= tw {
+			rw := runeWidth(rs[i])
+			if hw+tw+rw > budget {
+				break
+			}
+			head += string(rs[i])
+			hw += rw
+			i++
+		} else {
+			rw := runeWidth(rs[j])
+			if hw+tw+rw > budget {
+				break
+			}
+			tail = string(rs[j]) + tail
+			tw += rw
+			j--
+		}
+	}
+	return head + "…" + tail
+}
+
+// wrapText breaks plain text s into lines of at most width columns at
+// spaces, splitting words too long to fit on a line of their own. A width of
+// 0 or less leaves s whole.
+func wrapText(s string, width int) []string {
+	if width <= 0 || stringWidth(s) <= width {
+		return []string{s}
+	}
+	var lines []string
+	var line strings.Builder
+	lw := 0
+	for _, word := range strings.Fields(s) {
+		ww := stringWidth(word)
+		if lw > 0 && lw+1+ww <= width {
+			line.WriteByte(' ')
+			line.WriteString(word)
+			lw += 1 + ww
+			continue
+		}
+		if lw > 0 {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		for ww > width {
+			head := truncate(word, width)
+			if head == "" {
+				_, size := utf8.DecodeRuneInString(word)
+				head = word[:size]
+			}
+			lines = append(lines, head)
+			word = word[len(head):]
+			ww = stringWidth(word)
+		}
+		line.WriteString(word)
+		lw = ww
+	}
+	if lw > 0 || len(lines) == 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}